@@ -36,9 +36,15 @@ var ErrWrongFractionRange = fmt.Errorf("Fraction is not in range (0.0 - 1.0]")
 // ErrWrongTolerance ...
 var ErrWrongTolerance = fmt.Errorf("Tolerance must be non-negative")
 
+// ErrNotPositiveTolerance ...
+var ErrNotPositiveTolerance = fmt.Errorf("Tolerance must be positive")
+
 // ErrWrongExponent ...
 var ErrWrongExponent = fmt.Errorf("Exponent out of bounds")
 
+// ErrWrongMakeValidMethod ...
+var ErrWrongMakeValidMethod = fmt.Errorf("MakeValid method must be linework or structure")
+
 // ErrComputeOffsetZero ...
 var ErrComputeOffsetZero = fmt.Errorf("Cannot compute offset from zero-length line segment")
 
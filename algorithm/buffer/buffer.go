@@ -2,7 +2,7 @@
 package buffer
 
 import (
-	"log"
+	"math"
 
 	"github.com/spatial-go/geoos/algorithm/calc"
 	"github.com/spatial-go/geoos/algorithm/matrix"
@@ -16,28 +16,35 @@ type ComputerBuffer struct {
 	param    *CurveParameters
 }
 
-// Buffer Computes the set of raw offset curves for the buffer.
-// Each offset curve has an attached  indicating
-// its left and right location.
+// Buffer Computes the buffer of a geometry, the raw offset curves of its components
+// are noded and the faces of positive depth of their planar graph are returned as
+// a PolygonMatrix, or a Collection of PolygonMatrix, nil if the buffer is empty.
 func Buffer(geom matrix.Steric, distance float64, quadrantSegments int) matrix.Steric {
+	param := DefaultCurveParameters()
+	param.QuadrantSegments = quadrantSegments
+	return BufferWithParams(geom, distance, param)
+}
+
+// BufferWithParams Computes the buffer with the parameters of the curves:
+// the end cap style of lines, the join style and mitre limit of corners,
+// and single-sided buffers of lines, on the left side for a positive distance and on the right side for a negative one.
+// Nil parameters are the default ones, zero values of the parameters are replaced by their defaults.
+func BufferWithParams(geom matrix.Steric, distance float64, param *CurveParameters) matrix.Steric {
+	if geom == nil {
+		return nil
+	}
 	eb := ComputerBuffer{}
-	eb.param = DefaultCurveParameters()
-	eb.param.QuadrantSegments = quadrantSegments
+	eb.param = param.withDefaults()
 	eb.distance = distance
 	eb.CurveBuilder = &CurveBuilder{
 		Curve: CurveWithParameters(eb.param, eb.distance),
 	}
 
 	eb.Add(geom)
-	bufferSeg := eb.CurveBuilder.Curves
-	if len(bufferSeg) <= 0 {
+	if len(eb.CurveBuilder.Curves) <= 0 {
 		return nil
 	}
-	poly := matrix.PolygonMatrix{}
-	for _, v := range bufferSeg {
-		poly = append(poly, v.Line)
-	}
-	return poly
+	return eb.union()
 }
 
 // Add Add a geometry to the graph.
@@ -58,12 +65,13 @@ func (eb *ComputerBuffer) Add(geom matrix.Steric) {
 		eb.addLineString(st)
 	case matrix.PolygonMatrix:
 		eb.addPolygon(st)
+	case matrix.MultiPolygonMatrix:
+		for _, v := range st {
+			eb.addPolygon(v)
+		}
 	case matrix.Collection:
-		// TODO add support collection
-		for i, v := range st {
-			if i == 0 {
-				eb.Add(v)
-			}
+		for _, v := range st {
+			eb.Add(v)
 		}
 	}
 }
@@ -82,13 +90,13 @@ func (eb *ComputerBuffer) addLineString(line matrix.LineMatrix) {
 	if eb.isLineOffsetEmpty(eb.distance) {
 		return
 	}
-
-	if matrix.Matrix(line[0]).Equals(matrix.Matrix((line[len(line)-1]))) && eb.param.IsSingleSided {
-		eb.LineCurve(matrix.LineMatrix(line), eb.distance, calc.ImExterior, calc.ImInterior)
+	line = removeRepeatedPoints(line)
+	// a closed line is buffered on both sides as a ring, so its buffer may have a hole.
+	if len(line) > calc.MinRingSize && matrix.Matrix(line[0]).Equals(matrix.Matrix(line[len(line)-1])) && !eb.param.IsSingleSided {
+		eb.AddRingBothSides(line, eb.distance)
 	} else {
-		eb.LineCurve(matrix.LineMatrix(line), eb.distance, calc.ImExterior, calc.ImInterior)
+		eb.LineCurve(line, eb.distance, calc.ImExterior, calc.ImInterior)
 	}
-
 }
 
 // isLineOffsetEmpty Tests whether the offset curve for line or point geometries
@@ -108,6 +116,7 @@ func (eb *ComputerBuffer) isLineOffsetEmpty(distance float64) bool {
 	}
 	return false
 }
+
 func (eb *ComputerBuffer) addPolygon(p matrix.PolygonMatrix) {
 	offsetDistance := eb.distance
 
@@ -117,11 +126,15 @@ func (eb *ComputerBuffer) addPolygon(p matrix.PolygonMatrix) {
 		offsetSide = calc.SideRight
 	}
 
-	shell := p[0]
+	shell := removeRepeatedPoints(p[0])
 
 	if eb.distance <= 0.0 && len(shell) < 3 {
 		return
 	}
+	// a shell eroded completely by a negative buffer has no curve, nor have its holes.
+	if eb.distance < 0.0 && isErodedCompletely(shell, eb.distance) {
+		return
+	}
 	eb.addRingSide(
 		shell,
 		offsetDistance,
@@ -129,24 +142,26 @@ func (eb *ComputerBuffer) addPolygon(p matrix.PolygonMatrix) {
 		calc.ImExterior,
 		calc.ImInterior)
 
-	if offsetSide == calc.SideLeft {
-		offsetSide = calc.SideRight
-	}
+	// Holes are offset on the opposite side of the shell.
+	holeSide := calc.SideRight
 	if offsetSide == calc.SideRight {
-		offsetSide = calc.SideLeft
+		holeSide = calc.SideLeft
 	}
 
 	for i := 1; i < len(p); i++ {
+		hole := removeRepeatedPoints(p[i])
 
-		hole := p[i]
-
+		// a hole filled completely by a positive buffer has no curve.
+		if eb.distance > 0.0 && isErodedCompletely(hole, -eb.distance) {
+			continue
+		}
 		// Holes are topologically labelled opposite to the shell, since
 		// the interior of the polygon lies on their opposite side
 		// (on the left, if the hole is oriented CCW)
 		eb.addRingSide(
 			hole,
 			offsetDistance,
-			offsetSide,
+			holeSide,
 			calc.ImInterior,
 			calc.ImExterior)
 	}
@@ -177,9 +192,9 @@ func (eb *ComputerBuffer) addRingSide(ring matrix.LineMatrix, offsetDistance flo
 	leftLoc := cwLeftLoc
 	rightLoc := cwRightLoc
 
-	// add test ccw
-	isCCW := measure.IsCCW(matrix.LineMatrix(ring))
-	if len(ring) >= calc.MinRingSize && !isCCW {
+	// measure.IsCCW is true for rings oriented CW, the locations are interchanged for CCW rings.
+	isCW := measure.IsCCW(matrix.LineMatrix(ring))
+	if len(ring) >= calc.MinRingSize && !isCW {
 		leftLoc = cwRightLoc
 		rightLoc = cwLeftLoc
 		if side == calc.SideLeft {
@@ -188,7 +203,35 @@ func (eb *ComputerBuffer) addRingSide(ring matrix.LineMatrix, offsetDistance flo
 			side = calc.SideLeft
 		}
 	}
-	log.Println("start ring curve")
 	eb.RingCurve(matrix.LineMatrix(ring), offsetDistance, side, leftLoc, rightLoc)
+}
 
+// isErodedCompletely Tests whether a ring is eroded completely by a negative buffer distance,
+// its envelope is narrower than twice the distance.
+func isErodedCompletely(ring matrix.LineMatrix, bufferDistance float64) bool {
+	// degenerate ring has no area
+	if len(ring) < 4 {
+		return bufferDistance < 0
+	}
+	if bufferDistance >= 0 {
+		return false
+	}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, v := range ring {
+		minX, maxX = math.Min(minX, v[0]), math.Max(maxX, v[0])
+		minY, maxY = math.Min(minY, v[1]), math.Max(maxY, v[1])
+	}
+	return 2*math.Abs(bufferDistance) > math.Min(maxX-minX, maxY-minY)
+}
+
+// removeRepeatedPoints returns the line without consecutive equal points.
+func removeRepeatedPoints(line matrix.LineMatrix) matrix.LineMatrix {
+	result := make(matrix.LineMatrix, 0, len(line))
+	for _, v := range line {
+		if len(result) > 0 && matrix.Matrix(result[len(result)-1]).Equals(matrix.Matrix(v)) {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
 }
@@ -0,0 +1,137 @@
+package buffer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+// nodedSegment a segment of a curve and the nodes where other segments intersect it.
+type nodedSegment struct {
+	p0, p1 matrix.Matrix
+	nodes  []matrix.Matrix
+}
+
+// splitPoints returns the end points and the nodes of the segment, ordered from p0 to p1.
+func (s *nodedSegment) splitPoints() []matrix.Matrix {
+	points := make([]matrix.Matrix, 0, len(s.nodes)+2)
+	points = append(points, s.p0)
+	nodes := append([]matrix.Matrix{}, s.nodes...)
+	sort.Slice(nodes, func(i, j int) bool {
+		return squareDistance(s.p0, nodes[i]) < squareDistance(s.p0, nodes[j])
+	})
+	points = append(points, nodes...)
+	return append(points, s.p1)
+}
+
+// computeNodes computes the intersections of the segments,
+// sweeping them by x so only the segments overlapping in x are tested.
+func computeNodes(segments []*nodedSegment) {
+	sorted := append([]*nodedSegment{}, segments...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return math.Min(sorted[i].p0[0], sorted[i].p1[0]) < math.Min(sorted[j].p0[0], sorted[j].p1[0])
+	})
+	active := []*nodedSegment{}
+	for _, s := range sorted {
+		minX := math.Min(s.p0[0], s.p1[0])
+		kept := active[:0]
+		for _, a := range active {
+			if math.Max(a.p0[0], a.p1[0]) >= minX {
+				kept = append(kept, a)
+				intersectSegments(a, s)
+			}
+		}
+		active = append(kept, s)
+	}
+}
+
+// intersectSegments adds the intersection of two segments to the nodes of both,
+// the end points of a segment touching the other are nodes of the other.
+func intersectSegments(a, b *nodedSegment) {
+	if math.Max(a.p0[1], a.p1[1]) < math.Min(b.p0[1], b.p1[1]) ||
+		math.Max(b.p0[1], b.p1[1]) < math.Min(a.p0[1], a.p1[1]) {
+		return
+	}
+	o1, o2 := OrientationIndex(a.p0, a.p1, b.p0), OrientationIndex(a.p0, a.p1, b.p1)
+	o3, o4 := OrientationIndex(b.p0, b.p1, a.p0), OrientationIndex(b.p0, b.p1, a.p1)
+	if o1*o2 > 0 || o3*o4 > 0 {
+		return
+	}
+	if o1 == 0 && inSegmentEnvelope(b.p0, a) {
+		a.nodes = append(a.nodes, b.p0)
+	}
+	if o2 == 0 && inSegmentEnvelope(b.p1, a) {
+		a.nodes = append(a.nodes, b.p1)
+	}
+	if o3 == 0 && inSegmentEnvelope(a.p0, b) {
+		b.nodes = append(b.nodes, a.p0)
+	}
+	if o4 == 0 && inSegmentEnvelope(a.p1, b) {
+		b.nodes = append(b.nodes, a.p1)
+	}
+	if o1 == 0 || o2 == 0 || o3 == 0 || o4 == 0 {
+		return
+	}
+	// a proper intersection, the point is kept in the envelopes of both segments.
+	p, ok := lineIntersection(a.p0, a.p1, b.p0, b.p1)
+	if !ok {
+		return
+	}
+	for _, s := range []*nodedSegment{a, b} {
+		p[0] = math.Max(math.Min(s.p0[0], s.p1[0]), math.Min(p[0], math.Max(s.p0[0], s.p1[0])))
+		p[1] = math.Max(math.Min(s.p0[1], s.p1[1]), math.Min(p[1], math.Max(s.p0[1], s.p1[1])))
+	}
+	a.nodes = append(a.nodes, p)
+	b.nodes = append(b.nodes, p)
+}
+
+// inSegmentEnvelope returns true if the point is in the envelope of the segment.
+func inSegmentEnvelope(p matrix.Matrix, s *nodedSegment) bool {
+	return p[0] >= math.Min(s.p0[0], s.p1[0]) && p[0] <= math.Max(s.p0[0], s.p1[0]) &&
+		p[1] >= math.Min(s.p0[1], s.p1[1]) && p[1] <= math.Max(s.p0[1], s.p1[1])
+}
+
+func squareDistance(p, q matrix.Matrix) float64 {
+	dx, dy := p[0]-q[0], p[1]-q[1]
+	return dx*dx + dy*dy
+}
+
+// nodeSnapper merges the points closer than a tolerance into the same node of a graph,
+// the points are hashed in a grid of cells of the tolerance size.
+type nodeSnapper struct {
+	tolerance float64
+	cells     map[[2]int64][]int
+}
+
+// newNodeSnapper returns a snapper of the points of the segments, with a tolerance relative to their magnitude.
+func newNodeSnapper(segments []*nodedSegment) *nodeSnapper {
+	magnitude := 0.0
+	for _, s := range segments {
+		magnitude = math.Max(magnitude, math.Max(math.Max(math.Abs(s.p0[0]), math.Abs(s.p0[1])),
+			math.Max(math.Abs(s.p1[0]), math.Abs(s.p1[1]))))
+	}
+	tolerance := magnitude * snapFactor
+	if tolerance == 0 {
+		tolerance = snapFactor
+	}
+	return &nodeSnapper{tolerance: tolerance, cells: map[[2]int64][]int{}}
+}
+
+// node returns the node of the graph at the point, adding it if no node is within the tolerance.
+func (n *nodeSnapper) node(p matrix.Matrix, g *bufferGraph) int {
+	cx, cy := int64(math.Floor(p[0]/n.tolerance)), int64(math.Floor(p[1]/n.tolerance))
+	for x := cx - 1; x <= cx+1; x++ {
+		for y := cy - 1; y <= cy+1; y++ {
+			for _, i := range n.cells[[2]int64{x, y}] {
+				if q := g.nodes[i]; math.Abs(p[0]-q[0]) <= n.tolerance && math.Abs(p[1]-q[1]) <= n.tolerance {
+					return i
+				}
+			}
+		}
+	}
+	g.nodes = append(g.nodes, matrix.Matrix{p[0], p[1]})
+	cell := [2]int64{cx, cy}
+	n.cells[cell] = append(n.cells[cell], len(g.nodes)-1)
+	return len(g.nodes) - 1
+}
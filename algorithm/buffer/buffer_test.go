@@ -1,10 +1,13 @@
 package buffer
 
 import (
+	"math"
 	"testing"
 
 	"github.com/spatial-go/geoos"
+	"github.com/spatial-go/geoos/algorithm/calc"
 	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/measure"
 )
 
 func TestBuffer(t *testing.T) {
@@ -80,11 +83,17 @@ func TestBuffer(t *testing.T) {
 			geom:     matrix.Collection{matrix.Matrix{100, 100}, matrix.Matrix{200, 200}},
 			distance: 50,
 			quadsegs: 4,
-		}, want: matrix.PolygonMatrix{
-			{{150, 100}, {146.19397662556435, 80.86582838174553}, {135.3553390593274, 64.64466094067265}, {119.13417161825454, 53.80602337443568}, {100.00000000000009, 50}, {
-				80.86582838174562, 53.806023374435625}, {64.64466094067271, 64.64466094067254}, {53.80602337443571, 80.86582838174539}, {50, 99.99999999999984}, {
-				53.80602337443559, 119.13417161825431}, {64.64466094067248, 135.35533905932724}, {80.86582838174532, 146.19397662556426}, {99.99999999999977, 150}, {
-				119.13417161825426, 146.19397662556443}, {135.35533905932718, 135.35533905932758}, {146.19397662556423, 119.13417161825477}, {150, 100}},
+		}, want: matrix.Collection{
+			matrix.PolygonMatrix{{{146.19397662556435, 80.86582838174552}, {150, 100}, {146.19397662556432, 119.13417161825453}, {135.35533905932738, 135.35533905932738},
+				{119.1341716182545, 146.19397662556435}, {100, 150}, {80.86582838174549, 146.19397662556432}, {64.64466094067262, 135.35533905932738},
+				{53.80602337443566, 119.13417161825448}, {50, 100}, {53.80602337443566, 80.8658283817455}, {64.64466094067262, 64.64466094067262},
+				{80.86582838174552, 53.80602337443566}, {100, 50}, {119.1341716182545, 53.80602337443566}, {135.35533905932738, 64.64466094067262},
+				{146.19397662556435, 80.86582838174552}}},
+			matrix.PolygonMatrix{{{246.19397662556435, 180.8658283817455}, {250, 200}, {246.19397662556432, 219.13417161825453}, {235.35533905932738, 235.35533905932738},
+				{219.1341716182545, 246.19397662556435}, {200, 250}, {180.86582838174547, 246.19397662556432}, {164.64466094067262, 235.35533905932738},
+				{153.80602337443565, 219.13417161825447}, {150, 200}, {153.80602337443565, 180.8658283817455}, {164.64466094067262, 164.64466094067262},
+				{180.8658283817455, 153.80602337443565}, {200, 150}, {219.1341716182545, 153.80602337443565}, {235.35533905932738, 164.64466094067262},
+				{246.19397662556435, 180.8658283817455}}},
 		},
 		},
 
@@ -145,3 +154,201 @@ func TestBuffer(t *testing.T) {
 		})
 	}
 }
+
+func TestCurveParameters_withDefaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		param *CurveParameters
+		want  *CurveParameters
+	}{
+		{name: "nil", param: nil, want: DefaultCurveParameters()},
+		{name: "zero", param: &CurveParameters{}, want: DefaultCurveParameters()},
+		{name: "mitre", param: &CurveParameters{JoinStyle: calc.JoinMitre, MitreLimit: 2, IsSingleSided: true},
+			want: &CurveParameters{calc.QuadrantSegments, calc.CapRound, calc.JoinMitre, 2, calc.SimplifyFactor, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before CurveParameters
+			if tt.param != nil {
+				before = *tt.param
+			}
+			if got := tt.param.withDefaults(); *got != *tt.want {
+				t.Errorf("withDefaults() = %v, want %v", got, tt.want)
+			}
+			if tt.param != nil && *tt.param != before {
+				t.Errorf("withDefaults() changed the parameters to %v", tt.param)
+			}
+		})
+	}
+}
+
+func TestBufferWithParams(t *testing.T) {
+	line := matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}}
+	square := matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	tests := []struct {
+		name     string
+		geom     matrix.Steric
+		distance float64
+		param    *CurveParameters
+		want     matrix.Steric
+	}{
+		{name: "flat cap bevel join", geom: line, distance: 1,
+			param: &CurveParameters{EndCapStyle: calc.CapFlat, JoinStyle: calc.JoinBevel},
+			want:  matrix.PolygonMatrix{{{9, 1}, {9, 10}, {11, 10}, {11, 0}, {10, -1}, {0, -1}, {0, 1}, {9, 1}}}},
+		{name: "square cap mitre join", geom: line, distance: 1,
+			param: &CurveParameters{EndCapStyle: calc.CapSquare, JoinStyle: calc.JoinMitre},
+			want:  matrix.PolygonMatrix{{{9, 1}, {9, 10}, {9, 11}, {11, 11}, {11, -1}, {0, -1}, {-1, -1}, {-1, 1}, {9, 1}}}},
+		{name: "single-sided left", geom: line, distance: 1,
+			param: &CurveParameters{IsSingleSided: true},
+			want:  matrix.PolygonMatrix{{{10, 10}, {10, 0}, {0, 0}, {0, 1}, {9, 1}, {9, 10}, {10, 10}}}},
+		{name: "single-sided right", geom: line, distance: -1,
+			param: &CurveParameters{IsSingleSided: true, JoinStyle: calc.JoinMitre},
+			want:  matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {11, 10}, {11, -1}, {0, -1}, {0, 0}}}},
+		{name: "negative line", geom: line, distance: -1, param: nil, want: nil},
+		{name: "mitre polygon", geom: square, distance: 1,
+			param: &CurveParameters{JoinStyle: calc.JoinMitre},
+			want:  matrix.PolygonMatrix{{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}, {-1, -1}}}},
+		{name: "mitre limit", geom: square, distance: 1,
+			param: &CurveParameters{JoinStyle: calc.JoinMitre, MitreLimit: 1},
+			want: matrix.PolygonMatrix{{{-0.9142, -0.5}, {-0.5, -0.9142}, {10.5, -0.9142}, {10.9142, -0.5}, {10.9142, 10.5},
+				{10.5, 10.9142}, {-0.5, 10.9142}, {-0.9142, 10.5}, {-0.9142, -0.5}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BufferWithParams(tt.geom, tt.distance, tt.param)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("BufferWithParams() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || !got.EqualsExact(tt.want, 0.01) {
+				t.Errorf("BufferWithParams() = %v,\n want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuffer_Union(t *testing.T) {
+	dumbbell := matrix.PolygonMatrix{{{0, 0}, {4, 0}, {4, 1.5}, {6, 1.5}, {6, 0}, {10, 0}, {10, 4}, {6, 4}, {6, 2.5}, {4, 2.5}, {4, 4}, {0, 4}, {0, 0}}}
+	holed := matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{3, 3}, {3, 7}, {7, 7}, {7, 3}, {3, 3}}}
+	tests := []struct {
+		name     string
+		geom     matrix.Steric
+		distance float64
+		// rings the number of rings of each polygon.
+		rings []int
+		area  float64
+	}{
+		{"self-approaching line", matrix.LineMatrix{{0, 0}, {10, 0}, {10, 1}, {0, 1}}, 1, []int{1}, 35.07},
+		{"closed line", matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, 1, []int{2}, 79.12},
+		{"crossing line", matrix.LineMatrix{{0, 0}, {10, 10}, {10, 0}, {0, 10}}, 1, []int{2}, 73.20},
+		{"overlapping points", matrix.Collection{matrix.Matrix{0, 0}, matrix.Matrix{1, 0}}, 1, []int{1}, 5.03},
+		{"disjoint points", matrix.Collection{matrix.Matrix{0, 0}, matrix.Matrix{10, 0}}, 1, []int{1, 1}, 6.24},
+		{"negative split", dumbbell, -1, []int{1, 1}, 8.09},
+		{"negative", dumbbell, -0.2, []int{1}, 27.40},
+		{"eroded", dumbbell, -3, nil, 0},
+		{"hole", holed, 1, []int{2}, 139.12},
+		{"filled hole", holed, 3, []int{1}, 248.09},
+		{"negative hole", holed, -1, []int{2}, 28.88},
+		{"touching polygons", matrix.Collection{
+			matrix.PolygonMatrix{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}},
+			matrix.PolygonMatrix{{{5, 0}, {10, 0}, {10, 5}, {5, 5}, {5, 0}}}}, 0, []int{1}, 50},
+		{"collection", matrix.Collection{matrix.Matrix{20, 20}, matrix.LineMatrix{{0, 0}, {20, 0}},
+			matrix.PolygonMatrix{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}}}, 1, []int{1, 1}, 81.02},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Buffer(tt.geom, tt.distance, 8)
+			polys := []matrix.PolygonMatrix{}
+			switch g := got.(type) {
+			case matrix.PolygonMatrix:
+				polys = append(polys, g)
+			case matrix.Collection:
+				for _, v := range g {
+					polys = append(polys, v.(matrix.PolygonMatrix))
+				}
+			}
+			rings, area := []int{}, 0.0
+			for _, poly := range polys {
+				rings = append(rings, len(poly))
+				area += measure.AreaOfPolygon(poly)
+				if !isSimple(poly) {
+					t.Errorf("Buffer() = %v is not simple", poly)
+				}
+			}
+			if len(tt.rings) == 0 && got != nil {
+				t.Errorf("Buffer() = %v, want nil", got)
+			}
+			if len(rings) != len(tt.rings) || math.Abs(area-tt.area) > 0.01 {
+				t.Errorf("Buffer() rings = %v area = %v, want %v %v", rings, area, tt.rings, tt.area)
+			}
+			for i := range tt.rings {
+				if i < len(rings) && rings[i] != tt.rings[i] {
+					t.Errorf("Buffer() rings = %v, want %v", rings, tt.rings)
+				}
+			}
+		})
+	}
+}
+
+// isSimple returns true if no segments of the rings of the polygon cross.
+func isSimple(poly matrix.PolygonMatrix) bool {
+	segments := []*matrix.LineSegment{}
+	for _, ring := range poly {
+		for i := 1; i < len(ring); i++ {
+			segments = append(segments, &matrix.LineSegment{P0: ring[i-1], P1: ring[i]})
+		}
+	}
+	for i, s := range segments {
+		for _, o := range segments[i+1:] {
+			if OrientationIndex(s.P0, s.P1, o.P0)*OrientationIndex(s.P0, s.P1, o.P1) < 0 &&
+				OrientationIndex(o.P0, o.P1, s.P0)*OrientationIndex(o.P0, o.P1, s.P1) < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestOffsetCurve(t *testing.T) {
+	corner := matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}}
+	mitre := &CurveParameters{JoinStyle: calc.JoinMitre}
+	tests := []struct {
+		name     string
+		line     matrix.LineMatrix
+		distance float64
+		param    *CurveParameters
+		want     matrix.LineMatrix
+	}{
+		{name: "left", line: matrix.LineMatrix{{0, 0}, {10, 0}}, distance: 1, want: matrix.LineMatrix{{0, 1}, {10, 1}}},
+		{name: "right", line: matrix.LineMatrix{{0, 0}, {10, 0}}, distance: -1, want: matrix.LineMatrix{{0, -1}, {10, -1}}},
+		{name: "zero", line: corner, distance: 0, want: corner},
+		{name: "inside corner", line: corner, distance: 1, want: matrix.LineMatrix{{0, 1}, {9, 1}, {9, 10}}},
+		{name: "mitre corner", line: corner, distance: -1, param: mitre, want: matrix.LineMatrix{{0, -1}, {11, -1}, {11, 10}}},
+		{name: "bevel corner", line: corner, distance: -1, param: &CurveParameters{JoinStyle: calc.JoinBevel},
+			want: matrix.LineMatrix{{0, -1}, {10, -1}, {11, 0}, {11, 10}}},
+		{name: "tight bend", line: matrix.LineMatrix{{0, 0}, {10, 0}, {10, 0.5}, {20, 0.5}}, distance: 3, param: mitre,
+			want: matrix.LineMatrix{{0, 3}, {7, 3}, {7, 3.5}, {20, 3.5}}},
+		{name: "acute bend", line: matrix.LineMatrix{{0, 0}, {10, 0}, {5, 1}}, distance: 1, param: mitre,
+			want: matrix.LineMatrix{{0, 1}, {4.0001, 1}}},
+		{name: "closed", line: matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, distance: 2,
+			want: matrix.LineMatrix{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+		{name: "collapsed", line: matrix.LineMatrix{{0, 0}, {10, 0}, {10, 1}, {0, 1}}, distance: 1, want: nil},
+		{name: "point", line: matrix.LineMatrix{{1, 1}, {1, 1}}, distance: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OffsetCurve(tt.line, tt.distance, tt.param)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("OffsetCurve() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || !got.EqualsExact(tt.want, 0.001) {
+				t.Errorf("OffsetCurve() = %v,\n want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package buffer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/relate"
+)
+
+// snapFactor the tolerance of the nodes of the curves relative to the magnitude of their coordinates.
+const snapFactor = 1e-12
+
+// union nodes the raw offset curves, builds their planar graph and keeps the faces of positive depth.
+// The curves are oriented with the interior of the buffer on their right,
+// so the depth of a face is the number of curves it is inside of, less the number of holes.
+// Returns a PolygonMatrix, a Collection of PolygonMatrix, or nil if no face has a positive depth.
+func (eb *ComputerBuffer) union() matrix.Steric {
+	curves := make([]matrix.LineMatrix, 0, len(eb.Curves))
+	for _, c := range eb.Curves {
+		if len(c.Line) < 3 {
+			continue
+		}
+		curves = append(curves, orientCurve(c.Line, c.rightLoc))
+	}
+	if len(curves) == 0 {
+		return nil
+	}
+	g := newBufferGraph(curves)
+	if g.isSimpleCycle(len(curves)) {
+		// a single curve without self-intersections is the buffer if it encloses the interior.
+		if signedArea(curves[0]) < 0 {
+			return matrix.PolygonMatrix{eb.Curves[0].Line}
+		}
+		return nil
+	}
+	g.computeDepths()
+	polys := g.polygons()
+	switch len(polys) {
+	case 0:
+		return nil
+	case 1:
+		return polys[0]
+	}
+	coll := matrix.Collection{}
+	for _, v := range polys {
+		coll = append(coll, v)
+	}
+	return coll
+}
+
+// orientCurve returns the closed curve with the interior on its right.
+func orientCurve(line matrix.LineMatrix, rightLoc int) matrix.LineMatrix {
+	curve := make(matrix.LineMatrix, 0, len(line)+1)
+	if rightLoc == calc.ImInterior {
+		curve = append(curve, line...)
+	} else {
+		for i := len(line) - 1; i >= 0; i-- {
+			curve = append(curve, line[i])
+		}
+	}
+	if !matrix.Matrix(curve[0]).Equals(matrix.Matrix(curve[len(curve)-1])) {
+		curve = append(curve, curve[0])
+	}
+	return curve
+}
+
+// signedArea returns the area of a closed ring, positive if it is oriented CCW.
+func signedArea(ring matrix.LineMatrix) float64 {
+	sum := 0.0
+	for i := 1; i < len(ring); i++ {
+		sum += (ring[i-1][0] - ring[0][0]) * (ring[i][1] - ring[0][1])
+		sum -= (ring[i][0] - ring[0][0]) * (ring[i-1][1] - ring[0][1])
+	}
+	return sum / 2
+}
+
+// halfEdge a directed edge of the buffer graph.
+type halfEdge struct {
+	orig, dest int
+	// delta the number of curves traversing the edge in its direction, less the opposite ones,
+	// the depth of the face on its right is the depth of the face on its left plus delta.
+	delta int
+	angle float64
+	face  int
+	// pos the position of the edge in the edges leaving its origin.
+	pos int
+}
+
+// bufferGraph the planar graph of the noded curves, the half-edges 2i and 2i+1 are twins.
+type bufferGraph struct {
+	nodes []matrix.Matrix
+	edges []*halfEdge
+	// out the half-edges leaving each node, sorted CCW by angle.
+	out [][]int
+	// faces the half-edges of each face, which is on their left.
+	faces [][]int
+	depth []int
+	// componentOf the connected component of each node.
+	componentOf []int
+}
+
+// newBufferGraph nodes the curves and builds their graph,
+// coincident edges are merged and edges of no depth delta are dropped.
+func newBufferGraph(curves []matrix.LineMatrix) *bufferGraph {
+	g := &bufferGraph{}
+	type edgeKey [2]int
+	deltas := map[edgeKey]int{}
+	keys := []edgeKey{}
+	g.nodeCurves(curves, func(_, orig, dest int) {
+		key, delta := edgeKey{orig, dest}, 1
+		if dest < orig {
+			key, delta = edgeKey{dest, orig}, -1
+		}
+		if _, ok := deltas[key]; !ok {
+			keys = append(keys, key)
+		}
+		deltas[key] += delta
+	})
+	g.out = make([][]int, len(g.nodes))
+	for _, key := range keys {
+		delta := deltas[key]
+		if delta == 0 {
+			continue
+		}
+		g.addEdge(key[0], key[1], delta)
+		g.addEdge(key[1], key[0], -delta)
+	}
+	g.sortEdges()
+	return g
+}
+
+// nodeCurves nodes the curves, adds their nodes to the graph
+// and visits each noded edge of a curve, from node orig to node dest.
+func (g *bufferGraph) nodeCurves(curves []matrix.LineMatrix, visit func(curve, orig, dest int)) {
+	segments, curveOf := []*nodedSegment{}, []int{}
+	for c, curve := range curves {
+		for i := 1; i < len(curve); i++ {
+			segments = append(segments, &nodedSegment{p0: curve[i-1], p1: curve[i]})
+			curveOf = append(curveOf, c)
+		}
+	}
+	computeNodes(segments)
+
+	snap := newNodeSnapper(segments)
+	for i, seg := range segments {
+		points := seg.splitPoints()
+		prev := snap.node(points[0], g)
+		for _, p := range points[1:] {
+			next := snap.node(p, g)
+			if next == prev {
+				continue
+			}
+			visit(curveOf[i], prev, next)
+			prev = next
+		}
+	}
+}
+
+// sortEdges sorts the half-edges leaving each node CCW by angle.
+func (g *bufferGraph) sortEdges() {
+	for _, out := range g.out {
+		sort.Slice(out, func(i, j int) bool { return g.edges[out[i]].angle < g.edges[out[j]].angle })
+		for i, e := range out {
+			g.edges[e].pos = i
+		}
+	}
+}
+
+func (g *bufferGraph) addEdge(orig, dest, delta int) {
+	p0, p1 := g.nodes[orig], g.nodes[dest]
+	g.out[orig] = append(g.out[orig], len(g.edges))
+	g.edges = append(g.edges, &halfEdge{
+		orig:  orig,
+		dest:  dest,
+		delta: delta,
+		angle: math.Atan2(p1[1]-p0[1], p1[0]-p0[0]),
+		face:  -1,
+	})
+}
+
+// isSimpleCycle returns true if the graph is the cycle of a single curve.
+func (g *bufferGraph) isSimpleCycle(curves int) bool {
+	if curves != 1 || len(g.edges) != 2*len(g.nodes) {
+		return false
+	}
+	for _, out := range g.out {
+		if len(out) != 2 {
+			return false
+		}
+		if delta := g.edges[out[0]].delta; delta != 1 && delta != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// next returns the half-edge following e around the face on its left,
+// the edge leaving the destination of e which is next clockwise from the twin of e.
+func (g *bufferGraph) next(e int) int {
+	return g.clockwise(e ^ 1)
+}
+
+// clockwise returns the half-edge leaving the origin of e which is next clockwise from e.
+func (g *bufferGraph) clockwise(e int) int {
+	out := g.out[g.edges[e].orig]
+	return out[(g.edges[e].pos-1+len(out))%len(out)]
+}
+
+// computeDepths computes the depth of the faces, the outer face of each connected component
+// has the depth of the faces of the other components around it, given by their winding numbers,
+// the depths of the other faces are propagated across the edges.
+func (g *bufferGraph) computeDepths() {
+	g.traceFaces()
+	g.depth = make([]int, len(g.faces))
+	component := g.components()
+	visited := make([]bool, len(g.faces))
+	for c, faces := range component {
+		outer := g.outerFace(faces)
+		g.depth[outer] = g.windingDepth(g.nodes[g.edges[g.faces[outer][0]].orig], c)
+		visited[outer] = true
+		queue := []int{outer}
+		for len(queue) > 0 {
+			f := queue[0]
+			queue = queue[1:]
+			for _, e := range g.faces[f] {
+				right := g.edges[e^1].face
+				if !visited[right] {
+					visited[right] = true
+					g.depth[right] = g.depth[f] + g.edges[e].delta
+					queue = append(queue, right)
+				}
+			}
+		}
+	}
+}
+
+// traceFaces traces the cycles of half-edges around the faces.
+func (g *bufferGraph) traceFaces() {
+	for e := range g.edges {
+		if g.edges[e].face >= 0 {
+			continue
+		}
+		face := len(g.faces)
+		edges := []int{}
+		for f := e; g.edges[f].face < 0; f = g.next(f) {
+			g.edges[f].face = face
+			edges = append(edges, f)
+		}
+		g.faces = append(g.faces, edges)
+	}
+}
+
+// outerFace returns the outer face of a connected component, the face of minimum signed area.
+func (g *bufferGraph) outerFace(faces []int) int {
+	outer, minArea := -1, math.Inf(1)
+	for _, f := range faces {
+		if area := g.faceArea(f); area < minArea {
+			outer, minArea = f, area
+		}
+	}
+	return outer
+}
+
+// components returns the faces of each connected component of the graph, and labels the nodes by component.
+func (g *bufferGraph) components() [][]int {
+	label := make([]int, len(g.nodes))
+	for i := range label {
+		label[i] = -1
+	}
+	count := 0
+	for n := range g.nodes {
+		if label[n] >= 0 || len(g.out[n]) == 0 {
+			continue
+		}
+		label[n] = count
+		stack := []int{n}
+		for len(stack) > 0 {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, e := range g.out[m] {
+				if d := g.edges[e].dest; label[d] < 0 {
+					label[d] = count
+					stack = append(stack, d)
+				}
+			}
+		}
+		count++
+	}
+	components := make([][]int, count)
+	for f, edges := range g.faces {
+		c := label[g.edges[edges[0]].orig]
+		components[c] = append(components[c], f)
+	}
+	g.componentOf = label
+	return components
+}
+
+// faceArea returns the signed area of the cycle of a face, negative for the outer face of a component.
+func (g *bufferGraph) faceArea(f int) float64 {
+	ring := make(matrix.LineMatrix, 0, len(g.faces[f])+1)
+	for _, e := range g.faces[f] {
+		ring = append(ring, g.nodes[g.edges[e].orig])
+	}
+	ring = append(ring, ring[0])
+	return signedArea(ring)
+}
+
+// windingDepth returns the depth at the point given by the edges of the other components than c,
+// the curves enclose the interior clockwise so the depth is the opposite of their winding number.
+func (g *bufferGraph) windingDepth(p matrix.Matrix, c int) int {
+	winding := 0
+	for i := 0; i < len(g.edges); i += 2 {
+		e := g.edges[i]
+		if g.componentOf[e.orig] == c {
+			continue
+		}
+		p0, p1 := g.nodes[e.orig], g.nodes[e.dest]
+		if p0[1] <= p[1] {
+			if p1[1] > p[1] && OrientationIndex(p0, p1, p) == calc.CounterClockWise {
+				winding += e.delta
+			}
+		} else if p1[1] <= p[1] && OrientationIndex(p0, p1, p) == calc.ClockWise {
+			winding -= e.delta
+		}
+	}
+	return -winding
+}
+
+// isBoundary returns true if the half-edge has the interior of the buffer on its left and the exterior on its right.
+func (g *bufferGraph) isBoundary(e int) bool {
+	return g.depth[g.edges[e].face] > 0 && g.depth[g.edges[e^1].face] <= 0
+}
+
+// polygons traces the rings of the boundary edges, with the interior on their left,
+// and returns the CCW shells with the CW holes they contain.
+func (g *bufferGraph) polygons() []matrix.PolygonMatrix {
+	visited := make([]bool, len(g.edges))
+	shells, holes := []matrix.LineMatrix{}, []matrix.LineMatrix{}
+	for start := range g.edges {
+		if visited[start] || !g.isBoundary(start) {
+			continue
+		}
+		walk := []int{}
+		for e := start; !visited[e]; {
+			visited[e] = true
+			walk = append(walk, g.edges[e].orig)
+			next := g.next(e)
+			for !g.isBoundary(next) {
+				next = g.clockwise(next)
+			}
+			e = next
+		}
+		for _, ring := range g.simpleRings(walk) {
+			if area := signedArea(ring); area > 0 {
+				shells = append(shells, ring)
+			} else if area < 0 {
+				holes = append(holes, ring)
+			}
+		}
+	}
+	polys := make([]matrix.PolygonMatrix, len(shells))
+	areas := make([]float64, len(shells))
+	for i, shell := range shells {
+		polys[i] = matrix.PolygonMatrix{shell}
+		areas[i] = signedArea(shell)
+	}
+	for _, hole := range holes {
+		owner := -1
+		for i, shell := range shells {
+			if (owner < 0 || areas[i] < areas[owner]) && ringInside(hole, shell) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			polys[owner] = append(polys[owner], hole)
+		}
+	}
+	return polys
+}
+
+// simpleRings splits the closed walk of nodes into simple rings at its repeated nodes,
+// a shell touching itself is split into a shell and a hole, CCW and CW as the walk keeps the interior on its left.
+func (g *bufferGraph) simpleRings(walk []int) []matrix.LineMatrix {
+	rings := []matrix.LineMatrix{}
+	stack, at := []int{}, map[int]int{}
+	for _, n := range append(walk, walk[0]) {
+		if i, ok := at[n]; ok {
+			ring := make(matrix.LineMatrix, 0, len(stack)-i+1)
+			for _, m := range stack[i:] {
+				ring = append(ring, g.nodes[m])
+				delete(at, m)
+			}
+			if ring = append(ring, g.nodes[n]); len(ring) >= 4 {
+				rings = append(rings, ring)
+			}
+			stack = stack[:i]
+		}
+		at[n] = len(stack)
+		stack = append(stack, n)
+	}
+	return rings
+}
+
+// ringInside returns true if the hole is inside the shell, the rings do not cross.
+func ringInside(hole, shell matrix.LineMatrix) bool {
+	for _, p := range hole {
+		switch relate.LocateInRings(p, shell) {
+		case calc.ImInterior:
+			return true
+		case calc.ImExterior:
+			return false
+		}
+	}
+	// all the vertices are on the shell, a midpoint of an edge decides.
+	for i := 1; i < len(hole); i++ {
+		mid := matrix.Matrix{(hole[i-1][0] + hole[i][0]) / 2, (hole[i-1][1] + hole[i][1]) / 2}
+		if location := relate.LocateInRings(mid, shell); location != calc.ImBoundary {
+			return location == calc.ImInterior
+		}
+	}
+	return false
+}
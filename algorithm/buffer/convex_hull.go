@@ -89,9 +89,10 @@ func (c *ConvexHullComputer) reduce() []matrix.Matrix {
 	for _, v := range polyPts {
 		ls = append(ls, v)
 	}
+	// the points in the interior of the ring are not on the hull, the ones on its boundary are kept.
 	reducedSet := list.New()
 	for _, v := range c.inputPts {
-		if !relate.InLineMatrix(v, ls) {
+		if relate.LocateInRings(v, ls) != calc.ImInterior {
 			reducedSet.PushBack(v)
 		}
 	}
@@ -107,7 +108,7 @@ func (c *ConvexHullComputer) reduce() []matrix.Matrix {
 func (c *ConvexHullComputer) padArray3(pts []matrix.Matrix) []matrix.Matrix {
 	pad := make([]matrix.Matrix, 3)
 	for i := 0; i < len(pad); i++ {
-		if i < len(pad) {
+		if i < len(pts) {
 			pad[i] = pts[i]
 		} else {
 			pad[i] = pts[0]
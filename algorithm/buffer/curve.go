@@ -147,9 +147,7 @@ func (c *Curve) AddLineEndCap(p0, p1 matrix.Matrix, distance float64) {
 		c.Add(offsetR.P1)
 	case calc.CapSquare:
 		// add a square defined by extensions of the offset segment endpoints
-		squareCapSideOffset := matrix.Matrix{}
-		squareCapSideOffset[0] = math.Abs(distance) * math.Cos(angle)
-		squareCapSideOffset[1] = math.Abs(distance) * math.Sin(angle)
+		squareCapSideOffset := matrix.Matrix{math.Abs(distance) * math.Cos(angle), math.Abs(distance) * math.Sin(angle)}
 
 		squareCapLOffset := matrix.Matrix{
 			offsetL.P1[0] + squareCapSideOffset[0],
@@ -405,14 +403,14 @@ func (c *Curve) addMitreJoin(p matrix.Matrix,
 	// This computation is unstable if the offset segments are nearly collinear.
 	// However, this situation should have been eliminated earlier by the check
 	// for whether the offset segment endpoints are almost coincident
-	_, intPt := relate.Intersection(c.offset0.P0, c.offset0.P1, offset1.P0, offset1.P1)
-	if intPt != nil {
+	// the offset segments of an outside turn do not meet, the mitre is at the intersection of their lines.
+	if intPt, ok := lineIntersection(offset0.P0, offset0.P1, offset1.P0, offset1.P1); ok {
 		mitreRatio := 1.0
 		if distance > 0.0 {
-			mitreRatio = measure.PlanarDistance(intPt[0].Matrix, p) / math.Abs(distance)
+			mitreRatio = measure.PlanarDistance(intPt, p) / math.Abs(distance)
 		}
 		if mitreRatio <= c.parameters.MitreLimit {
-			c.Add(intPt[0].Matrix)
+			c.Add(intPt)
 			return
 		}
 	}
@@ -478,3 +476,16 @@ func (c *Curve) addBevelJoin(
 	c.Add(offset0.P1)
 	c.Add(offset1.P0)
 }
+
+// lineIntersection returns the intersection point of the lines through p1-p2 and q1-q2,
+// false if the lines are parallel.
+func lineIntersection(p1, p2, q1, q2 matrix.Matrix) (matrix.Matrix, bool) {
+	dpx, dpy := p2[0]-p1[0], p2[1]-p1[1]
+	dqx, dqy := q2[0]-q1[0], q2[1]-q1[1]
+	denom := dpx*dqy - dpy*dqx
+	if denom == 0 || math.IsNaN(denom) {
+		return nil, false
+	}
+	t := ((q1[0]-p1[0])*dqy - (q1[1]-p1[1])*dqx) / denom
+	return matrix.Matrix{p1[0] + t*dpx, p1[1] + t*dpy}, true
+}
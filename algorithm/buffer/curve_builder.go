@@ -21,7 +21,8 @@ type CurveBuilder struct {
 // fail for closed lines, but will generate superfluous line caps).
 func (c *CurveBuilder) LineCurve(pts matrix.LineMatrix, distance float64,
 	leftLoc, rightLoc int) matrix.LineMatrix {
-	c.distance = distance
+	// the sign of the distance selects the side of single-sided curves, the curve is offset by its absolute value.
+	c.reset(math.Abs(distance))
 
 	if len(pts) <= 1 {
 		c.computePointCurve(pts[0])
@@ -43,21 +44,23 @@ func (c *CurveBuilder) LineCurve(pts matrix.LineMatrix, distance float64,
 // RingCurve This method handles the degenerate cases of single points and lines,
 // as well as valid rings.
 func (c *CurveBuilder) RingCurve(pts matrix.LineMatrix, distance float64, side int, leftLoc, rightLoc int) matrix.LineMatrix {
-
-	c.distance = distance
-
 	if len(pts) <= 2 {
 		return c.LineCurve(pts, distance, leftLoc, rightLoc)
 	}
+	c.reset(distance)
 	if distance == 0.0 {
 		copy := make(matrix.LineMatrix, len(pts))
 		for i := 0; i < len(copy); i++ {
 			copy[i] = matrix.Matrix(pts[i])
 		}
+		c.AddCurve(copy, leftLoc, rightLoc)
 		return copy
 	}
 
 	c.computeRingBufferCurve(pts, side)
+	if c.IsRingCurveInverted(pts, distance) {
+		return nil
+	}
 	lineCoord := c.Curve.Line
 
 	c.AddCurve(lineCoord, leftLoc, rightLoc)
@@ -110,7 +113,7 @@ func (c *CurveBuilder) IsRingCurveInverted(pts matrix.LineMatrix, distance float
 
 	// An inverted curve has no more points than the input ring.
 	// This also eliminates concave inputs (which will produce fillet arcs)
-	if len(c.Curves) > len(pts) {
+	if len(c.Curve.Line) > len(pts) {
 		return false
 	}
 
@@ -131,6 +134,15 @@ func (c *CurveBuilder) IsRingCurveInverted(pts matrix.LineMatrix, distance float
 	return isCurveTooClose
 }
 
+// reset starts a new curve offset at the distance.
+func (c *CurveBuilder) reset(distance float64) {
+	c.distance = distance
+	c.Curve.distance = distance
+	c.Curve.minimimVertexDistance = distance * calc.CurveVertexSnapDistanceFactor
+	c.Curve.hasNarrowConcaveAngle = false
+	c.Curve.Line = nil
+}
+
 func (c *CurveBuilder) computePointCurve(pt matrix.Matrix) {
 	switch c.parameters.EndCapStyle {
 	case calc.CapRound:
@@ -196,13 +208,15 @@ func (c *CurveBuilder) computeSingleSidedBufferCurve(pts matrix.LineMatrix, isRi
 
 		// since we are traversing line in opposite order, offset position is still LEFT
 		c.Curve.initSideSegments(simp2[n2], simp2[n2-1], calc.SideLeft)
-		c.Curve.Add(c.Curve.offset1.P1)
+		c.Curve.Add(c.Curve.offset1.P0)
 		for i := n2 - 2; i >= 0; i-- {
 			c.Curve.addNextSegment(simp2[i], true)
 		}
 	} else {
-		// add original line
-		c.Curve.AddLine(pts)
+		// add original line reversed, so the curve is a ring
+		for i := len(pts) - 1; i >= 0; i-- {
+			c.Curve.AddPt(pts[i])
+		}
 
 		//--------- compute points for left side of line
 		// Simplify the appropriate side of the line before generating
@@ -213,7 +227,7 @@ func (c *CurveBuilder) computeSingleSidedBufferCurve(pts matrix.LineMatrix, isRi
 		//      Coordinate[] simp1 = inputPts;
 		n1 := len(simp1) - 1
 		c.Curve.initSideSegments(simp1[0], simp1[1], calc.SideLeft)
-		c.Curve.Add(c.Curve.offset1.P1)
+		c.Curve.Add(c.Curve.offset1.P0)
 		for i := 2; i <= n1; i++ {
 			c.Curve.addNextSegment(simp1[i], true)
 		}
@@ -222,6 +236,49 @@ func (c *CurveBuilder) computeSingleSidedBufferCurve(pts matrix.LineMatrix, isRi
 	c.Curve.CloseRing()
 }
 
+// OffsetCurve Computes the raw offset curve of a line on its left side, or on its right side,
+// in the direction of the line, a closed line has a closed raw curve.
+// The raw curve may have loops on the tight bends of the line.
+func (c *CurveBuilder) OffsetCurve(pts matrix.LineMatrix, distance float64, isRightSide bool) matrix.LineMatrix {
+	c.reset(distance)
+	if len(pts) > calc.MinRingSize && matrix.Matrix(pts[0]).Equals(matrix.Matrix(pts[len(pts)-1])) {
+		side := calc.SideLeft
+		if isRightSide {
+			side = calc.SideRight
+		}
+		c.computeRingBufferCurve(pts, side)
+		return c.Curve.Line
+	}
+	distTol := c.distance * c.parameters.SimplifyFactor
+	simp := &LineSimplifier{inputLine: pts}
+	if isRightSide {
+		simp2 := simp.Simplify(-distTol)
+		n2 := len(simp2) - 1
+		// since we are traversing line in opposite order, offset position is still LEFT
+		c.Curve.initSideSegments(simp2[n2], simp2[n2-1], calc.SideLeft)
+		c.Curve.Add(c.Curve.offset1.P0)
+		for i := n2 - 2; i >= 0; i-- {
+			c.Curve.addNextSegment(simp2[i], true)
+		}
+	} else {
+		simp1 := simp.Simplify(distTol)
+		n1 := len(simp1) - 1
+		c.Curve.initSideSegments(simp1[0], simp1[1], calc.SideLeft)
+		c.Curve.Add(c.Curve.offset1.P0)
+		for i := 2; i <= n1; i++ {
+			c.Curve.addNextSegment(simp1[i], true)
+		}
+	}
+	c.Curve.Add(c.Curve.offset1.P1)
+	line := c.Curve.Line
+	if isRightSide {
+		for i, j := 0, len(line)-1; i < j; i, j = i+1, j-1 {
+			line[i], line[j] = line[j], line[i]
+		}
+	}
+	return line
+}
+
 func (c *CurveBuilder) computeRingBufferCurve(pts matrix.LineMatrix, side int) {
 	// simplify input line to improve performance
 	distTol := c.distance * c.parameters.SimplifyFactor
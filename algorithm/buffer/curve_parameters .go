@@ -26,3 +26,29 @@ func DefaultCurveParameters() *CurveParameters {
 func (c *CurveParameters) IsEmpty() bool {
 	return c.MitreLimit == 0.0
 }
+
+// withDefaults returns a copy of the parameters, the default parameters if nil,
+// with the zero values replaced by their defaults.
+func (c *CurveParameters) withDefaults() *CurveParameters {
+	param := DefaultCurveParameters()
+	if c == nil {
+		return param
+	}
+	p := *c
+	if p.QuadrantSegments <= 0 {
+		p.QuadrantSegments = param.QuadrantSegments
+	}
+	if p.EndCapStyle == 0 {
+		p.EndCapStyle = param.EndCapStyle
+	}
+	if p.JoinStyle == 0 {
+		p.JoinStyle = param.JoinStyle
+	}
+	if p.MitreLimit <= 0 {
+		p.MitreLimit = param.MitreLimit
+	}
+	if p.SimplifyFactor <= 0 {
+		p.SimplifyFactor = param.SimplifyFactor
+	}
+	return &p
+}
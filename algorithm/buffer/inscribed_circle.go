@@ -0,0 +1,238 @@
+package buffer
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/relate"
+	"github.com/spatial-go/geoos/index/strtree"
+)
+
+// MaximumInscribedCircle Computes the largest circle contained in a polygonal geometry,
+// its centre is the pole of inaccessibility of the polygons, the interior point farthest from their boundary.
+// The centre is searched by the polylabel algorithm, a branch and bound over square cells
+// which are split until the distance of the centre is within the tolerance of the maximum.
+// Returns the centre and the radius of the circle.
+func MaximumInscribedCircle(polygonal matrix.Steric, tolerance float64) (matrix.Matrix, float64, error) {
+	if polygonal == nil || polygonal.IsEmpty() {
+		return nil, 0, algorithm.ErrNilSteric
+	}
+	if tolerance <= 0 {
+		return nil, 0, algorithm.ErrNotPositiveTolerance
+	}
+	locator, err := relate.NewIndexedPointInAreaLocator(polygonal, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	boundary := newSegmentIndex(polygonal)
+	cell := func(x, y, h float64) *searchCell {
+		p := matrix.Matrix{x, y}
+		c := &searchCell{x: x, y: y, h: h, distance: boundary.distance(p)}
+		if c.isCandidate = locator.Locate(p) != calc.ImExterior; !c.isCandidate {
+			c.distance = -c.distance
+		}
+		c.max = c.distance + h*math.Sqrt2
+		return c
+	}
+	best := searchFarthest(polygonal, tolerance, cell)
+	return matrix.Matrix{best.x, best.y}, best.distance, nil
+}
+
+// LargestEmptyCircle Computes the largest circle whose interior does not intersect the obstacles
+// and whose centre lies in the boundary, a polygonal geometry, or in the convex hull of the obstacles if boundary is nil.
+// The obstacles are points, lines or polygons, the circle does not overlap polygonal obstacles.
+// The centre is searched as the one of the maximum inscribed circle, within the tolerance.
+// Returns the centre and the radius of the circle, the radius is zero if the convex hull of the obstacles is not polygonal.
+func LargestEmptyCircle(obstacles, boundary matrix.Steric, tolerance float64) (matrix.Matrix, float64, error) {
+	if obstacles == nil || obstacles.IsEmpty() {
+		return nil, 0, algorithm.ErrNilSteric
+	}
+	if tolerance <= 0 {
+		return nil, 0, algorithm.ErrNotPositiveTolerance
+	}
+	if boundary == nil || boundary.IsEmpty() {
+		boundary = ConvexHull(hullInput(obstacles))
+		if _, ok := boundary.(matrix.PolygonMatrix); !ok {
+			p := matrix.TransMatrixes(obstacles)[0]
+			return matrix.Matrix{p[0], p[1]}, 0, nil
+		}
+	}
+	boundaryLocator, err := relate.NewIndexedPointInAreaLocator(boundary, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	boundaryIndex := newSegmentIndex(boundary)
+	obstacleIndex := newSegmentIndex(obstacles)
+	var obstacleLocator *relate.IndexedPointInAreaLocator
+	if areal := polygonalComponents(obstacles, matrix.Collection{}); len(areal) > 0 {
+		if obstacleLocator, err = relate.NewIndexedPointInAreaLocator(areal, 0); err != nil {
+			return nil, 0, err
+		}
+	}
+	cell := func(x, y, h float64) *searchCell {
+		p := matrix.Matrix{x, y}
+		c := &searchCell{x: x, y: y, h: h}
+		if obstacleLocator == nil || obstacleLocator.Locate(p) == calc.ImExterior {
+			c.distance = obstacleIndex.distance(p)
+		}
+		c.max = c.distance + h*math.Sqrt2
+		c.isCandidate = boundaryLocator.Locate(p) != calc.ImExterior
+		// a cell outside the boundary has no candidate centre.
+		if !c.isCandidate && boundaryIndex.distance(p) > h*math.Sqrt2 {
+			c.max = math.Inf(-1)
+		}
+		return c
+	}
+	best := searchFarthest(boundary, tolerance, cell)
+	return matrix.Matrix{best.x, best.y}, best.distance, nil
+}
+
+// searchCell a square cell of the search, of centre x,y and half side h,
+// with the distance of its centre and an upper bound of the distance over the cell.
+type searchCell struct {
+	x, y, h       float64
+	distance, max float64
+	// isCandidate the centre of the cell is a candidate of the search.
+	isCandidate bool
+}
+
+// searchFarthest returns the candidate cell of maximum distance within the tolerance,
+// searching from the cell covering the region, seeded by its centroid and its interior point.
+func searchFarthest(region matrix.Steric, tolerance float64, cell func(x, y, h float64) *searchCell) *searchCell {
+	env := envelope.MatrixList(matrix.TransMatrixes(region))
+	var best *searchCell
+	for _, p := range []matrix.Matrix{Centroid(region), InteriorPoint(region)} {
+		if len(p) < 2 {
+			continue
+		}
+		if c := cell(p[0], p[1], 0); c.isCandidate && (best == nil || c.distance > best.distance) {
+			best = c
+		}
+	}
+	h := math.Max(env.Width(), env.Height()) / 2
+	queue := &cellQueue{}
+	heap.Push(queue, cell((env.MinX+env.MaxX)/2, (env.MinY+env.MaxY)/2, h))
+	for queue.Len() > 0 {
+		c := heap.Pop(queue).(*searchCell)
+		if c.isCandidate && (best == nil || c.distance > best.distance) {
+			best = c
+		}
+		if math.IsInf(c.max, -1) {
+			continue
+		}
+		// the cells are taken by decreasing bound, none of the remaining ones may improve the best one.
+		if best != nil && c.max-best.distance <= tolerance {
+			break
+		}
+		h := c.h / 2
+		if h == 0 {
+			continue
+		}
+		heap.Push(queue, cell(c.x-h, c.y-h, h))
+		heap.Push(queue, cell(c.x+h, c.y-h, h))
+		heap.Push(queue, cell(c.x-h, c.y+h, h))
+		heap.Push(queue, cell(c.x+h, c.y+h, h))
+	}
+	if best == nil {
+		p := matrix.TransMatrixes(region)[0]
+		return &searchCell{x: p[0], y: p[1]}
+	}
+	return best
+}
+
+// cellQueue a priority queue of the cells of maximum bound.
+type cellQueue []*searchCell
+
+func (q cellQueue) Len() int            { return len(q) }
+func (q cellQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q cellQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cellQueue) Push(x interface{}) { *q = append(*q, x.(*searchCell)) }
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// segmentIndex the segments and the points of a geometry, indexed for the distance to their nearest one.
+type segmentIndex struct {
+	tree *strtree.STRtree
+}
+
+func newSegmentIndex(geom matrix.Steric) *segmentIndex {
+	s := &segmentIndex{tree: strtree.NewSTRtree()}
+	s.add(geom)
+	return s
+}
+
+func (s *segmentIndex) add(geom matrix.Steric) {
+	switch m := geom.(type) {
+	case matrix.Matrix:
+		s.insert(m, m)
+	case matrix.LineMatrix:
+		if len(m) == 1 {
+			s.insert(m[0], m[0])
+		}
+		for i := 1; i < len(m); i++ {
+			s.insert(m[i-1], m[i])
+		}
+	case matrix.PolygonMatrix:
+		for _, ring := range m {
+			s.add(matrix.LineMatrix(ring))
+		}
+	case matrix.MultiPolygonMatrix:
+		for _, v := range m {
+			s.add(matrix.PolygonMatrix(v))
+		}
+	case matrix.Collection:
+		for _, v := range m {
+			s.add(v)
+		}
+	}
+}
+
+func (s *segmentIndex) insert(p0, p1 matrix.Matrix) {
+	_ = s.tree.Insert(envelope.TwoMatrix(p0, p1), &matrix.LineSegment{P0: p0, P1: p1})
+}
+
+// distance returns the distance of the point to the nearest segment.
+func (s *segmentIndex) distance(p matrix.Matrix) float64 {
+	nearest := s.tree.NearestNeighbours(envelope.Matrix(p), 1, math.Inf(1), func(item interface{}) float64 {
+		seg := item.(*matrix.LineSegment)
+		return segmentDistance(p, seg.P0, seg.P1)
+	})
+	if len(nearest) == 0 {
+		return math.Inf(1)
+	}
+	return nearest[0].Distance
+}
+
+// segmentDistance returns the distance of p to the segment ab.
+func segmentDistance(p, a, b matrix.Matrix) float64 {
+	if a.Equals(b) {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	f := segmentFraction(p, a, b)
+	return math.Hypot(p[0]-(a[0]+f*(b[0]-a[0])), p[1]-(a[1]+f*(b[1]-a[1])))
+}
+
+// polygonalComponents appends the polygons of the geometry to the collection.
+func polygonalComponents(geom matrix.Steric, polygons matrix.Collection) matrix.Collection {
+	switch m := geom.(type) {
+	case matrix.PolygonMatrix:
+		polygons = append(polygons, m)
+	case matrix.MultiPolygonMatrix:
+		for _, v := range m {
+			polygons = append(polygons, matrix.PolygonMatrix(v))
+		}
+	case matrix.Collection:
+		for _, v := range m {
+			polygons = polygonalComponents(v, polygons)
+		}
+	}
+	return polygons
+}
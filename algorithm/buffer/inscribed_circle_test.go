@@ -0,0 +1,85 @@
+package buffer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+var (
+	square = matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	// the centre of the L shape is on its diagonal, as far from the sides as from the inner corner.
+	lShape      = matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 10}, {0, 10}, {0, 0}}}
+	lShapePole  = 2 * math.Sqrt2 / (1 + math.Sqrt2)
+	holedSquare = matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}}
+)
+
+func TestMaximumInscribedCircle(t *testing.T) {
+	tests := []struct {
+		name       string
+		polygonal  matrix.Steric
+		tolerance  float64
+		wantCentre matrix.Matrix
+		wantRadius float64
+		wantErr    error
+	}{
+		{"square", square, 0.001, matrix.Matrix{5, 5}, 5, nil},
+		{"l shape", lShape, 0.001, matrix.Matrix{lShapePole, lShapePole}, lShapePole, nil},
+		{"multi polygon", matrix.Collection{square, matrix.PolygonMatrix{{{20, 0}, {40, 0}, {40, 20}, {20, 20}, {20, 0}}}}, 0.001,
+			matrix.Matrix{30, 10}, 10, nil},
+		{"empty", matrix.PolygonMatrix{}, 0.001, nil, 0, algorithm.ErrNilSteric},
+		{"zero tolerance", square, 0, nil, 0, algorithm.ErrNotPositiveTolerance},
+		{"line", matrix.LineMatrix{{0, 0}, {1, 1}}, 0.001, nil, 0, algorithm.ErrNotMatchType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			centre, radius, err := MaximumInscribedCircle(tt.polygonal, tt.tolerance)
+			if err != tt.wantErr {
+				t.Fatalf("MaximumInscribedCircle() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (!centre.EqualsExact(tt.wantCentre, 0.01) || math.Abs(radius-tt.wantRadius) > tt.tolerance) {
+				t.Errorf("MaximumInscribedCircle() = %v %v, want %v %v", centre, radius, tt.wantCentre, tt.wantRadius)
+			}
+		})
+	}
+	// the centre of the holed square is in a corner between the hole and the shell.
+	centre, radius, _ := MaximumInscribedCircle(holedSquare, 0.001)
+	if want := 8 - 4*math.Sqrt2; math.Abs(radius-want) > 0.001 || math.Abs(math.Abs(centre[0]-5)-(5-want)) > 0.01 {
+		t.Errorf("MaximumInscribedCircle() = %v %v, want radius %v", centre, radius, want)
+	}
+}
+
+func TestLargestEmptyCircle(t *testing.T) {
+	corners := matrix.Collection{matrix.Matrix{0, 0}, matrix.Matrix{10, 0}, matrix.Matrix{10, 10}, matrix.Matrix{0, 10}}
+	tests := []struct {
+		name       string
+		obstacles  matrix.Steric
+		boundary   matrix.Steric
+		wantCentre matrix.Matrix
+		wantRadius float64
+		wantErr    error
+	}{
+		{"points", corners, nil, matrix.Matrix{5, 5}, 5 * math.Sqrt2, nil},
+		{"lines", matrix.Collection{matrix.LineMatrix{{0, 0}, {10, 0}}, matrix.LineMatrix{{0, 4}, {10, 4}}}, nil,
+			matrix.Matrix{5, 2}, 2, nil},
+		{"boundary", matrix.Matrix{1, 1}, square, matrix.Matrix{10, 10}, 9 * math.Sqrt2, nil},
+		{"polygon obstacle", matrix.Collection{square, matrix.Matrix{30, 0}, matrix.Matrix{30, 10}}, nil,
+			matrix.Matrix{20.625, 5}, 10.625, nil},
+		{"single point", matrix.Matrix{1, 1}, nil, matrix.Matrix{1, 1}, 0, nil},
+		{"empty", matrix.Collection{}, nil, nil, 0, algorithm.ErrNilSteric},
+		{"line boundary", corners, matrix.LineMatrix{{0, 0}, {1, 1}}, nil, 0, algorithm.ErrNotMatchType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			centre, radius, err := LargestEmptyCircle(tt.obstacles, tt.boundary, 0.001)
+			if err != tt.wantErr {
+				t.Fatalf("LargestEmptyCircle() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (!centre.EqualsExact(tt.wantCentre, 0.01) || math.Abs(radius-tt.wantRadius) > 0.001) {
+				t.Errorf("LargestEmptyCircle() = %v %v, want %v %v", centre, radius, tt.wantCentre, tt.wantRadius)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package buffer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+// MakeValid Repairs a polygonal geometry, a PolygonMatrix, a MultiPolygonMatrix or a Collection of PolygonMatrix,
+// the self-intersections, bow-ties, ring orientations, duplicate or degenerate rings and overlapping polygons.
+// The rings are noded into a planar graph and the faces of the graph are kept by the method:
+// calc.MakeValidLinework keeps the faces enclosed by an odd number of the distinct rings,
+// so the overlaps of polygons are removed, and keeps the collapsed linework as lines, or points;
+// calc.MakeValidStructure keeps the faces enclosed by a shell and by none of its holes,
+// so the overlapping polygons are merged, and drops the collapsed rings.
+// Returns a PolygonMatrix, a Collection of PolygonMatrix, lines or points for collapsed linework,
+// a Collection of polygons and lines, or an empty PolygonMatrix if nothing is kept.
+func MakeValid(polygonal matrix.Steric, method int) (matrix.Steric, error) {
+	if polygonal == nil {
+		return nil, algorithm.ErrNilSteric
+	}
+	if method != calc.MakeValidLinework && method != calc.MakeValidStructure {
+		return nil, algorithm.ErrWrongMakeValidMethod
+	}
+	polys, err := polygonalParts(polygonal, nil)
+	if err != nil {
+		return nil, err
+	}
+	v := &validMaker{method: method, seen: map[string]bool{}}
+	for i, poly := range polys {
+		for j, ring := range poly {
+			v.addRing(ring, i, j == 0)
+		}
+	}
+	return v.make(), nil
+}
+
+// polygonalParts appends the polygons of a polygonal geometry.
+func polygonalParts(polygonal matrix.Steric, polys []matrix.PolygonMatrix) ([]matrix.PolygonMatrix, error) {
+	var err error
+	switch m := polygonal.(type) {
+	case matrix.PolygonMatrix:
+		polys = append(polys, m)
+	case matrix.MultiPolygonMatrix:
+		for _, v := range m {
+			polys = append(polys, matrix.PolygonMatrix(v))
+		}
+	case matrix.Collection:
+		for _, v := range m {
+			if polys, err = polygonalParts(v, polys); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, algorithm.ErrNotMatchType
+	}
+	return polys, nil
+}
+
+// validMaker the rings of the polygons to repair, and the polygon and role of each ring.
+type validMaker struct {
+	method    int
+	rings     []matrix.LineMatrix
+	polygonOf []int
+	isShell   []bool
+	// points the rings collapsed to a point.
+	points []matrix.Matrix
+	// seen the keys of the rings, the duplicate rings are dropped by the linework method.
+	seen map[string]bool
+}
+
+// addRing adds a ring, closed and without repeated points.
+func (v *validMaker) addRing(ring matrix.LineMatrix, polygon int, isShell bool) {
+	ring = removeRepeatedPoints(ring)
+	if len(ring) == 0 {
+		return
+	}
+	if !matrix.Matrix(ring[0]).Equals(matrix.Matrix(ring[len(ring)-1])) {
+		ring = append(append(matrix.LineMatrix{}, ring...), ring[0])
+	}
+	if len(ring) < 3 {
+		v.points = append(v.points, matrix.Matrix{ring[0][0], ring[0][1]})
+		return
+	}
+	if v.method == calc.MakeValidLinework {
+		key := ringKey(ring)
+		if v.seen[key] {
+			return
+		}
+		v.seen[key] = true
+	}
+	v.rings = append(v.rings, ring)
+	v.polygonOf = append(v.polygonOf, polygon)
+	v.isShell = append(v.isShell, isShell)
+}
+
+// make builds the graph of the rings, computes the winding numbers of the rings around its faces,
+// and returns the faces kept by the method.
+func (v *validMaker) make() matrix.Steric {
+	g, labels := v.graph()
+	g.traceFaces()
+	windings := v.windings(g, labels)
+	g.depth = make([]int, len(g.faces))
+	for f, winding := range windings {
+		if v.isKept(winding) {
+			g.depth[f] = 1
+		}
+	}
+	result := matrix.Collection{}
+	for _, poly := range g.polygons() {
+		result = append(result, poly)
+	}
+	if v.method == calc.MakeValidLinework {
+		for _, line := range v.collapsedLines(g) {
+			result = append(result, line)
+		}
+		if len(result) == 0 {
+			for _, p := range v.points {
+				duplicate := false
+				for _, q := range result {
+					duplicate = duplicate || q.Equals(p)
+				}
+				if !duplicate {
+					result = append(result, p)
+				}
+			}
+		}
+	}
+	switch len(result) {
+	case 0:
+		return matrix.PolygonMatrix{}
+	case 1:
+		return result[0]
+	}
+	return result
+}
+
+// graph returns the graph of the noded rings, and the winding number deltas of the rings across each half-edge,
+// the winding number of a ring on the left of a half-edge is the one on its right plus its delta.
+func (v *validMaker) graph() (*bufferGraph, []map[int]int) {
+	g := &bufferGraph{}
+	type edgeKey [2]int
+	deltas := map[edgeKey]map[int]int{}
+	keys := []edgeKey{}
+	g.nodeCurves(v.rings, func(ring, orig, dest int) {
+		key, delta := edgeKey{orig, dest}, 1
+		if dest < orig {
+			key, delta = edgeKey{dest, orig}, -1
+		}
+		if _, ok := deltas[key]; !ok {
+			deltas[key] = map[int]int{}
+			keys = append(keys, key)
+		}
+		deltas[key][ring] += delta
+	})
+	g.out = make([][]int, len(g.nodes))
+	labels := make([]map[int]int, 0, 2*len(keys))
+	// the coincident edges are merged, an edge of no delta is kept as it may be collapsed linework.
+	for _, key := range keys {
+		label, twin, sum := map[int]int{}, map[int]int{}, 0
+		for ring, delta := range deltas[key] {
+			if delta != 0 {
+				label[ring], twin[ring] = delta, -delta
+				sum += delta
+			}
+		}
+		g.addEdge(key[0], key[1], sum)
+		g.addEdge(key[1], key[0], -sum)
+		labels = append(labels, label, twin)
+	}
+	g.sortEdges()
+	return g, labels
+}
+
+// windings returns the winding numbers of the rings around each face, the ones of the outer face
+// of each connected component are given by the other components, the others are propagated across the edges.
+func (v *validMaker) windings(g *bufferGraph, labels []map[int]int) []map[int]int {
+	windings := make([]map[int]int, len(g.faces))
+	for c, faces := range g.components() {
+		outer := g.outerFace(faces)
+		windings[outer] = v.windingAt(g, labels, g.nodes[g.edges[g.faces[outer][0]].orig], c)
+		queue := []int{outer}
+		for len(queue) > 0 {
+			f := queue[0]
+			queue = queue[1:]
+			for _, e := range g.faces[f] {
+				right := g.edges[e^1].face
+				if windings[right] != nil {
+					continue
+				}
+				winding := map[int]int{}
+				for ring, w := range windings[f] {
+					winding[ring] = w
+				}
+				for ring, delta := range labels[e] {
+					if winding[ring] -= delta; winding[ring] == 0 {
+						delete(winding, ring)
+					}
+				}
+				windings[right] = winding
+				queue = append(queue, right)
+			}
+		}
+	}
+	return windings
+}
+
+// windingAt returns the winding numbers of the rings around the point given by the edges of the other components than c.
+func (v *validMaker) windingAt(g *bufferGraph, labels []map[int]int, p matrix.Matrix, c int) map[int]int {
+	winding := map[int]int{}
+	for i := 0; i < len(g.edges); i += 2 {
+		e := g.edges[i]
+		if g.componentOf[e.orig] == c {
+			continue
+		}
+		p0, p1 := g.nodes[e.orig], g.nodes[e.dest]
+		sign := 0
+		if p0[1] <= p[1] {
+			if p1[1] > p[1] && OrientationIndex(p0, p1, p) == calc.CounterClockWise {
+				sign = 1
+			}
+		} else if p1[1] <= p[1] && OrientationIndex(p0, p1, p) == calc.ClockWise {
+			sign = -1
+		}
+		for ring, delta := range labels[i] {
+			winding[ring] += sign * delta
+		}
+	}
+	for ring, w := range winding {
+		if w == 0 {
+			delete(winding, ring)
+		}
+	}
+	return winding
+}
+
+// isKept returns true if a face of the winding numbers is in the valid geometry.
+func (v *validMaker) isKept(winding map[int]int) bool {
+	if v.method == calc.MakeValidLinework {
+		sum := 0
+		for _, w := range winding {
+			sum += w
+		}
+		return sum%2 != 0
+	}
+	shells, holed := map[int]bool{}, map[int]bool{}
+	for ring := range winding {
+		if v.isShell[ring] {
+			shells[v.polygonOf[ring]] = true
+		} else {
+			holed[v.polygonOf[ring]] = true
+		}
+	}
+	for polygon := range shells {
+		if !holed[polygon] {
+			return true
+		}
+	}
+	return false
+}
+
+// collapsedLines returns the linework outside the kept faces which encloses no face, merged into lines.
+func (v *validMaker) collapsedLines(g *bufferGraph) []matrix.LineMatrix {
+	adjacent := map[int][]int{}
+	for i := 0; i < len(g.edges); i += 2 {
+		if face := g.edges[i].face; face == g.edges[i+1].face && g.depth[face] == 0 {
+			adjacent[g.edges[i].orig] = append(adjacent[g.edges[i].orig], i)
+			adjacent[g.edges[i].dest] = append(adjacent[g.edges[i].dest], i+1)
+		}
+	}
+	nodes := make([]int, 0, len(adjacent))
+	for n := range adjacent {
+		nodes = append(nodes, n)
+	}
+	sort.Ints(nodes)
+	used := map[int]bool{}
+	lines := []matrix.LineMatrix{}
+	trace := func(n, e int) {
+		line := matrix.LineMatrix{g.nodes[n]}
+		for {
+			used[e>>1] = true
+			n = g.edges[e].dest
+			line = append(line, g.nodes[n])
+			if len(adjacent[n]) != 2 {
+				break
+			}
+			next := adjacent[n][0]
+			if used[next>>1] {
+				next = adjacent[n][1]
+			}
+			if used[next>>1] {
+				break
+			}
+			e = next
+		}
+		lines = append(lines, line)
+	}
+	// the lines start at their end points or at a junction, then the closed lines are traced.
+	for _, n := range nodes {
+		if len(adjacent[n]) != 2 {
+			for _, e := range adjacent[n] {
+				if !used[e>>1] {
+					trace(n, e)
+				}
+			}
+		}
+	}
+	for _, n := range nodes {
+		for _, e := range adjacent[n] {
+			if !used[e>>1] {
+				trace(n, e)
+			}
+		}
+	}
+	return lines
+}
+
+// ringKey returns a key of a closed ring independent of its start point and its orientation.
+func ringKey(ring matrix.LineMatrix) string {
+	pts := ring[:len(ring)-1]
+	n := len(pts)
+	start := 0
+	for i := range pts {
+		if pts[i][0] < pts[start][0] || (pts[i][0] == pts[start][0] && pts[i][1] < pts[start][1]) {
+			start = i
+		}
+	}
+	step := 1
+	next, prev := pts[(start+1)%n], pts[(start-1+n)%n]
+	if prev[0] < next[0] || (prev[0] == next[0] && prev[1] < next[1]) {
+		step = -1
+	}
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		p := pts[((start+i*step)%n+n)%n]
+		b.WriteString(strconv.FormatFloat(p[0], 'g', -1, 64))
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(p[1], 'g', -1, 64))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
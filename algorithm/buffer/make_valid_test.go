@@ -0,0 +1,91 @@
+package buffer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/measure"
+)
+
+func TestMakeValid(t *testing.T) {
+	square := matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	tests := []struct {
+		name      string
+		polygonal matrix.Steric
+		method    int
+		// wantPolygons the number of polygons and their area.
+		wantPolygons int
+		wantArea     float64
+		wantLines    []matrix.LineMatrix
+	}{
+		{"valid", square, calc.MakeValidLinework, 1, 100, nil},
+		{"clockwise shell", matrix.PolygonMatrix{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}}, calc.MakeValidStructure, 1, 100, nil},
+		{"unclosed shell", matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}, calc.MakeValidStructure, 1, 100, nil},
+		{"bow-tie", matrix.PolygonMatrix{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}, calc.MakeValidLinework, 2, 50, nil},
+		{"bow-tie structure", matrix.PolygonMatrix{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}, calc.MakeValidStructure, 2, 50, nil},
+		// the part of the hole outside the shell is kept by the linework.
+		{"hole across shell", matrix.PolygonMatrix{square[0], {{5, 2}, {15, 2}, {15, 8}, {5, 8}, {5, 2}}}, calc.MakeValidLinework, 2, 100, nil},
+		{"hole across shell structure", matrix.PolygonMatrix{square[0], {{5, 2}, {15, 2}, {15, 8}, {5, 8}, {5, 2}}}, calc.MakeValidStructure, 1, 70, nil},
+		{"self-touching shell", matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {5, 10}, {8, 5}, {2, 5}, {5, 10}, {0, 10}, {0, 0}}},
+			calc.MakeValidLinework, 1, 85, nil},
+		{"duplicate ring", matrix.PolygonMatrix{square[0], square[0]}, calc.MakeValidLinework, 1, 100, nil},
+		{"duplicate ring structure", matrix.PolygonMatrix{square[0], square[0]}, calc.MakeValidStructure, 0, 0, nil},
+		// the overlap of the polygons is removed by the linework and merged by the structure.
+		{"overlapping polygons", matrix.Collection{square, matrix.PolygonMatrix{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}},
+			calc.MakeValidLinework, 2, 150, nil},
+		{"overlapping polygons structure", matrix.Collection{square, matrix.PolygonMatrix{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}},
+			calc.MakeValidStructure, 1, 175, nil},
+		{"spike", matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {10, 5}, {10, 10}, {0, 10}, {0, 0}}},
+			calc.MakeValidLinework, 1, 100, []matrix.LineMatrix{{{10, 5}, {15, 5}}}},
+		{"spike structure", matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {10, 5}, {10, 10}, {0, 10}, {0, 0}}},
+			calc.MakeValidStructure, 1, 100, nil},
+		{"collapsed", matrix.PolygonMatrix{{{0, 0}, {1, 1}, {2, 2}, {0, 0}}}, calc.MakeValidLinework, 0, 0,
+			[]matrix.LineMatrix{{{0, 0}, {1, 1}, {2, 2}}}},
+		{"collapsed structure", matrix.PolygonMatrix{{{0, 0}, {1, 1}, {2, 2}, {0, 0}}}, calc.MakeValidStructure, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakeValid(tt.polygonal, tt.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			polys, lines := []matrix.PolygonMatrix{}, []matrix.LineMatrix{}
+			parts := matrix.Collection{got}
+			if coll, ok := got.(matrix.Collection); ok {
+				parts = coll
+			}
+			area := 0.0
+			for _, v := range parts {
+				switch part := v.(type) {
+				case matrix.PolygonMatrix:
+					if !part.IsEmpty() {
+						polys = append(polys, part)
+						area += measure.AreaOfPolygon(part)
+					}
+				case matrix.LineMatrix:
+					lines = append(lines, part)
+				}
+			}
+			if len(polys) != tt.wantPolygons || math.Abs(area-tt.wantArea) > 1e-9 || len(lines) != len(tt.wantLines) {
+				t.Fatalf("MakeValid() = %v, want %v polygons of area %v and %v lines", got, tt.wantPolygons, tt.wantArea, len(tt.wantLines))
+			}
+			for i, line := range lines {
+				if !line.Equals(tt.wantLines[i]) && !line.Equals(tt.wantLines[i].Reverse()) {
+					t.Errorf("MakeValid() line = %v, want %v", line, tt.wantLines[i])
+				}
+			}
+		})
+	}
+	if got, _ := MakeValid(matrix.PolygonMatrix{{{1, 1}, {1, 1}, {1, 1}, {1, 1}}}, calc.MakeValidLinework); !got.Equals(matrix.Matrix{1, 1}) {
+		t.Errorf("MakeValid() = %v, want the point", got)
+	}
+	if _, err := MakeValid(square, 0); err != algorithm.ErrWrongMakeValidMethod {
+		t.Errorf("MakeValid() error = %v, want %v", err, algorithm.ErrWrongMakeValidMethod)
+	}
+	if _, err := MakeValid(matrix.LineMatrix{{0, 0}, {1, 1}}, calc.MakeValidLinework); err != algorithm.ErrNotMatchType {
+		t.Errorf("MakeValid() error = %v, want %v", err, algorithm.ErrNotMatchType)
+	}
+}
@@ -0,0 +1,283 @@
+package buffer
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+// MinimumBoundingCircle Computes the smallest circle which contains a geometry,
+// from its distinct vertices in shuffled order by Welzl's algorithm.
+// Returns the centre and the radius of the circle, nil if the geometry is empty.
+func MinimumBoundingCircle(geom matrix.Steric) (matrix.Matrix, float64) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, 0
+	}
+	pts := distinctVertices(geom)
+	if len(pts) == 0 {
+		return nil, 0
+	}
+	// the expected time is linear for a random order, the shuffle is seeded to keep the result stable.
+	random := rand.New(rand.NewSource(int64(len(pts))))
+	random.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+	centre, radius := matrix.Matrix{pts[0][0], pts[0][1]}, 0.0
+	for i := 1; i < len(pts); i++ {
+		if inCircle(pts[i], centre, radius) {
+			continue
+		}
+		centre, radius = matrix.Matrix{pts[i][0], pts[i][1]}, 0.0
+		for j := 0; j < i; j++ {
+			if inCircle(pts[j], centre, radius) {
+				continue
+			}
+			centre, radius = diameterCircle(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if !inCircle(pts[k], centre, radius) {
+					centre, radius = circumCircle(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+	return centre, radius
+}
+
+// MinimumDiameter Computes the minimum width of a geometry, the smallest distance between
+// two parallel lines enclosing it, by rotating calipers over its convex hull.
+// Returns the segment from a vertex of the hull perpendicular to the opposite edge,
+// nil if the geometry is empty.
+func MinimumDiameter(geom matrix.Steric) matrix.LineMatrix {
+	pts := hullVertices(geom)
+	switch len(pts) {
+	case 0:
+		return nil
+	case 1, 2:
+		return matrix.LineMatrix{pts[0], pts[0]}
+	}
+	c := newCalipers(pts)
+	best, bestWidth := 0, math.Inf(1)
+	for i := range pts {
+		c.rotate(i)
+		if width := c.width(); width < bestWidth {
+			best, bestWidth = i, width
+		}
+	}
+	c = newCalipers(pts)
+	for i := 0; i <= best; i++ {
+		c.rotate(i)
+	}
+	far := pts[c.far]
+	u, _ := c.axes()
+	t := (far[0]-pts[best][0])*u[0] + (far[1]-pts[best][1])*u[1]
+	return matrix.LineMatrix{far, {pts[best][0] + t*u[0], pts[best][1] + t*u[1]}}
+}
+
+// MinimumAreaRectangle Computes the rectangle of minimum area which contains a geometry,
+// which has a side collinear with an edge of the convex hull of the geometry.
+// Returns a PolygonMatrix, the LineMatrix of collinear geometries or the Matrix of a single point,
+// nil if the geometry is empty.
+func MinimumAreaRectangle(geom matrix.Steric) matrix.Steric {
+	return minimumRectangle(geom, func(c *calipers) float64 { return c.width() * c.length() })
+}
+
+// MinimumWidthRectangle Computes the rectangle of minimum width which contains a geometry,
+// its width is the minimum diameter of the geometry.
+// Returns a PolygonMatrix, the LineMatrix of collinear geometries or the Matrix of a single point,
+// nil if the geometry is empty.
+func MinimumWidthRectangle(geom matrix.Steric) matrix.Steric {
+	return minimumRectangle(geom, func(c *calipers) float64 { return c.width() })
+}
+
+// minimumRectangle returns the enclosing rectangle minimizing the measure over the edges of the convex hull.
+func minimumRectangle(geom matrix.Steric, measure func(*calipers) float64) matrix.Steric {
+	pts := hullVertices(geom)
+	switch len(pts) {
+	case 0:
+		return nil
+	case 1:
+		return pts[0]
+	case 2:
+		return matrix.LineMatrix{pts[0], pts[1]}
+	}
+	c := newCalipers(pts)
+	best, bestMeasure := 0, math.Inf(1)
+	for i := range pts {
+		c.rotate(i)
+		if m := measure(c); m < bestMeasure {
+			best, bestMeasure = i, m
+		}
+	}
+	c = newCalipers(pts)
+	for i := 0; i <= best; i++ {
+		c.rotate(i)
+	}
+	return c.rectangle()
+}
+
+// hullVertices returns the distinct vertices of the convex hull of a geometry, CCW for a polygonal hull.
+func hullVertices(geom matrix.Steric) []matrix.Matrix {
+	if geom == nil || geom.IsEmpty() {
+		return nil
+	}
+	switch hull := ConvexHullWithGeom(hullInput(geom)).ConvexHull().(type) {
+	case matrix.Matrix:
+		return []matrix.Matrix{hull}
+	case matrix.LineMatrix:
+		pts := []matrix.Matrix{hull[0]}
+		if !matrix.Matrix(hull[0]).Equals(matrix.Matrix(hull[len(hull)-1])) {
+			pts = append(pts, hull[len(hull)-1])
+		}
+		return pts
+	case matrix.PolygonMatrix:
+		ring := hull[0]
+		pts := make([]matrix.Matrix, 0, len(ring)-1)
+		// the convex hull is CW.
+		for i := len(ring) - 1; i > 0; i-- {
+			pts = append(pts, ring[i])
+		}
+		if OrientationIndex(pts[0], pts[1], pts[2]) < 0 {
+			for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+				pts[i], pts[j] = pts[j], pts[i]
+			}
+		}
+		return pts
+	}
+	return nil
+}
+
+// distinctVertices returns the distinct vertices of all the components of a geometry.
+func distinctVertices(geom matrix.Steric) []matrix.Matrix {
+	pts := []matrix.Matrix{}
+	seen := map[[2]float64]bool{}
+	for _, v := range matrix.TransMatrixes(geom) {
+		if key := [2]float64{v[0], v[1]}; !seen[key] {
+			seen[key] = true
+			pts = append(pts, v)
+		}
+	}
+	return pts
+}
+
+// hullInput returns the vertices of all the components of a geometry, as the input of a convex hull.
+func hullInput(geom matrix.Steric) matrix.LineMatrix {
+	vertices := matrix.LineMatrix{}
+	for _, v := range matrix.TransMatrixes(geom) {
+		vertices = append(vertices, v)
+	}
+	return vertices
+}
+
+// calipers the rotating calipers over the edges of a CCW convex polygon,
+// with the vertices farthest from the current edge and at its extents along it.
+type calipers struct {
+	pts []matrix.Matrix
+	// edge the index of the first vertex of the current edge.
+	edge              int
+	far, minAt, maxAt int
+}
+
+func newCalipers(pts []matrix.Matrix) *calipers {
+	c := &calipers{pts: pts, edge: 0}
+	u, n := c.axes()
+	for i := range pts {
+		if c.along(i, n) > c.along(c.far, n) {
+			c.far = i
+		}
+		if c.along(i, u) > c.along(c.maxAt, u) {
+			c.maxAt = i
+		}
+		if c.along(i, u) < c.along(c.minAt, u) {
+			c.minAt = i
+		}
+	}
+	return c
+}
+
+// rotate sets the current edge, the extreme vertices are advanced CCW as the edges rotate.
+func (c *calipers) rotate(edge int) {
+	c.edge = edge
+	u, n := c.axes()
+	c.far = c.advance(c.far, n, 1)
+	c.maxAt = c.advance(c.maxAt, u, 1)
+	c.minAt = c.advance(c.minAt, u, -1)
+}
+
+// advance moves the vertex CCW while the next one is strictly farther along the axis, in the direction of the sign.
+func (c *calipers) advance(i int, axis matrix.Matrix, sign float64) int {
+	for k := 0; k < len(c.pts); k++ {
+		next := (i + 1) % len(c.pts)
+		if sign*c.along(next, axis) <= sign*c.along(i, axis) {
+			break
+		}
+		i = next
+	}
+	return i
+}
+
+// axes returns the unit direction of the current edge and its left normal, towards the polygon.
+func (c *calipers) axes() (matrix.Matrix, matrix.Matrix) {
+	p0, p1 := c.pts[c.edge], c.pts[(c.edge+1)%len(c.pts)]
+	dx, dy := p1[0]-p0[0], p1[1]-p0[1]
+	length := math.Hypot(dx, dy)
+	return matrix.Matrix{dx / length, dy / length}, matrix.Matrix{-dy / length, dx / length}
+}
+
+// along returns the coordinate of a vertex along an axis from the first vertex of the current edge.
+func (c *calipers) along(i int, axis matrix.Matrix) float64 {
+	p0 := c.pts[c.edge]
+	return (c.pts[i][0]-p0[0])*axis[0] + (c.pts[i][1]-p0[1])*axis[1]
+}
+
+// width returns the distance of the farthest vertex from the current edge.
+func (c *calipers) width() float64 {
+	_, n := c.axes()
+	return c.along(c.far, n)
+}
+
+// length returns the extent of the polygon along the current edge.
+func (c *calipers) length() float64 {
+	u, _ := c.axes()
+	return c.along(c.maxAt, u) - c.along(c.minAt, u)
+}
+
+// rectangle returns the CCW rectangle enclosing the polygon with a side on the current edge.
+func (c *calipers) rectangle() matrix.PolygonMatrix {
+	u, n := c.axes()
+	p0 := c.pts[c.edge]
+	minU, maxU, maxN := c.along(c.minAt, u), c.along(c.maxAt, u), c.along(c.far, n)
+	corner := func(a, b float64) matrix.Matrix {
+		return matrix.Matrix{p0[0] + a*u[0] + b*n[0], p0[1] + a*u[1] + b*n[1]}
+	}
+	first := corner(minU, 0)
+	return matrix.PolygonMatrix{{first, corner(maxU, 0), corner(maxU, maxN), corner(minU, maxN), first}}
+}
+
+// inCircle returns true if the point is in the circle, with a tolerance relative to the radius.
+func inCircle(p, centre matrix.Matrix, radius float64) bool {
+	return math.Hypot(p[0]-centre[0], p[1]-centre[1]) <= radius*(1+1e-12)
+}
+
+// diameterCircle returns the circle of which the segment is a diameter.
+func diameterCircle(a, b matrix.Matrix) (matrix.Matrix, float64) {
+	return matrix.Matrix{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}, math.Hypot(b[0]-a[0], b[1]-a[1]) / 2
+}
+
+// circumCircle returns the circle through three points, the circle of the farthest pair if they are collinear.
+func circumCircle(a, b, c matrix.Matrix) (matrix.Matrix, float64) {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		centre, radius := diameterCircle(a, b)
+		for _, pair := range [][2]matrix.Matrix{{a, c}, {b, c}} {
+			if cc, r := diameterCircle(pair[0], pair[1]); r > radius {
+				centre, radius = cc, r
+			}
+		}
+		return centre, radius
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux := (cy*b2 - by*c2) / d
+	uy := (bx*c2 - cx*b2) / d
+	return matrix.Matrix{a[0] + ux, a[1] + uy}, math.Hypot(ux, uy)
+}
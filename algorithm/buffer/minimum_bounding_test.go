@@ -0,0 +1,149 @@
+package buffer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/algorithm/relate"
+)
+
+func TestMinimumBoundingCircle(t *testing.T) {
+	tests := []struct {
+		name       string
+		geom       matrix.Steric
+		wantCentre matrix.Matrix
+		wantRadius float64
+	}{
+		{"square", matrix.PolygonMatrix{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}, matrix.Matrix{1, 1}, math.Sqrt2},
+		{"obtuse triangle", matrix.LineMatrix{{0, 0}, {4, 0}, {1, 1}}, matrix.Matrix{2, 0}, 2},
+		{"acute triangle", matrix.LineMatrix{{0, 0}, {4, 0}, {2, 3}, {2, 1}}, matrix.Matrix{2, 5.0 / 6}, 13.0 / 6},
+		{"collinear", matrix.LineMatrix{{0, 0}, {1, 1}, {3, 3}}, matrix.Matrix{1.5, 1.5}, 1.5 * math.Sqrt2},
+		{"point", matrix.Matrix{1, 2}, matrix.Matrix{1, 2}, 0},
+		{"empty", matrix.LineMatrix{}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			centre, radius := MinimumBoundingCircle(tt.geom)
+			if tt.wantCentre == nil {
+				if centre != nil {
+					t.Errorf("MinimumBoundingCircle() centre = %v, want nil", centre)
+				}
+				return
+			}
+			if !centre.EqualsExact(tt.wantCentre, 1e-9) || math.Abs(radius-tt.wantRadius) > 1e-9 {
+				t.Errorf("MinimumBoundingCircle() = %v %v, want %v %v", centre, radius, tt.wantCentre, tt.wantRadius)
+			}
+		})
+	}
+}
+
+func TestMinimumDiameter(t *testing.T) {
+	tests := []struct {
+		name      string
+		geom      matrix.Steric
+		wantWidth float64
+	}{
+		{"rectangle", matrix.PolygonMatrix{{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 0}}}, 2},
+		{"diamond", matrix.PolygonMatrix{{{0, 2}, {2, 0}, {4, 2}, {2, 4}, {0, 2}}}, 2 * math.Sqrt2},
+		{"triangle", matrix.LineMatrix{{0, 0}, {4, 0}, {0, 1}}, 4 / math.Sqrt(17)},
+		{"collinear", matrix.LineMatrix{{0, 0}, {1, 1}, {3, 3}}, 0},
+		{"point", matrix.Matrix{1, 2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MinimumDiameter(tt.geom)
+			if width := measure.PlanarDistance(matrix.Matrix(got[0]), matrix.Matrix(got[1])); math.Abs(width-tt.wantWidth) > 1e-9 {
+				t.Errorf("MinimumDiameter() = %v, want width %v", got, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestMinimumRectangle(t *testing.T) {
+	tests := []struct {
+		name      string
+		geom      matrix.Steric
+		rectangle func(matrix.Steric) matrix.Steric
+		wantArea  float64
+		wantWidth float64
+	}{
+		{"area of diamond", matrix.PolygonMatrix{{{0, 2}, {2, 0}, {4, 2}, {2, 4}, {0, 2}}}, MinimumAreaRectangle, 8, 2 * math.Sqrt2},
+		{"width of diamond", matrix.PolygonMatrix{{{0, 2}, {2, 0}, {4, 2}, {2, 4}, {0, 2}}}, MinimumWidthRectangle, 8, 2 * math.Sqrt2},
+		// the thinnest rectangle of the trapezoid is not the smallest.
+		{"area of trapezoid", matrix.LineMatrix{{0, 0}, {4, 0}, {4, 2}, {0, 4}}, MinimumAreaRectangle, 16, 4},
+		{"width of trapezoid", matrix.LineMatrix{{0, 0}, {4, 0}, {4, 2}, {0, 4}}, MinimumWidthRectangle, 19.2, 8 / math.Sqrt(5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.rectangle(tt.geom).(matrix.PolygonMatrix)
+			if !ok {
+				t.Fatalf("rectangle = %v, want a polygon", got)
+			}
+			ring := got[0]
+			side0 := measure.PlanarDistance(matrix.Matrix(ring[0]), matrix.Matrix(ring[1]))
+			side1 := measure.PlanarDistance(matrix.Matrix(ring[1]), matrix.Matrix(ring[2]))
+			if math.Abs(measure.AreaOfPolygon(got)-tt.wantArea) > 1e-9 || math.Abs(math.Min(side0, side1)-tt.wantWidth) > 1e-9 {
+				t.Errorf("rectangle = %v, want area %v and width %v", got, tt.wantArea, tt.wantWidth)
+			}
+		})
+	}
+	if got := MinimumAreaRectangle(matrix.LineMatrix{{0, 0}, {1, 1}, {3, 3}}); !got.Equals(matrix.LineMatrix{{0, 0}, {3, 3}}) &&
+		!got.Equals(matrix.LineMatrix{{3, 3}, {0, 0}}) {
+		t.Errorf("MinimumAreaRectangle() = %v, want the segment", got)
+	}
+}
+
+func TestMinimumBounding_RandomCloud(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	spiral, cloud := matrix.LineMatrix{}, matrix.LineMatrix{}
+	for i := 0; i < 200; i++ {
+		a, r := float64(i)*0.3, 1+float64(i)*0.07
+		spiral = append(spiral, []float64{r * math.Cos(a), r * math.Sin(a)})
+		cloud = append(cloud, []float64{random.NormFloat64() * 30, random.Float64() * 10})
+	}
+	for name, pts := range map[string]matrix.LineMatrix{"spiral": spiral, "cloud": cloud} {
+		t.Run(name, func(t *testing.T) {
+			hull := ConvexHullWithGeom(pts).ConvexHull().(matrix.PolygonMatrix)
+			centre, radius := MinimumBoundingCircle(pts)
+			onCircle := 0
+			for _, p := range pts {
+				if relate.LocateInRings(p, hull[0]) == calc.ImExterior {
+					t.Errorf("ConvexHull() = %v, point %v is outside", hull, p)
+				}
+				d := math.Hypot(p[0]-centre[0], p[1]-centre[1])
+				if d > radius*(1+1e-9) {
+					t.Errorf("MinimumBoundingCircle() radius = %v, point %v is at %v", radius, p, d)
+				}
+				if d > radius*(1-1e-9) {
+					onCircle++
+				}
+			}
+			if onCircle < 2 {
+				t.Errorf("MinimumBoundingCircle() = %v %v, want at least 2 points on the circle", centre, radius)
+			}
+			for _, rectangle := range []matrix.Steric{MinimumAreaRectangle(pts), MinimumWidthRectangle(pts)} {
+				ring := rectangle.(matrix.PolygonMatrix)[0]
+				for _, p := range pts {
+					for i := 1; i < len(ring); i++ {
+						// the rectangle is CCW, the points are on the left of its sides.
+						a, b := ring[i-1], ring[i]
+						cross := (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+						if cross < -1e-9*math.Hypot(b[0]-a[0], b[1]-a[1]) {
+							t.Errorf("rectangle = %v, point %v is outside", rectangle, p)
+						}
+					}
+				}
+			}
+			width := MinimumDiameter(pts)
+			ring := MinimumWidthRectangle(pts).(matrix.PolygonMatrix)[0]
+			side := math.Min(measure.PlanarDistance(matrix.Matrix(ring[0]), matrix.Matrix(ring[1])), measure.PlanarDistance(matrix.Matrix(ring[1]), matrix.Matrix(ring[2])))
+			if d := measure.PlanarDistance(matrix.Matrix(width[0]), matrix.Matrix(width[1])); math.Abs(d-side) > 1e-9 {
+				t.Errorf("MinimumDiameter() = %v, want width %v", d, side)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package buffer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/index/strtree"
+)
+
+// offsetMatchFactor the tolerance of the match of the buffer boundary with the raw offset curve, relative to the distance.
+const offsetMatchFactor = 1e-6
+
+// OffsetCurve Computes the curve parallel to a line at a distance, on its left side for a positive distance
+// and on its right side for a negative one, in the direction of the line.
+// The corners use the join style, the mitre limit and the quadrant segments of the parameters,
+// nil parameters are the default ones.
+// The loops of the raw offset curve on the tight bends of the line are removed,
+// only the parts of the raw curve on the boundary of the buffer of the line are kept.
+// Returns nil if the line has less than two distinct points, or if no part of the curve is kept.
+func OffsetCurve(line matrix.LineMatrix, distance float64, param *CurveParameters) matrix.LineMatrix {
+	line = removeRepeatedPoints(line)
+	if len(line) < 2 {
+		return nil
+	}
+	if distance == 0 {
+		curve := make(matrix.LineMatrix, len(line))
+		for i, v := range line {
+			curve[i] = matrix.Matrix{v[0], v[1]}
+		}
+		return curve
+	}
+	param = param.withDefaults()
+	param.IsSingleSided = false
+	absDistance := math.Abs(distance)
+	builder := &CurveBuilder{Curve: CurveWithParameters(param, absDistance)}
+	raw := builder.OffsetCurve(line, absDistance, distance < 0)
+	return extractOffsetCurve(raw, BufferWithParams(line, absDistance, param), absDistance*offsetMatchFactor)
+}
+
+// offsetSection a segment of the buffer boundary on the raw offset curve, at positions along the raw curve.
+type offsetSection struct {
+	p0, p1     matrix.Matrix
+	pos0, pos1 float64
+}
+
+// extractOffsetCurve returns the segments of the rings of the buffer which lie on the raw offset curve,
+// ordered and joined along the raw curve.
+func extractOffsetCurve(raw matrix.LineMatrix, buffer matrix.Steric, tolerance float64) matrix.LineMatrix {
+	polys := []matrix.PolygonMatrix{}
+	switch b := buffer.(type) {
+	case matrix.PolygonMatrix:
+		polys = append(polys, b)
+	case matrix.Collection:
+		for _, v := range b {
+			if poly, ok := v.(matrix.PolygonMatrix); ok {
+				polys = append(polys, poly)
+			}
+		}
+	}
+	tree := strtree.NewSTRtree()
+	for i := 1; i < len(raw); i++ {
+		if matrix.Matrix(raw[i-1]).Equals(matrix.Matrix(raw[i])) {
+			continue
+		}
+		_ = tree.Insert(envelope.TwoMatrix(raw[i-1], raw[i]), i-1)
+	}
+	sections := []offsetSection{}
+	for _, poly := range polys {
+		for _, ring := range poly {
+			for i := 1; i < len(ring); i++ {
+				if section, ok := matchRawSegment(ring[i-1], ring[i], raw, tree, tolerance); ok {
+					sections = append(sections, section)
+				}
+			}
+		}
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].pos0 < sections[j].pos0 })
+	curve := matrix.LineMatrix{sections[0].p0}
+	for _, section := range sections {
+		if !matrix.Matrix(curve[len(curve)-1]).Equals(section.p0) {
+			curve = append(curve, section.p0)
+		}
+		curve = append(curve, section.p1)
+	}
+	return removeRepeatedPoints(curve)
+}
+
+// matchRawSegment returns the section of a segment of the buffer boundary,
+// oriented along the raw curve, if its midpoint lies on a segment of the raw curve.
+func matchRawSegment(p0, p1 matrix.Matrix, raw matrix.LineMatrix, tree *strtree.STRtree, tolerance float64) (offsetSection, bool) {
+	mid := matrix.Matrix{(p0[0] + p1[0]) / 2, (p0[1] + p1[1]) / 2}
+	env := envelope.Matrix(mid)
+	env.ExpandBy(tolerance)
+	candidates := []int{}
+	for _, item := range tree.Query(env).([]interface{}) {
+		candidates = append(candidates, item.(int))
+	}
+	sort.Ints(candidates)
+	for _, i := range candidates {
+		a, b := matrix.Matrix(raw[i]), matrix.Matrix(raw[i+1])
+		if measure.PlanarDistance(mid, matrix.LineMatrix{a, b}) > tolerance {
+			continue
+		}
+		section := offsetSection{
+			p0:   matrix.Matrix{p0[0], p0[1]},
+			p1:   matrix.Matrix{p1[0], p1[1]},
+			pos0: float64(i) + segmentFraction(p0, a, b),
+			pos1: float64(i) + segmentFraction(p1, a, b),
+		}
+		if section.pos1 < section.pos0 {
+			section.p0, section.p1 = section.p1, section.p0
+			section.pos0, section.pos1 = section.pos1, section.pos0
+		}
+		return section, true
+	}
+	return offsetSection{}, false
+}
+
+// segmentFraction returns the fraction of the projection of p along the segment ab, clamped to [0,1].
+func segmentFraction(p, a, b matrix.Matrix) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	fraction := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / (dx*dx + dy*dy)
+	return math.Max(0, math.Min(1, fraction))
+}
@@ -94,6 +94,13 @@ const (
 	// JoinBevel Specifies a bevel join style.
 	JoinBevel = 3
 
+	// MakeValidLinework Specifies the repair of polygons from their noded linework,
+	// the faces enclosed by an odd number of rings are kept.
+	MakeValidLinework = 1
+	// MakeValidStructure Specifies the repair of polygons from their structure,
+	// the shells less their holes are kept and the polygons are merged.
+	MakeValidStructure = 2
+
 	// QuadrantSegments The default number of facets into which to divide a fillet of 90 degrees.
 	// A value of 8 gives less than 2% max error in the buffer distance.
 	// For a max error of &lt; 1%, use QS = 12.
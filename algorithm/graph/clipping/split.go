@@ -0,0 +1,304 @@
+package clipping
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/overlay/chain"
+	"github.com/spatial-go/geoos/algorithm/polygonize"
+	"github.com/spatial-go/geoos/algorithm/relate"
+)
+
+// Split Splits a geometry by a blade geometry.
+// A line is split at the points of the blade on it, and at its intersections with the lines of the blade
+// or the boundaries of its polygons, only at the ends where it runs along them, the parts are in the direction of the line.
+// A polygon is split by the lines of the blade, or the boundaries of its polygons,
+// the rings of the polygon and the lines are noded and polygonized, the parts are the faces inside the polygon.
+// The components of a Collection are split one by one.
+// Returns a Collection of the parts, the geometry itself if the blade does not split it.
+func Split(m, blade matrix.Steric) (matrix.Collection, error) {
+	if m == nil || blade == nil {
+		return nil, algorithm.ErrNilSteric
+	}
+	points, lines := bladeComponents(blade, nil, nil)
+	switch mType := m.(type) {
+	case matrix.LineMatrix:
+		return splitLine(mType, points, lines), nil
+	case matrix.PolygonMatrix:
+		return splitPolygon(mType, lines)
+	case matrix.MultiPolygonMatrix:
+		parts := matrix.Collection{}
+		for _, poly := range mType {
+			split, err := splitPolygon(poly, lines)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, split...)
+		}
+		return parts, nil
+	case matrix.Collection:
+		parts := matrix.Collection{}
+		for _, v := range mType {
+			split, err := Split(v, blade)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, split...)
+		}
+		return parts, nil
+	}
+	return nil, algorithm.ErrNotMatchType
+}
+
+// bladeComponents appends the points and the lines of the blade, the rings of its polygons are lines.
+func bladeComponents(blade matrix.Steric, points []matrix.Matrix, lines []matrix.LineMatrix) ([]matrix.Matrix, []matrix.LineMatrix) {
+	switch b := blade.(type) {
+	case matrix.Matrix:
+		points = append(points, b)
+	case matrix.LineMatrix:
+		if len(b) > 1 {
+			lines = append(lines, b)
+		}
+	case matrix.PolygonMatrix:
+		for _, ring := range b {
+			lines = append(lines, ring)
+		}
+	case matrix.MultiPolygonMatrix:
+		for _, poly := range b {
+			points, lines = bladeComponents(matrix.PolygonMatrix(poly), points, lines)
+		}
+	case matrix.Collection:
+		for _, v := range b {
+			points, lines = bladeComponents(v, points, lines)
+		}
+	}
+	return points, lines
+}
+
+// splitNode a point where a line is split, on its segment of index seg at the fraction along it.
+type splitNode struct {
+	seg      int
+	fraction float64
+	point    matrix.Matrix
+}
+
+// splitLine returns the parts of the line split at the points and at its intersections with the lines.
+func splitLine(line matrix.LineMatrix, points []matrix.Matrix, lines []matrix.LineMatrix) matrix.Collection {
+	if len(line) < 2 {
+		return matrix.Collection{line}
+	}
+	nodes := []splitNode{}
+	for _, p := range points {
+		for i := 1; i < len(line); i++ {
+			if in, _ := relate.InLine(p, line[i-1], line[i]); in {
+				nodes = append(nodes, newSplitNode(line, i-1, p))
+			}
+		}
+	}
+	for _, other := range lines {
+		for _, node := range intersectionNodes(line, other) {
+			if !insideOverlap(line, node, lines) {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	parts := matrix.Collection{}
+	for _, part := range splitAtNodes(line, nodes) {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// insideOverlap returns true if the line runs along the lines on both sides of the node,
+// the node is then inside an overlap of the line and the lines and does not split the line.
+func insideOverlap(line matrix.LineMatrix, node splitNode, lines []matrix.LineMatrix) bool {
+	before, after := node.seg, node.seg+1
+	if node.point.EqualsExact(matrix.Matrix(line[before]), calc.DefaultTolerance) {
+		before--
+	}
+	if node.point.EqualsExact(matrix.Matrix(line[after]), calc.DefaultTolerance) {
+		after++
+	}
+	if before < 0 || after >= len(line) {
+		return false
+	}
+	return runsAlong(node.point, line[before], lines) && runsAlong(node.point, line[after], lines)
+}
+
+// runsAlong returns true if the segment from p towards q overlaps a segment of the lines beyond p.
+func runsAlong(p, q matrix.Matrix, lines []matrix.LineMatrix) bool {
+	if p.EqualsExact(q, calc.DefaultTolerance) {
+		return false
+	}
+	for _, other := range lines {
+		for i := 1; i < len(other); i++ {
+			a, b := matrix.Matrix(other[i-1]), matrix.Matrix(other[i])
+			if in, _ := relate.InLine(p, a, b); !in {
+				continue
+			}
+			if in, _ := relate.InLine(q, a, b); in {
+				return true
+			}
+			for _, end := range []matrix.Matrix{a, b} {
+				if in, _ := relate.InLine(end, p, q); in && !end.EqualsExact(p, calc.DefaultTolerance) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// splitPolygon returns the faces inside the polygon of its rings and the lines, noded with each other.
+func splitPolygon(poly matrix.PolygonMatrix, lines []matrix.LineMatrix) (matrix.Collection, error) {
+	if len(lines) == 0 {
+		return matrix.Collection{poly}, nil
+	}
+	rings := make([]matrix.LineMatrix, 0, len(poly))
+	for _, ring := range poly {
+		rings = append(rings, ring)
+	}
+	edges := append(append([]matrix.LineMatrix{}, rings...), lines...)
+	nodes := make([][]splitNode, len(edges))
+	for i := range edges {
+		for j := i + 1; j < len(edges); j++ {
+			n0, n1 := mutualIntersectionNodes(edges[i], edges[j])
+			nodes[i], nodes[j] = append(nodes[i], n0...), append(nodes[j], n1...)
+		}
+	}
+	noded := matrix.Collection{}
+	for i, edge := range edges {
+		for _, part := range splitAtNodes(edge, nodes[i]) {
+			noded = append(noded, part)
+		}
+	}
+	result, err := polygonize.Polygonize(noded)
+	if err != nil {
+		return nil, err
+	}
+	parts := matrix.Collection{}
+	for _, face := range result.Polygons {
+		if p := interiorPoint(face); p != nil && relate.LocateInRings(p, rings...) == calc.ImInterior {
+			parts = append(parts, face)
+		}
+	}
+	if len(parts) <= 1 {
+		return matrix.Collection{poly}, nil
+	}
+	return parts, nil
+}
+
+// interiorPoint returns a point in the interior of a polygon, the middle of its widest section
+// by a horizontal line between the vertices nearest to the middle of its height.
+func interiorPoint(poly matrix.PolygonMatrix) matrix.Matrix {
+	if len(poly) == 0 || len(poly[0]) < 4 {
+		return nil
+	}
+	minY, maxY := poly[0][0][1], poly[0][0][1]
+	for _, p := range poly[0] {
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+	center := (minY + maxY) / 2
+	below, above := minY, maxY
+	for _, ring := range poly {
+		for _, p := range ring {
+			if p[1] <= center && p[1] > below {
+				below = p[1]
+			}
+			if p[1] > center && p[1] < above {
+				above = p[1]
+			}
+		}
+	}
+	y := (below + above) / 2
+	xs := []float64{}
+	for _, ring := range poly {
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			if (a[1] > y) != (b[1] > y) {
+				xs = append(xs, a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	var interior matrix.Matrix
+	width := 0.0
+	for i := 1; i < len(xs); i += 2 {
+		if w := xs[i] - xs[i-1]; w > width {
+			width, interior = w, matrix.Matrix{(xs[i] + xs[i-1]) / 2, y}
+		}
+	}
+	return interior
+}
+
+// intersectionNodes returns the nodes of the line at its intersections with the other line.
+func intersectionNodes(line, other matrix.LineMatrix) []splitNode {
+	nodes, _ := mutualIntersectionNodes(line, other)
+	return nodes
+}
+
+// mutualIntersectionNodes returns the nodes of two lines at their intersections,
+// found by the monotone chains of their segments.
+func mutualIntersectionNodes(line, other matrix.LineMatrix) ([]splitNode, []splitNode) {
+	smi := &chain.SegmentMutualIntersector{SegmentMutual: line}
+	icd := &chain.IntersectionCorrelation{Edge: line, Edge1: other}
+	smi.Process(other, icd)
+	result, ok := icd.Result().([]chain.IntersectionNodeOfLine)
+	if !ok {
+		return nil, nil
+	}
+	nodes := [2][]splitNode{}
+	for k, edge := range []matrix.LineMatrix{line, other} {
+		for _, r := range result[k] {
+			if r.Pos < len(edge)-1 {
+				nodes[k] = append(nodes[k], newSplitNode(edge, r.Pos, r.InterNode.Matrix))
+			}
+		}
+	}
+	return nodes[0], nodes[1]
+}
+
+func newSplitNode(line matrix.LineMatrix, seg int, p matrix.Matrix) splitNode {
+	a, b := line[seg], line[seg+1]
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	fraction := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		fraction = ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / length
+	}
+	return splitNode{seg: seg, fraction: fraction, point: matrix.Matrix{p[0], p[1]}}
+}
+
+// splitAtNodes returns the parts of the line between the nodes.
+func splitAtNodes(line matrix.LineMatrix, nodes []splitNode) []matrix.LineMatrix {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].seg != nodes[j].seg {
+			return nodes[i].seg < nodes[j].seg
+		}
+		return nodes[i].fraction < nodes[j].fraction
+	})
+	parts := []matrix.LineMatrix{}
+	part := matrix.LineMatrix{line[0]}
+	extend := func(p matrix.Matrix) {
+		if !matrix.Matrix(part[len(part)-1]).EqualsExact(p, calc.DefaultTolerance) {
+			part = append(part, p)
+		}
+	}
+	k := 0
+	for i := 1; i < len(line); i++ {
+		for ; k < len(nodes) && nodes[k].seg == i-1; k++ {
+			extend(nodes[k].point)
+			if len(part) > 1 {
+				parts = append(parts, part)
+				part = matrix.LineMatrix{nodes[k].point}
+			}
+		}
+		extend(line[i])
+	}
+	if len(part) > 1 {
+		parts = append(parts, part)
+	}
+	return parts
+}
@@ -0,0 +1,76 @@
+package clipping
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+func TestSplit(t *testing.T) {
+	square := matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}}}
+	tests := []struct {
+		name    string
+		m       matrix.Steric
+		blade   matrix.Steric
+		want    matrix.Collection
+		wantErr error
+	}{
+		{name: "line by line", m: matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}}, blade: matrix.LineMatrix{{5, -5}, {5, 5}, {15, 5}},
+			want: matrix.Collection{matrix.LineMatrix{{0, 0}, {5, 0}}, matrix.LineMatrix{{5, 0}, {10, 0}, {10, 5}},
+				matrix.LineMatrix{{10, 5}, {10, 10}}}},
+		{name: "line by points", m: matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}},
+			blade: matrix.Collection{matrix.Matrix{5, 0}, matrix.Matrix{10, 0}, matrix.Matrix{3, 3}},
+			want:  matrix.Collection{matrix.LineMatrix{{0, 0}, {5, 0}}, matrix.LineMatrix{{5, 0}, {10, 0}}, matrix.LineMatrix{{10, 0}, {10, 10}}}},
+		{name: "line by overlapping line", m: matrix.LineMatrix{{0, 0}, {10, 0}}, blade: matrix.LineMatrix{{2, 0}, {4, 0}},
+			want: matrix.Collection{matrix.LineMatrix{{0, 0}, {2, 0}}, matrix.LineMatrix{{2, 0}, {4, 0}}, matrix.LineMatrix{{4, 0}, {10, 0}}}},
+		{name: "line along polygon edge", m: matrix.LineMatrix{{0, 0}, {5, 0}, {10, 0}},
+			blade: matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			want:  matrix.Collection{matrix.LineMatrix{{0, 0}, {5, 0}, {10, 0}}}},
+		{name: "line partly along polygon edge", m: matrix.LineMatrix{{-5, 0}, {5, 0}, {15, 0}},
+			blade: matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			want: matrix.Collection{matrix.LineMatrix{{-5, 0}, {0, 0}}, matrix.LineMatrix{{0, 0}, {5, 0}, {10, 0}},
+				matrix.LineMatrix{{10, 0}, {15, 0}}}},
+		{name: "line along line through vertex", m: matrix.LineMatrix{{0, 0}, {10, 0}}, blade: matrix.LineMatrix{{-5, 0}, {5, 0}, {15, 0}},
+			want: matrix.Collection{matrix.LineMatrix{{0, 0}, {10, 0}}}},
+		{name: "line along line bending at vertex", m: matrix.LineMatrix{{0, 0}, {5, 0}, {5, 5}}, blade: matrix.LineMatrix{{-5, 0}, {5, 0}, {5, 10}},
+			want: matrix.Collection{matrix.LineMatrix{{0, 0}, {5, 0}, {5, 5}}}},
+		{name: "line leaving line at vertex", m: matrix.LineMatrix{{0, 0}, {5, 0}, {5, 5}}, blade: matrix.LineMatrix{{-5, 0}, {10, 0}},
+			want: matrix.Collection{matrix.LineMatrix{{0, 0}, {5, 0}}, matrix.LineMatrix{{5, 0}, {5, 5}}}},
+		{name: "line by polygon", m: matrix.LineMatrix{{-5, 5}, {15, 5}}, blade: square,
+			want: matrix.Collection{matrix.LineMatrix{{-5, 5}, {0, 5}}, matrix.LineMatrix{{0, 5}, {4, 5}}, matrix.LineMatrix{{4, 5}, {6, 5}},
+				matrix.LineMatrix{{6, 5}, {10, 5}}, matrix.LineMatrix{{10, 5}, {15, 5}}}},
+		{name: "polygon by line", m: square, blade: matrix.LineMatrix{{-1, 2}, {11, 2}},
+			want: matrix.Collection{matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 2}, {0, 2}, {0, 0}}},
+				matrix.PolygonMatrix{{{10, 2}, {10, 10}, {0, 10}, {0, 2}, {10, 2}}, {{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}}}}},
+		{name: "polygon by line through hole", m: square, blade: matrix.LineMatrix{{5, -5}, {5, 15}},
+			want: matrix.Collection{matrix.PolygonMatrix{{{0, 0}, {5, 0}, {5, 4}, {4, 4}, {4, 6}, {5, 6}, {5, 10}, {0, 10}, {0, 0}}},
+				matrix.PolygonMatrix{{{5, 0}, {10, 0}, {10, 10}, {5, 10}, {5, 6}, {6, 6}, {6, 4}, {5, 4}, {5, 0}}}}},
+		{name: "polygon by diagonal", m: square, blade: matrix.LineMatrix{{0, 0}, {10, 10}},
+			want: matrix.Collection{matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {6, 6}, {6, 4}, {4, 4}, {0, 0}}},
+				matrix.PolygonMatrix{{{10, 10}, {0, 10}, {0, 0}, {4, 4}, {4, 6}, {6, 6}, {10, 10}}}}},
+		{name: "polygon by crossing lines", m: square,
+			blade: matrix.Collection{matrix.LineMatrix{{5, -5}, {5, 15}}, matrix.LineMatrix{{-5, 5}, {15, 5}}},
+			want: matrix.Collection{matrix.PolygonMatrix{{{0, 0}, {5, 0}, {5, 4}, {4, 4}, {4, 5}, {0, 5}, {0, 0}}},
+				matrix.PolygonMatrix{{{5, 0}, {10, 0}, {10, 5}, {6, 5}, {6, 4}, {5, 4}, {5, 0}}},
+				matrix.PolygonMatrix{{{10, 5}, {10, 10}, {5, 10}, {5, 6}, {6, 6}, {6, 5}, {10, 5}}},
+				matrix.PolygonMatrix{{{5, 10}, {0, 10}, {0, 5}, {4, 5}, {4, 6}, {5, 6}, {5, 10}}}}},
+		{name: "polygon by disjoint line", m: square, blade: matrix.LineMatrix{{20, 20}, {30, 30}}, want: matrix.Collection{square}},
+		{name: "polygon by line not across", m: square, blade: matrix.LineMatrix{{2, -1}, {2, 5}}, want: matrix.Collection{square}},
+		{name: "polygon by point", m: square, blade: matrix.Matrix{2, 2}, want: matrix.Collection{square}},
+		{name: "point", m: matrix.Matrix{1, 1}, blade: matrix.LineMatrix{{0, 0}, {2, 2}}, wantErr: algorithm.ErrNotMatchType},
+		{name: "nil", m: square, wantErr: algorithm.ErrNilSteric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.m, tt.blade)
+			if err != tt.wantErr {
+				t.Fatalf("Split() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
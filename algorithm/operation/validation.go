@@ -0,0 +1,532 @@
+package operation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/buffer"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/relate"
+	"github.com/spatial-go/geoos/index/strtree"
+)
+
+// InvalidReason the reason a geometry is invalid.
+type InvalidReason int
+
+// Reasons of invalidity.
+const (
+	// InvalidCoordinate a coordinate is NaN or infinite, or has less than two ordinates.
+	InvalidCoordinate InvalidReason = iota + 1
+	// TooFewPoints a line has less than two distinct points, or a ring less than three.
+	TooFewPoints
+	// RingNotClosed the first and last points of a ring differ.
+	RingNotClosed
+	// SelfIntersection a ring intersects or touches itself, the rings of a polygon cross or overlap,
+	// or the polygons of a multipolygon cross or overlap.
+	SelfIntersection
+	// HoleOutsideShell a hole of a polygon is outside its shell.
+	HoleOutsideShell
+	// NestedHoles a hole of a polygon is inside another one.
+	NestedHoles
+	// NestedShells a polygon of a multipolygon is inside another one.
+	NestedShells
+	// DisconnectedInterior the rings of a polygon touch so that its interior is split.
+	DisconnectedInterior
+)
+
+// String returns the name of the reason.
+func (r InvalidReason) String() string {
+	switch r {
+	case InvalidCoordinate:
+		return "invalid coordinate"
+	case TooFewPoints:
+		return "too few points"
+	case RingNotClosed:
+		return "ring not closed"
+	case SelfIntersection:
+		return "self-intersection"
+	case HoleOutsideShell:
+		return "hole outside shell"
+	case NestedHoles:
+		return "nested holes"
+	case NestedShells:
+		return "nested shells"
+	case DisconnectedInterior:
+		return "disconnected interior"
+	}
+	return "unknown"
+}
+
+// ValidationResult An invalidity of a geometry, its reason and the coordinate where it is found.
+type ValidationResult struct {
+	Reason   InvalidReason
+	Location matrix.Matrix
+}
+
+// String returns the reason and the location of the invalidity.
+func (v ValidationResult) String() string {
+	if len(v.Location) < 2 {
+		return v.Reason.String()
+	}
+	return fmt.Sprintf("%v at %v %v", v.Reason, v.Location[0], v.Location[1])
+}
+
+// Validate Computes the validity of the geometry, with the OGC rules for polygons,
+// the rings are closed and simple, the holes are inside the shell and disjoint from each other,
+// the rings touch at single points without splitting the interior, and the polygons of a multipolygon
+// touch at points only. The empty geometries are valid.
+// The components of a geometry are validated in order, then the interactions between its polygons.
+// Returns the first invalidity found, nil if the geometry is valid.
+func (el *ValidOP) Validate() *ValidationResult {
+	v := &validator{}
+	v.validate(el.Steric)
+	if len(v.results) == 0 {
+		return nil
+	}
+	return &v.results[0]
+}
+
+// ValidateAll Computes the validity of the geometry as Validate.
+// Returns all the invalidities found, none if the geometry is valid.
+func (el *ValidOP) ValidateAll() []ValidationResult {
+	v := &validator{all: true}
+	v.validate(el.Steric)
+	return v.results
+}
+
+// validator collects the invalidities of a geometry, it stops at the first one unless all are wanted.
+type validator struct {
+	all     bool
+	results []ValidationResult
+}
+
+func (v *validator) report(reason InvalidReason, location matrix.Matrix) {
+	v.results = append(v.results, ValidationResult{Reason: reason, Location: matrix.Matrix{location[0], location[1]}})
+}
+
+// done returns true if the validation stops.
+func (v *validator) done() bool {
+	return !v.all && len(v.results) > 0
+}
+
+func (v *validator) validate(steric matrix.Steric) {
+	switch m := steric.(type) {
+	case matrix.Matrix:
+		v.validCoordinates([]matrix.Matrix{m})
+	case matrix.LineMatrix:
+		v.validateLine(m)
+	case matrix.PolygonMatrix:
+		v.validPolygon(m)
+	case matrix.MultiPolygonMatrix:
+		valid := [][]*indexedRing{}
+		for _, poly := range m {
+			if v.done() {
+				return
+			}
+			if rings, ok := v.validPolygon(poly); ok && len(rings) > 0 {
+				valid = append(valid, rings)
+			}
+		}
+		v.validatePolygonPairs(valid)
+	case matrix.Collection:
+		valid := [][]*indexedRing{}
+		for _, g := range m {
+			if v.done() {
+				return
+			}
+			// the polygons of a Collection are the ones of a multipolygon, as a MultiPolygon is converted to a Collection.
+			if poly, ok := g.(matrix.PolygonMatrix); ok {
+				if rings, ok := v.validPolygon(poly); ok && len(rings) > 0 {
+					valid = append(valid, rings)
+				}
+			} else {
+				v.validate(g)
+			}
+		}
+		v.validatePolygonPairs(valid)
+	}
+}
+
+// validCoordinates reports the invalid coordinates, returns true if there is none.
+func (v *validator) validCoordinates(pts []matrix.Matrix) bool {
+	valid := true
+	for _, p := range pts {
+		if len(p) < 2 || math.IsNaN(p[0]) || math.IsNaN(p[1]) || math.IsInf(p[0], 0) || math.IsInf(p[1], 0) {
+			location := matrix.Matrix{math.NaN(), math.NaN()}
+			if len(p) >= 2 {
+				location = p
+			}
+			v.report(InvalidCoordinate, location)
+			valid = false
+			if v.done() {
+				return false
+			}
+		}
+	}
+	return valid
+}
+
+func (v *validator) validateLine(line matrix.LineMatrix) {
+	if len(line) == 0 {
+		return
+	}
+	if !v.validCoordinates(toMatrixes(line)) {
+		return
+	}
+	if len(removeRepeatedPoints(line)) < 2 {
+		v.report(TooFewPoints, line[0])
+	}
+}
+
+// validRing reports the invalidities of a ring alone, returns the ring without repeated points if it is valid.
+func (v *validator) validRing(ring matrix.LineMatrix) (*indexedRing, bool) {
+	if !v.validCoordinates(toMatrixes(ring)) {
+		return nil, false
+	}
+	ring = removeRepeatedPoints(ring)
+	closed := matrix.Matrix(ring[0]).Equals(matrix.Matrix(ring[len(ring)-1]))
+	if distinct := len(ring); (closed && distinct < 4) || (!closed && distinct < 3) {
+		v.report(TooFewPoints, ring[0])
+		return nil, false
+	}
+	if !closed {
+		v.report(RingNotClosed, ring[0])
+		return nil, false
+	}
+	indexed := newIndexedRing(ring)
+	valid, seen := true, map[[2]float64]bool{}
+	n := len(ring) - 1
+	for i := 0; i < n && !v.done(); i++ {
+		for _, j := range indexed.candidates(ring[i], ring[i+1]) {
+			if j <= i || v.done() {
+				continue
+			}
+			kind, p := segmentIntersection(ring[i], ring[i+1], ring[j], ring[j+1])
+			// the adjacent segments share a vertex, they may not overlap.
+			if j == i+1 || (i == 0 && j == n-1) {
+				if kind != collinearIntersection {
+					continue
+				}
+				p = ring[j]
+				if i == 0 && j == n-1 {
+					p = ring[0]
+				}
+			}
+			if kind == noIntersection {
+				continue
+			}
+			if key := [2]float64{p[0], p[1]}; !seen[key] {
+				seen[key] = true
+				v.report(SelfIntersection, p)
+				valid = false
+			}
+		}
+	}
+	return indexed, valid
+}
+
+// validatePolygonPairs reports the invalidities of the interactions of valid polygons, as the polygons of a multipolygon.
+func (v *validator) validatePolygonPairs(valid [][]*indexedRing) {
+	for i := 0; i < len(valid); i++ {
+		for j := i + 1; j < len(valid); j++ {
+			if v.done() {
+				return
+			}
+			v.validatePolygonPair(valid[i], valid[j])
+		}
+	}
+}
+
+// validPolygon reports the invalidities of a polygon, returns its rings without repeated points if it is valid.
+func (v *validator) validPolygon(poly matrix.PolygonMatrix) ([]*indexedRing, bool) {
+	if poly.IsEmpty() || len(poly[0]) == 0 {
+		return nil, true
+	}
+	rings := make([]*indexedRing, 0, len(poly))
+	valid := true
+	for _, ring := range poly {
+		if v.done() {
+			return nil, false
+		}
+		if len(ring) == 0 {
+			continue
+		}
+		r, ok := v.validRing(ring)
+		valid = valid && ok
+		rings = append(rings, r)
+	}
+	if !valid {
+		return nil, false
+	}
+	// the rings meet at touch points, the interior is split if the rings and their touch points form a cycle.
+	// each touch point is one node, linked once to each ring through it.
+	parent := map[interface{}]interface{}{}
+	type ringTouch struct {
+		ring  int
+		touch [2]float64
+	}
+	linked := map[ringTouch]bool{}
+	var find func(x interface{}) interface{}
+	find = func(x interface{}) interface{} {
+		if p, ok := parent[x]; ok && p != x {
+			root := find(p)
+			parent[x] = root
+			return root
+		}
+		return x
+	}
+	for i := 0; i < len(rings); i++ {
+		for j := i + 1; j < len(rings); j++ {
+			touches, ok := v.ringInteraction(rings[i], rings[j])
+			if !ok {
+				valid = false
+				if v.done() {
+					return nil, false
+				}
+				continue
+			}
+			for _, p := range touches {
+				key := [2]float64{p[0], p[1]}
+				for _, ring := range []int{i, j} {
+					if linked[ringTouch{ring, key}] {
+						continue
+					}
+					linked[ringTouch{ring, key}] = true
+					a, b := find(ring), find(key)
+					if a == b {
+						v.report(DisconnectedInterior, p)
+						valid = false
+						if v.done() {
+							return nil, false
+						}
+					}
+					parent[a] = b
+				}
+			}
+		}
+	}
+	if !valid {
+		return nil, false
+	}
+	shell := rings[0].LineMatrix
+	for i := 1; i < len(rings); i++ {
+		hole := rings[i].LineMatrix
+		if p := interiorVertex(hole, shell); p != nil && relate.LocateInRings(p, shell) == calc.ImExterior {
+			v.report(HoleOutsideShell, p)
+			valid = false
+		}
+		for j := 1; j < len(rings) && !v.done(); j++ {
+			if i == j || !rings[i].env.IsIntersects(rings[j].env) {
+				continue
+			}
+			if p := interiorVertex(hole, rings[j].LineMatrix); p != nil && relate.LocateInRings(p, rings[j].LineMatrix) == calc.ImInterior {
+				v.report(NestedHoles, p)
+				valid = false
+			}
+		}
+		if v.done() {
+			return nil, false
+		}
+	}
+	return rings, valid
+}
+
+// validatePolygonPair reports the invalidities of two valid polygons of a multipolygon.
+func (v *validator) validatePolygonPair(poly, other []*indexedRing) {
+	// the polygons are disjoint if their shells are.
+	if !poly[0].env.IsIntersects(other[0].env) {
+		return
+	}
+	for _, ring := range poly {
+		for _, otherRing := range other {
+			if _, ok := v.ringInteraction(ring, otherRing); !ok {
+				return
+			}
+		}
+	}
+	polyRings, otherRings := lines(poly), lines(other)
+	if p := interiorVertex(polyRings[0], otherRings...); p != nil && relate.LocateInRings(p, otherRings...) == calc.ImInterior {
+		v.report(NestedShells, p)
+		return
+	}
+	if p := interiorVertex(otherRings[0], polyRings...); p != nil && relate.LocateInRings(p, polyRings...) == calc.ImInterior {
+		v.report(NestedShells, p)
+	}
+}
+
+// ringInteraction reports the crossings and the overlaps of two distinct rings,
+// returns the points where they touch, and false if they cross or overlap.
+func (v *validator) ringInteraction(indexed, otherIndexed *indexedRing) ([]matrix.Matrix, bool) {
+	touches := []matrix.Matrix{}
+	seen := map[[2]float64]bool{}
+	if !indexed.env.IsIntersects(otherIndexed.env) {
+		return touches, true
+	}
+	ring, other := indexed.LineMatrix, otherIndexed.LineMatrix
+	for i := 1; i < len(ring); i++ {
+		for _, j := range otherIndexed.candidates(ring[i-1], ring[i]) {
+			switch kind, p := segmentIntersection(ring[i-1], ring[i], other[j], other[j+1]); kind {
+			case touchIntersection:
+				if key := [2]float64{p[0], p[1]}; !seen[key] {
+					seen[key] = true
+					touches = append(touches, p)
+				}
+			case properIntersection, collinearIntersection:
+				v.report(SelfIntersection, p)
+				return nil, false
+			}
+		}
+	}
+	return touches, true
+}
+
+// indexedRing a ring, its envelope and the STRtree of its segments,
+// to find the segments which may intersect a segment without testing all of them.
+type indexedRing struct {
+	matrix.LineMatrix
+	env      *envelope.Envelope
+	segments *strtree.STRtree
+}
+
+func newIndexedRing(ring matrix.LineMatrix) *indexedRing {
+	r := &indexedRing{LineMatrix: ring, env: envelope.MatrixList(toMatrixes(ring)), segments: strtree.NewSTRtree()}
+	for i := 1; i < len(ring); i++ {
+		_ = r.segments.Insert(envelope.TwoMatrix(ring[i-1], ring[i]), i-1)
+	}
+	return r
+}
+
+// candidates returns in increasing order the indexes of the segments whose envelopes intersect the one of segment ab.
+func (r *indexedRing) candidates(a, b matrix.Matrix) []int {
+	visitor := &segmentVisitor{}
+	_ = r.segments.QueryVisitor(envelope.TwoMatrix(a, b), visitor)
+	sort.Ints(visitor.indexes)
+	return visitor.indexes
+}
+
+// segmentVisitor collects the indexes of the segments visited.
+type segmentVisitor struct {
+	indexes []int
+}
+
+// VisitItem collects the index of a segment.
+func (s *segmentVisitor) VisitItem(item interface{}) {
+	s.indexes = append(s.indexes, item.(int))
+}
+
+// Items returns the indexes of the segments visited.
+func (s *segmentVisitor) Items() interface{} {
+	return s.indexes
+}
+
+// lines returns the rings of the indexed rings.
+func lines(rings []*indexedRing) []matrix.LineMatrix {
+	result := make([]matrix.LineMatrix, len(rings))
+	for i, r := range rings {
+		result[i] = r.LineMatrix
+	}
+	return result
+}
+
+// interiorVertex returns a vertex of the ring which is not on the other rings, nil if there is none.
+func interiorVertex(ring matrix.LineMatrix, others ...matrix.LineMatrix) matrix.Matrix {
+	for _, p := range ring {
+		if relate.LocateInRings(p, others...) != calc.ImBoundary {
+			return p
+		}
+	}
+	return nil
+}
+
+// The kinds of the intersection of two segments.
+const (
+	noIntersection = iota
+	// touchIntersection the segments meet at a single point, an end point of one of them.
+	touchIntersection
+	// properIntersection the segments cross at a single point interior to both of them.
+	properIntersection
+	// collinearIntersection the segments overlap along a segment.
+	collinearIntersection
+)
+
+// segmentIntersection returns the kind of the intersection of the segments a0a1 and b0b1, and a point of it.
+func segmentIntersection(a0, a1, b0, b1 matrix.Matrix) (int, matrix.Matrix) {
+	if !envelope.IsIntersectsTwo(a0, a1, b0, b1) {
+		return noIntersection, nil
+	}
+	ob0, ob1 := buffer.OrientationIndex(a0, a1, b0), buffer.OrientationIndex(a0, a1, b1)
+	oa0, oa1 := buffer.OrientationIndex(b0, b1, a0), buffer.OrientationIndex(b0, b1, a1)
+	if ob0 == 0 && ob1 == 0 {
+		return collinearSegmentIntersection(a0, a1, b0, b1)
+	}
+	if ob0*ob1 > 0 || oa0*oa1 > 0 {
+		return noIntersection, nil
+	}
+	switch {
+	case ob0 == 0:
+		return touchIntersection, b0
+	case ob1 == 0:
+		return touchIntersection, b1
+	case oa0 == 0:
+		return touchIntersection, a0
+	case oa1 == 0:
+		return touchIntersection, a1
+	}
+	// the intersection point of the lines of the segments.
+	d := (a1[0]-a0[0])*(b1[1]-b0[1]) - (a1[1]-a0[1])*(b1[0]-b0[0])
+	t := ((b0[0]-a0[0])*(b1[1]-b0[1]) - (b0[1]-a0[1])*(b1[0]-b0[0])) / d
+	return properIntersection, matrix.Matrix{a0[0] + t*(a1[0]-a0[0]), a0[1] + t*(a1[1]-a0[1])}
+}
+
+// collinearSegmentIntersection returns the kind of the intersection of two collinear segments, and a point of it.
+func collinearSegmentIntersection(a0, a1, b0, b1 matrix.Matrix) (int, matrix.Matrix) {
+	axis := 0
+	if math.Abs(a1[1]-a0[1]) > math.Abs(a1[0]-a0[0]) {
+		axis = 1
+	}
+	aMin, aMax := a0, a1
+	if aMin[axis] > aMax[axis] {
+		aMin, aMax = aMax, aMin
+	}
+	bMin, bMax := b0, b1
+	if bMin[axis] > bMax[axis] {
+		bMin, bMax = bMax, bMin
+	}
+	start, end := aMin, aMax
+	if bMin[axis] > start[axis] {
+		start = bMin
+	}
+	if bMax[axis] < end[axis] {
+		end = bMax
+	}
+	switch {
+	case start[axis] > end[axis]:
+		return noIntersection, nil
+	case start[axis] == end[axis]:
+		return touchIntersection, start
+	}
+	return collinearIntersection, start
+}
+
+// removeRepeatedPoints returns the line without the consecutive repeated points.
+func removeRepeatedPoints(line matrix.LineMatrix) matrix.LineMatrix {
+	result := matrix.LineMatrix{}
+	for _, p := range line {
+		if len(result) == 0 || !matrix.Matrix(result[len(result)-1]).Equals(matrix.Matrix(p)) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// toMatrixes returns the points of a line.
+func toMatrixes(line matrix.LineMatrix) []matrix.Matrix {
+	pts := make([]matrix.Matrix, 0, len(line))
+	for _, p := range line {
+		pts = append(pts, p)
+	}
+	return pts
+}
@@ -0,0 +1,122 @@
+package operation
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+func TestValidOP_Validate(t *testing.T) {
+	square := matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	tests := []struct {
+		name   string
+		steric matrix.Steric
+		want   []ValidationResult
+	}{
+		{name: "valid polygon", steric: matrix.PolygonMatrix{square, {{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}}},
+		{name: "hole touching shell", steric: matrix.PolygonMatrix{square, {{0, 5}, {5, 8}, {5, 2}, {0, 5}}}},
+		{name: "empty polygon", steric: matrix.PolygonMatrix{}},
+		{name: "valid line", steric: matrix.LineMatrix{{0, 0}, {1, 1}, {0, 1}, {1, 0}}},
+		{name: "invalid coordinate", steric: matrix.LineMatrix{{0, 0}, {math.Inf(1), 1}},
+			want: []ValidationResult{{InvalidCoordinate, matrix.Matrix{math.Inf(1), 1}}}},
+		{name: "too few points line", steric: matrix.LineMatrix{{1, 1}, {1, 1}},
+			want: []ValidationResult{{TooFewPoints, matrix.Matrix{1, 1}}}},
+		{name: "too few points ring", steric: matrix.PolygonMatrix{{{0, 0}, {1, 1}, {0, 0}}},
+			want: []ValidationResult{{TooFewPoints, matrix.Matrix{0, 0}}}},
+		{name: "ring not closed", steric: matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			want: []ValidationResult{{RingNotClosed, matrix.Matrix{0, 0}}}},
+		{name: "bow-tie", steric: matrix.PolygonMatrix{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+			want: []ValidationResult{{SelfIntersection, matrix.Matrix{5, 5}}}},
+		{name: "self-touching shell", steric: matrix.PolygonMatrix{{{0, 0}, {10, 0}, {5, 5}, {10, 10}, {0, 10}, {5, 5}, {0, 0}}},
+			want: []ValidationResult{{SelfIntersection, matrix.Matrix{5, 5}}}},
+		{name: "spike", steric: matrix.PolygonMatrix{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {10, 5}, {10, 10}, {0, 10}, {0, 0}}},
+			want: []ValidationResult{{SelfIntersection, matrix.Matrix{10, 5}}, {SelfIntersection, matrix.Matrix{15, 5}}}},
+		{name: "hole crossing shell", steric: matrix.PolygonMatrix{square, {{5, 5}, {15, 5}, {15, 8}, {5, 8}, {5, 5}}},
+			want: []ValidationResult{{SelfIntersection, matrix.Matrix{10, 5}}}},
+		{name: "hole outside shell", steric: matrix.PolygonMatrix{square, {{20, 20}, {20, 22}, {22, 22}, {22, 20}, {20, 20}}},
+			want: []ValidationResult{{HoleOutsideShell, matrix.Matrix{20, 20}}}},
+		{name: "nested holes", steric: matrix.PolygonMatrix{square, {{1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}},
+			{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}},
+			want: []ValidationResult{{NestedHoles, matrix.Matrix{2, 2}}}},
+		{name: "disconnected interior", steric: matrix.PolygonMatrix{square, {{0, 5}, {5, 10}, {10, 5}, {5, 0}, {0, 5}}},
+			want: []ValidationResult{{DisconnectedInterior, matrix.Matrix{10, 5}}, {DisconnectedInterior, matrix.Matrix{5, 10}},
+				{DisconnectedInterior, matrix.Matrix{0, 5}}}},
+		{name: "disconnected interior by holes", steric: matrix.PolygonMatrix{square,
+			{{0, 5}, {5, 7}, {5, 3}, {0, 5}}, {{5, 7}, {10, 5}, {5, 3}, {7, 5}, {5, 7}}},
+			want: []ValidationResult{{DisconnectedInterior, matrix.Matrix{5, 7}}, {DisconnectedInterior, matrix.Matrix{5, 3}}}},
+		{name: "holes touching shell at one point", steric: matrix.PolygonMatrix{square,
+			{{5, 0}, {2, 2}, {4, 3}, {5, 0}}, {{5, 0}, {6, 3}, {8, 2}, {5, 0}}}},
+		{name: "holes touching at one point", steric: matrix.PolygonMatrix{square,
+			{{5, 5}, {2, 4}, {2, 6}, {5, 5}}, {{5, 5}, {8, 4}, {8, 6}, {5, 5}}, {{5, 5}, {4, 8}, {6, 8}, {5, 5}}}},
+		{name: "nested shells", steric: matrix.MultiPolygonMatrix{{square}, {{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}}},
+			want: []ValidationResult{{NestedShells, matrix.Matrix{2, 2}}}},
+		{name: "shell in hole", steric: matrix.Collection{matrix.PolygonMatrix{square, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}},
+			matrix.PolygonMatrix{{{3, 3}, {3, 4}, {4, 4}, {4, 3}, {3, 3}}}}},
+		{name: "touching polygons", steric: matrix.Collection{matrix.PolygonMatrix{square},
+			matrix.PolygonMatrix{{{10, 10}, {20, 10}, {20, 20}, {10, 10}}}}},
+		{name: "overlapping polygons", steric: matrix.Collection{matrix.PolygonMatrix{square},
+			matrix.PolygonMatrix{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}},
+			want: []ValidationResult{{SelfIntersection, matrix.Matrix{10, 0}}}},
+		{name: "all invalidities", steric: matrix.Collection{
+			matrix.PolygonMatrix{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+			matrix.LineMatrix{{1, 1}},
+			matrix.PolygonMatrix{{{20, 0}, {30, 0}, {30, 10}, {20, 10}, {20, 0}}, {{40, 0}, {41, 0}, {41, 1}, {40, 0}}}},
+			// the components are validated in order.
+			want: []ValidationResult{{SelfIntersection, matrix.Matrix{5, 5}}, {TooFewPoints, matrix.Matrix{1, 1}},
+				{HoleOutsideShell, matrix.Matrix{40, 0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			el := &ValidOP{Steric: tt.steric}
+			got := el.ValidateAll()
+			if len(got) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ValidOP.ValidateAll() = %v, want %v", got, tt.want)
+				}
+			}
+			first := el.Validate()
+			if len(tt.want) == 0 {
+				if first != nil {
+					t.Errorf("ValidOP.Validate() = %v, want nil", first)
+				}
+			} else if first == nil || first.Reason != tt.want[0].Reason {
+				t.Errorf("ValidOP.Validate() = %v, want %v", first, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestValidOP_ValidateLargeRing(t *testing.T) {
+	// a wavy ring of 20000 vertices, and the same ring with two vertices swapped so that its edges cross.
+	n := 20000
+	ring := make(matrix.LineMatrix, 0, n+1)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		r := 100 + 5*math.Sin(50*a)
+		ring = append(ring, []float64{r * math.Cos(a), r * math.Sin(a)})
+	}
+	ring = append(ring, ring[0])
+	hole := matrix.LineMatrix{{-10, -10}, {-10, 10}, {10, 10}, {10, -10}, {-10, -10}}
+	start := time.Now()
+	if got := (&ValidOP{Steric: matrix.PolygonMatrix{ring, hole}}).ValidateAll(); len(got) != 0 {
+		t.Errorf("ValidOP.ValidateAll() = %v, want valid", got)
+	}
+	crossed := append(matrix.LineMatrix{}, ring...)
+	crossed[n/2], crossed[n/2+2] = crossed[n/2+2], crossed[n/2]
+	if got := (&ValidOP{Steric: matrix.PolygonMatrix{crossed}}).ValidateAll(); len(got) == 0 || got[0].Reason != SelfIntersection {
+		t.Errorf("ValidOP.ValidateAll() = %v, want a self-intersection", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ValidOP.ValidateAll() took %v", elapsed)
+	}
+}
+
+func TestValidationResult_String(t *testing.T) {
+	v := ValidationResult{Reason: SelfIntersection, Location: matrix.Matrix{5, 5.5}}
+	if got := v.String(); got != "self-intersection at 5 5.5" {
+		t.Errorf("ValidationResult.String() = %v", got)
+	}
+}
@@ -0,0 +1,360 @@
+// Package polygonize forms the polygons enclosed by a set of lines.
+package polygonize
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/graph"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/operation"
+	"github.com/spatial-go/geoos/algorithm/relate"
+)
+
+// Result the polygons formed by the lines, and the lines which bound no polygon.
+type Result struct {
+	Polygons []matrix.PolygonMatrix
+	// Dangles the lines with an end point connected to no other line, and the lines connected only to them.
+	Dangles []matrix.LineMatrix
+	// CutEdges the lines connected at both ends which bound no polygon, as they are the only link between two parts.
+	CutEdges []matrix.LineMatrix
+	// InvalidRings the rings of lines which are not valid, as the ones of lines which are not correctly noded.
+	InvalidRings []matrix.LineMatrix
+}
+
+// Polygonize Forms the polygons enclosed by lines which are correctly noded, they meet at their end points only.
+// The lines and their end points are the nodes of a planar graph, the duplicate lines are merged.
+// The dangles are removed from the graph, then the faces of the graph are traced,
+// the cut edges bound the same face on both sides and are removed.
+// The CCW rings of the faces are the shells of the polygons, the CW ones are their holes,
+// each hole belongs to the smallest shell containing it, the rings of the outer faces belong to no shell.
+// The lines are the LineMatrix of a geometry, or the rings of its polygons.
+func Polygonize(lines matrix.Steric) (*Result, error) {
+	if lines == nil {
+		return nil, algorithm.ErrNilSteric
+	}
+	p := &polygonizer{graph: &graph.MatrixGraph{}, result: &Result{}}
+	if err := p.add(lines); err != nil {
+		return nil, err
+	}
+	p.removeDangles()
+	p.removeCutEdges()
+	p.buildPolygons()
+	return p.result, nil
+}
+
+// polygonizer the planar graph of the lines, the nodes of the lines are connected to the nodes of their end points.
+type polygonizer struct {
+	graph  *graph.MatrixGraph
+	edges  []*lineEdge
+	result *Result
+}
+
+// lineEdge a line of the graph, and its end point nodes.
+type lineEdge struct {
+	node       *graph.Node
+	line       matrix.LineMatrix
+	start, end *graph.Node
+}
+
+func (p *polygonizer) add(geom matrix.Steric) error {
+	switch m := geom.(type) {
+	case matrix.Matrix:
+		// a point bounds no polygon.
+	case matrix.LineMatrix:
+		p.addLine(m)
+	case matrix.PolygonMatrix:
+		for _, ring := range m {
+			p.addLine(ring)
+		}
+	case matrix.MultiPolygonMatrix:
+		for _, poly := range m {
+			for _, ring := range poly {
+				p.addLine(ring)
+			}
+		}
+	case matrix.Collection:
+		for _, v := range m {
+			if err := p.add(v); err != nil {
+				return err
+			}
+		}
+	default:
+		return algorithm.ErrNotMatchType
+	}
+	return nil
+}
+
+func (p *polygonizer) addLine(line matrix.LineMatrix) {
+	line = removeRepeatedPoints(line)
+	if len(line) < 2 {
+		return
+	}
+	node := &graph.Node{Value: line, NodeType: graph.LNode}
+	if _, ok := p.graph.Node(node); ok {
+		return
+	}
+	p.graph.AddNode(node)
+	edge := &lineEdge{node: node, line: line}
+	for i, pt := range []matrix.Matrix{line[0], line[len(line)-1]} {
+		end := &graph.Node{Value: pt, NodeType: graph.PNode}
+		p.graph.AddNode(end)
+		p.graph.AddEdge(end, node)
+		end, _ = p.graph.Node(end)
+		if i == 0 {
+			edge.start = end
+		} else {
+			edge.end = end
+		}
+	}
+	p.edges = append(p.edges, edge)
+}
+
+// removeDangles removes the lines with an end point connected to no other line, until there is none.
+func (p *polygonizer) removeDangles() {
+	for removed := true; removed; {
+		removed = false
+		for _, e := range p.edges {
+			if !e.node.Stat || e.start == e.end {
+				continue
+			}
+			if p.graph.Degree(e.start.Index) == 1 || p.graph.Degree(e.end.Index) == 1 {
+				p.result.Dangles = append(p.result.Dangles, e.line)
+				p.graph.DeleteNode(e.node)
+				removed = true
+			}
+		}
+	}
+}
+
+// removeCutEdges removes the lines which bound the same face on both sides.
+func (p *polygonizer) removeCutEdges() {
+	h := p.halfEdges()
+	face := make([]int, len(h.edges))
+	for f, walk := range h.walks() {
+		for _, e := range walk {
+			face[e] = f
+		}
+	}
+	for i := 0; i < len(h.edges); i += 2 {
+		if face[i] == face[i+1] {
+			e := h.edges[i].edge
+			p.result.CutEdges = append(p.result.CutEdges, e.line)
+			p.graph.DeleteNode(e.node)
+		}
+	}
+}
+
+// buildPolygons splits the walks around the faces into simple rings, which are shells or holes.
+func (p *polygonizer) buildPolygons() {
+	h := p.halfEdges()
+	shells, holes := []matrix.LineMatrix{}, []matrix.LineMatrix{}
+	invalid := map[string]bool{}
+	for _, walk := range h.walks() {
+		for _, ring := range h.simpleRings(walk) {
+			coords := h.coordinates(ring)
+			vop := &operation.ValidOP{Steric: matrix.PolygonMatrix{coords}}
+			if vop.Validate() != nil {
+				// the rings of both sides of the lines are invalid, the lines are kept once.
+				if key := h.key(ring); !invalid[key] {
+					invalid[key] = true
+					p.result.InvalidRings = append(p.result.InvalidRings, coords)
+				}
+				continue
+			}
+			if signedArea(coords) > 0 {
+				shells = append(shells, coords)
+			} else {
+				holes = append(holes, coords)
+			}
+		}
+	}
+	polys := make([]matrix.PolygonMatrix, len(shells))
+	for i, shell := range shells {
+		polys[i] = matrix.PolygonMatrix{shell}
+	}
+	for _, hole := range holes {
+		if i := containingShell(hole, shells); i >= 0 {
+			polys[i] = append(polys[i], hole)
+		}
+	}
+	p.result.Polygons = polys
+}
+
+// containingShell returns the index of the smallest shell which contains the hole, -1 if there is none.
+func containingShell(hole matrix.LineMatrix, shells []matrix.LineMatrix) int {
+	holeEnv := envelope.MatrixList(toMatrixes(hole))
+	best, bestArea := -1, math.Inf(1)
+	for i, shell := range shells {
+		shellEnv := envelope.MatrixList(toMatrixes(shell))
+		if !shellEnv.Covers(holeEnv) {
+			continue
+		}
+		for _, pt := range hole {
+			location := relate.LocateInRings(pt, shell)
+			if location == calc.ImBoundary {
+				continue
+			}
+			if area := math.Abs(signedArea(shell)); location == calc.ImInterior && area < bestArea {
+				best, bestArea = i, area
+			}
+			break
+		}
+	}
+	return best
+}
+
+// halfEdge a line of the graph in a direction, from the node orig to the node dest.
+type halfEdge struct {
+	edge       *lineEdge
+	orig, dest int
+	forward    bool
+	angle      float64
+}
+
+// halfEdges the half-edges of the lines of the graph, a line gives the half-edges 2i and 2i+1 of opposite directions,
+// and the half-edges out of each node sorted CCW.
+type halfEdges struct {
+	edges []halfEdge
+	out   map[int][]int
+	pos   []int
+}
+
+func (p *polygonizer) halfEdges() *halfEdges {
+	h := &halfEdges{out: map[int][]int{}}
+	for _, e := range p.edges {
+		if !e.node.Stat {
+			continue
+		}
+		n := len(e.line)
+		h.edges = append(h.edges,
+			halfEdge{edge: e, orig: e.start.Index, dest: e.end.Index, forward: true,
+				angle: math.Atan2(e.line[1][1]-e.line[0][1], e.line[1][0]-e.line[0][0])},
+			halfEdge{edge: e, orig: e.end.Index, dest: e.start.Index,
+				angle: math.Atan2(e.line[n-2][1]-e.line[n-1][1], e.line[n-2][0]-e.line[n-1][0])})
+	}
+	for i, e := range h.edges {
+		h.out[e.orig] = append(h.out[e.orig], i)
+	}
+	h.pos = make([]int, len(h.edges))
+	for _, out := range h.out {
+		sort.SliceStable(out, func(i, j int) bool { return h.edges[out[i]].angle < h.edges[out[j]].angle })
+		for i, e := range out {
+			h.pos[e] = i
+		}
+	}
+	return h
+}
+
+// next returns the half-edge following e around the face on its left, the one out of its dest node
+// which is next clockwise from the opposite of e.
+func (h *halfEdges) next(e int) int {
+	out := h.out[h.edges[e].dest]
+	return out[(h.pos[e^1]-1+len(out))%len(out)]
+}
+
+// walks returns the closed walks of the half-edges around the faces of the graph.
+func (h *halfEdges) walks() [][]int {
+	visited := make([]bool, len(h.edges))
+	walks := [][]int{}
+	for i := range h.edges {
+		if visited[i] {
+			continue
+		}
+		walk := []int{}
+		for e := i; !visited[e]; e = h.next(e) {
+			visited[e] = true
+			walk = append(walk, e)
+		}
+		walks = append(walks, walk)
+	}
+	return walks
+}
+
+// simpleRings splits a walk at the nodes it passes more than once into rings passing each node once.
+func (h *halfEdges) simpleRings(walk []int) [][]int {
+	rings := [][]int{}
+	stack := []int{}
+	at := map[int]int{}
+	for _, e := range walk {
+		at[h.edges[e].orig] = len(stack)
+		stack = append(stack, e)
+		if j, ok := at[h.edges[e].dest]; ok {
+			ring := append([]int{}, stack[j:]...)
+			for _, r := range ring {
+				delete(at, h.edges[r].orig)
+			}
+			stack = stack[:j]
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+// coordinates returns the closed ring of the lines of the half-edges.
+func (h *halfEdges) coordinates(ring []int) matrix.LineMatrix {
+	coords := matrix.LineMatrix{}
+	for _, e := range ring {
+		line := h.edges[e].edge.line
+		for i := range line {
+			pt := line[i]
+			if !h.edges[e].forward {
+				pt = line[len(line)-1-i]
+			}
+			if i > 0 || len(coords) == 0 {
+				coords = append(coords, pt)
+			}
+		}
+	}
+	return coords
+}
+
+// key returns a key of the lines of a ring, independent of their direction.
+func (h *halfEdges) key(ring []int) string {
+	lines := make([]int, 0, len(ring))
+	for _, e := range ring {
+		lines = append(lines, h.edges[e].edge.node.Index)
+	}
+	sort.Ints(lines)
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(strconv.Itoa(l))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// signedArea returns the area of a closed ring, positive if it is CCW.
+func signedArea(ring matrix.LineMatrix) float64 {
+	sum := 0.0
+	for i := 1; i < len(ring); i++ {
+		sum += (ring[i-1][0] - ring[0][0]) * (ring[i][1] - ring[0][1])
+		sum -= (ring[i][0] - ring[0][0]) * (ring[i-1][1] - ring[0][1])
+	}
+	return sum / 2
+}
+
+// removeRepeatedPoints returns the line without the consecutive repeated points.
+func removeRepeatedPoints(line matrix.LineMatrix) matrix.LineMatrix {
+	result := matrix.LineMatrix{}
+	for _, p := range line {
+		if len(result) == 0 || !matrix.Matrix(result[len(result)-1]).Equals(matrix.Matrix(p)) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// toMatrixes returns the points of a line.
+func toMatrixes(line matrix.LineMatrix) []matrix.Matrix {
+	pts := make([]matrix.Matrix, 0, len(line))
+	for _, p := range line {
+		pts = append(pts, p)
+	}
+	return pts
+}
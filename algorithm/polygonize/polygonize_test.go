@@ -0,0 +1,102 @@
+package polygonize
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+func TestPolygonize(t *testing.T) {
+	tests := []struct {
+		name         string
+		lines        matrix.Steric
+		wantAreas    []float64
+		wantHoles    []int
+		dangles      []matrix.LineMatrix
+		cutEdges     []matrix.LineMatrix
+		invalidRings int
+		wantErr      error
+	}{
+		{name: "closed line", lines: matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			wantAreas: []float64{100}, wantHoles: []int{0}},
+		{name: "parcels", lines: matrix.Collection{
+			matrix.LineMatrix{{0, 0}, {10, 0}},
+			matrix.LineMatrix{{10, 0}, {20, 0}, {20, 10}, {10, 10}},
+			matrix.LineMatrix{{10, 10}, {0, 10}, {0, 0}},
+			matrix.LineMatrix{{10, 0}, {10, 10}},
+			// a duplicate line in the opposite direction.
+			matrix.LineMatrix{{10, 10}, {10, 0}},
+		}, wantAreas: []float64{100, 100}, wantHoles: []int{0, 0}},
+		{name: "hole and island", lines: matrix.Collection{
+			matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			matrix.LineMatrix{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}},
+		}, wantAreas: []float64{64, 36}, wantHoles: []int{1, 0}},
+		{name: "hole touching shell", lines: matrix.Collection{
+			matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			matrix.LineMatrix{{0, 0}, {5, 8}, {8, 5}, {0, 0}},
+		}, wantAreas: []float64{80.5, 19.5}, wantHoles: []int{1, 0}},
+		{name: "dangles and cut edge", lines: matrix.Collection{
+			matrix.LineMatrix{{0, 0}, {10, 0}, {10, 5}},
+			matrix.LineMatrix{{10, 5}, {10, 10}, {0, 10}, {0, 0}},
+			matrix.LineMatrix{{10, 5}, {20, 5}},
+			matrix.LineMatrix{{20, 5}, {20, 0}, {30, 0}, {30, 10}},
+			matrix.LineMatrix{{30, 10}, {20, 10}, {20, 5}},
+			matrix.LineMatrix{{30, 10}, {35, 15}, {40, 15}},
+			matrix.LineMatrix{{50, 50}, {60, 60}},
+		}, wantAreas: []float64{100, 100}, wantHoles: []int{0, 0},
+			dangles:  []matrix.LineMatrix{{{30, 10}, {35, 15}, {40, 15}}, {{50, 50}, {60, 60}}},
+			cutEdges: []matrix.LineMatrix{{{10, 5}, {20, 5}}}},
+		{name: "dangling chain", lines: matrix.Collection{
+			matrix.LineMatrix{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			matrix.LineMatrix{{12, 12}, {15, 15}},
+			matrix.LineMatrix{{10, 10}, {12, 12}},
+		}, wantAreas: []float64{100}, wantHoles: []int{0},
+			dangles: []matrix.LineMatrix{{{12, 12}, {15, 15}}, {{10, 10}, {12, 12}}}},
+		{name: "not noded", lines: matrix.LineMatrix{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}},
+			invalidRings: 1},
+		{name: "points", lines: matrix.Collection{matrix.Matrix{1, 1}}},
+		{name: "nil", wantErr: algorithm.ErrNilSteric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Polygonize(tt.lines)
+			if err != tt.wantErr {
+				t.Fatalf("Polygonize() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Polygons) != len(tt.wantAreas) {
+				t.Fatalf("Polygonize() polygons = %v, want %v", got.Polygons, len(tt.wantAreas))
+			}
+			for i, poly := range got.Polygons {
+				area := math.Abs(signedArea(poly[0]))
+				for _, hole := range poly[1:] {
+					if signedArea(hole) >= 0 {
+						t.Errorf("Polygonize() hole %v is not CW", hole)
+					}
+					area -= math.Abs(signedArea(hole))
+				}
+				if math.Abs(area-tt.wantAreas[i]) > 1e-9 || len(poly)-1 != tt.wantHoles[i] {
+					t.Errorf("Polygonize() polygon %v = %v, want area %v and %v holes", i, poly, tt.wantAreas[i], tt.wantHoles[i])
+				}
+			}
+			if len(got.Dangles) != 0 || len(tt.dangles) != 0 {
+				if !reflect.DeepEqual(got.Dangles, tt.dangles) {
+					t.Errorf("Polygonize() dangles = %v, want %v", got.Dangles, tt.dangles)
+				}
+			}
+			if len(got.CutEdges) != 0 || len(tt.cutEdges) != 0 {
+				if !reflect.DeepEqual(got.CutEdges, tt.cutEdges) {
+					t.Errorf("Polygonize() cut edges = %v, want %v", got.CutEdges, tt.cutEdges)
+				}
+			}
+			if len(got.InvalidRings) != tt.invalidRings {
+				t.Errorf("Polygonize() invalid rings = %v, want %v", got.InvalidRings, tt.invalidRings)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package relate
+
+import (
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+	"github.com/spatial-go/geoos/index/intervalrtree"
+)
+
+// IndexedPointInAreaLocator Locates points in polygons, the edges of the shells and holes
+// are indexed in an interval tree by their y-intervals,
+// so a point is located against the edges crossing its horizontal line only, in O(log n) for most polygons.
+// Points within the tolerance of an edge are located on the boundary.
+// The locator is not modified by Locate, it is safe for concurrent use.
+type IndexedPointInAreaLocator struct {
+	edges     *intervalrtree.SortedPackedIntervalRTree
+	env       *envelope.Envelope
+	tolerance float64
+}
+
+// NewIndexedPointInAreaLocator Returns a locator of points in the polygons of areal,
+// a PolygonMatrix, a MultiPolygonMatrix or a Collection of them.
+// The polygons must not overlap, a point in the interior of several polygons would be located in the exterior.
+func NewIndexedPointInAreaLocator(areal matrix.Steric, tolerance float64) (*IndexedPointInAreaLocator, error) {
+	if areal == nil || areal.IsEmpty() {
+		return nil, algorithm.ErrNilSteric
+	}
+	if tolerance < 0 {
+		return nil, algorithm.ErrWrongTolerance
+	}
+	rings, err := arealRings(areal, nil)
+	if err != nil {
+		return nil, err
+	}
+	l := &IndexedPointInAreaLocator{
+		edges:     &intervalrtree.SortedPackedIntervalRTree{},
+		env:       envelope.Empty(),
+		tolerance: tolerance,
+	}
+	for _, ring := range rings {
+		for i := 1; i < len(ring); i++ {
+			seg := &matrix.LineSegment{P0: ring[i-1], P1: ring[i]}
+			_ = l.edges.Insert(envelope.FourFloat(seg.P0[1], seg.P1[1], 0, 0), seg)
+			l.env.ExpandToIncludeMatrix(seg.P0)
+		}
+	}
+	l.env.ExpandBy(tolerance)
+	// the tree is built by the first query, so Locate never modifies it.
+	_ = l.edges.QueryVisitor(envelope.FourFloat(0, 0, 0, 0), &index.ArrayVisitor{})
+	return l, nil
+}
+
+// Tolerance Returns the distance to the edges within which points are located on the boundary.
+func (l *IndexedPointInAreaLocator) Tolerance() float64 {
+	return l.tolerance
+}
+
+// Locate Returns the location of the point: calc.ImInterior, calc.ImBoundary or calc.ImExterior.
+func (l *IndexedPointInAreaLocator) Locate(p matrix.Matrix) int {
+	if !l.env.IsIntersects(envelope.Matrix(p)) {
+		return calc.ImExterior
+	}
+	visitor := &index.ArrayVisitor{}
+	if err := l.edges.QueryVisitor(envelope.FourFloat(p[1]-l.tolerance, p[1]+l.tolerance, 0, 0), visitor); err != nil {
+		return calc.ImExterior
+	}
+	counter := &rayCrossingCounter{p: p, tolerance: l.tolerance}
+	for _, item := range visitor.ItemsArray {
+		seg := item.(*matrix.LineSegment)
+		counter.countSegment(seg.P0, seg.P1)
+		if counter.onBoundary {
+			break
+		}
+	}
+	return counter.location()
+}
+
+// LocateInRings Returns the location of the point in the polygons made of the shells and holes rings,
+// calc.ImInterior, calc.ImBoundary or calc.ImExterior, testing all the edges without an index.
+func LocateInRings(p matrix.Matrix, rings ...matrix.LineMatrix) int {
+	counter := &rayCrossingCounter{p: p}
+	for _, ring := range rings {
+		for i := 1; i < len(ring) && !counter.onBoundary; i++ {
+			counter.countSegment(ring[i-1], ring[i])
+		}
+	}
+	return counter.location()
+}
+
+// arealRings appends the closed rings of the polygons of areal to rings.
+func arealRings(areal matrix.Steric, rings []matrix.LineMatrix) ([]matrix.LineMatrix, error) {
+	var err error
+	switch m := areal.(type) {
+	case matrix.PolygonMatrix:
+		for _, ring := range m {
+			if len(ring) > 0 && !matrix.Matrix(ring[0]).Equals(matrix.Matrix(ring[len(ring)-1])) {
+				ring = append(append(matrix.LineMatrix{}, ring...), ring[0])
+			}
+			rings = append(rings, ring)
+		}
+	case matrix.MultiPolygonMatrix:
+		for _, v := range m {
+			rings, _ = arealRings(matrix.PolygonMatrix(v), rings)
+		}
+	case matrix.Collection:
+		for _, v := range m {
+			if rings, err = arealRings(v, rings); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, algorithm.ErrNotMatchType
+	}
+	return rings, nil
+}
+
+// rayCrossingCounter counts the edges crossed by the ray from a point in the positive x direction,
+// the point is in the interior of the rings if the count is odd.
+type rayCrossingCounter struct {
+	p          matrix.Matrix
+	tolerance  float64
+	count      int
+	onBoundary bool
+}
+
+// countSegment counts an edge of a ring, each vertex is the end point of an edge of a closed ring.
+func (r *rayCrossingCounter) countSegment(p1, p2 matrix.Matrix) {
+	p := r.p
+	if r.tolerance > 0 && distanceToSegment(p, p1, p2) <= r.tolerance {
+		r.onBoundary = true
+		return
+	}
+	if p1[0] < p[0] && p2[0] < p[0] {
+		return
+	}
+	if p[0] == p2[0] && p[1] == p2[1] {
+		r.onBoundary = true
+		return
+	}
+	// horizontal edges are not crossed, the point may lie on them.
+	if p1[1] == p[1] && p2[1] == p[1] {
+		if p[0] >= math.Min(p1[0], p2[0]) && p[0] <= math.Max(p1[0], p2[0]) {
+			r.onBoundary = true
+		}
+		return
+	}
+	// an edge crosses if an end point is strictly above the ray and the other is below or on it,
+	// so the crossing at a vertex is counted once.
+	if (p1[1] > p[1] && p2[1] <= p[1]) || (p2[1] > p[1] && p1[1] <= p[1]) {
+		orient := CrossProduct(p1, p2, p1, p)
+		if orient == 0 {
+			r.onBoundary = true
+			return
+		}
+		if p2[1] < p1[1] {
+			orient = -orient
+		}
+		if orient > 0 {
+			r.count++
+		}
+	}
+}
+
+func (r *rayCrossingCounter) location() int {
+	if r.onBoundary {
+		return calc.ImBoundary
+	}
+	if r.count%2 == 1 {
+		return calc.ImInterior
+	}
+	return calc.ImExterior
+}
+
+// distanceToSegment returns the distance from p to the segment a-b.
+func distanceToSegment(p, a, b matrix.Matrix) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
@@ -0,0 +1,108 @@
+package relate
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+)
+
+// locatorPolygons a square with a hole and a triangle.
+var locatorPolygons = matrix.MultiPolygonMatrix{
+	{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}},
+	{{{20, 0}, {30, 0}, {25, 10}, {20, 0}}},
+}
+
+func TestIndexedPointInAreaLocator_Locate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tolerance float64
+		point     matrix.Matrix
+		want      int
+	}{
+		{"interior", 0, matrix.Matrix{2, 2}, calc.ImInterior},
+		{"hole", 0, matrix.Matrix{5, 5}, calc.ImExterior},
+		{"shell edge", 0, matrix.Matrix{0, 5}, calc.ImBoundary},
+		{"hole edge", 0, matrix.Matrix{5, 4}, calc.ImBoundary},
+		{"vertex", 0, matrix.Matrix{10, 10}, calc.ImBoundary},
+		{"vertex height", 0, matrix.Matrix{-1, 10}, calc.ImExterior},
+		{"between", 0, matrix.Matrix{15, 5}, calc.ImExterior},
+		{"triangle", 0, matrix.Matrix{25, 5}, calc.ImInterior},
+		{"triangle apex height", 0, matrix.Matrix{15, 10}, calc.ImExterior},
+		{"triangle slope", 0, matrix.Matrix{22.5, 5}, calc.ImBoundary},
+		{"outside", 0, matrix.Matrix{-5, -5}, calc.ImExterior},
+		{"near shell", 0.01, matrix.Matrix{-0.005, 5}, calc.ImBoundary},
+		{"near hole", 0.01, matrix.Matrix{4.005, 5}, calc.ImBoundary},
+		{"near interior", 0.01, matrix.Matrix{0.02, 5}, calc.ImInterior},
+		{"near exterior", 0.01, matrix.Matrix{-0.02, 5}, calc.ImExterior},
+		{"near slope", 0.01, matrix.Matrix{22.505, 5}, calc.ImBoundary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewIndexedPointInAreaLocator(locatorPolygons, tt.tolerance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := l.Locate(tt.point); got != tt.want {
+				t.Errorf("IndexedPointInAreaLocator.Locate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexedPointInAreaLocator_InPolygon(t *testing.T) {
+	// a star of 200 spikes.
+	ring := matrix.LineMatrix{}
+	for i := 0; i < 400; i++ {
+		r := 10.0
+		if i%2 == 1 {
+			r = 4
+		}
+		angle := float64(i) * math.Pi / 200
+		ring = append(ring, []float64{r * math.Cos(angle), r * math.Sin(angle)})
+	}
+	ring = append(ring, ring[0])
+	l, err := NewIndexedPointInAreaLocator(matrix.PolygonMatrix{ring}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 2000; i++ {
+		p := matrix.Matrix{r.Float64()*24 - 12, r.Float64()*24 - 12}
+		want := calc.ImExterior
+		if InPolygon(p, ring) {
+			want = calc.ImInterior
+		}
+		if got := l.Locate(p); got != want {
+			t.Errorf("IndexedPointInAreaLocator.Locate(%v) = %v, want %v", p, got, want)
+		}
+		if got := LocateInRings(p, ring); got != want {
+			t.Errorf("LocateInRings(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestNewIndexedPointInAreaLocator(t *testing.T) {
+	tests := []struct {
+		name      string
+		areal     matrix.Steric
+		tolerance float64
+		wantErr   error
+	}{
+		{"polygon", matrix.PolygonMatrix(locatorPolygons[1]), 0, nil},
+		{"collection", matrix.Collection{matrix.PolygonMatrix(locatorPolygons[1])}, 0, nil},
+		{"nil", nil, 0, algorithm.ErrNilSteric},
+		{"negative tolerance", locatorPolygons, -1, algorithm.ErrWrongTolerance},
+		{"line", matrix.LineMatrix{{0, 0}, {1, 1}}, 0, algorithm.ErrNotMatchType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewIndexedPointInAreaLocator(tt.areal, tt.tolerance); err != tt.wantErr {
+				t.Errorf("NewIndexedPointInAreaLocator() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,514 @@
+package subdivision
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/buffer"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/relate"
+)
+
+// ErrWrongEdgeLengthRatio ...
+var ErrWrongEdgeLengthRatio = fmt.Errorf("edge length ratio is not in range [0.0 - 1.0]")
+
+// ErrWrongAlpha ...
+var ErrWrongAlpha = fmt.Errorf("alpha must be non-negative")
+
+// ConcaveHullOptions the parameters of a concave hull, the zero value is the tightest single polygon without holes.
+type ConcaveHullOptions struct {
+	// MaxEdgeLengthRatio the longest edge kept on the border of the hull, as a ratio in [0,1]
+	// between the shortest and the longest edge of the Delaunay triangulation:
+	// 0 is the tightest hull and 1 is the convex hull.
+	MaxEdgeLengthRatio float64
+	// Alpha if positive, the hull is an alpha shape, the triangles of circumradius greater than Alpha are removed,
+	// MaxEdgeLengthRatio is ignored.
+	Alpha float64
+	// HolesAllowed the hull may have holes, where the triangles inside the hull are removed.
+	HolesAllowed bool
+	// SinglePolygon the hull is a single polygon, the triangles whose removal would
+	// disconnect the hull or pinch its boundary are kept.
+	SinglePolygon bool
+}
+
+// ConcaveHull Computes the concave hull of points, by eroding the triangles of their Delaunay triangulation
+// from the border, or from inside the hull if holes are allowed.
+// Returns a PolygonMatrix, or a Collection of PolygonMatrix if the hull is not a single polygon,
+// the convex hull if there are less than three points or the points are collinear.
+func ConcaveHull(points []matrix.Matrix, opts ConcaveHullOptions) (matrix.Steric, error) {
+	return concaveHull(points, nil, opts)
+}
+
+// ConcaveHullOfPolygons Computes the concave hull of polygons, the hull of their vertices
+// which contains the polygons, the triangles inside the polygons are never removed.
+func ConcaveHullOfPolygons(polygons []matrix.PolygonMatrix, opts ConcaveHullOptions) (matrix.Steric, error) {
+	points := []matrix.Matrix{}
+	for _, poly := range polygons {
+		for _, ring := range poly {
+			for i := 0; i < len(ring)-1; i++ {
+				points = append(points, ring[i])
+			}
+		}
+	}
+	return concaveHull(points, polygons, opts)
+}
+
+func concaveHull(points []matrix.Matrix, polygons []matrix.PolygonMatrix, opts ConcaveHullOptions) (matrix.Steric, error) {
+	if opts.MaxEdgeLengthRatio < 0 || opts.MaxEdgeLengthRatio > 1 {
+		return nil, ErrWrongEdgeLengthRatio
+	}
+	if opts.Alpha < 0 {
+		return nil, ErrWrongAlpha
+	}
+	if len(points) == 0 {
+		return nil, nil
+	}
+	h := newHullTriangulation(points)
+	if len(h.tris) == 0 {
+		line := make(matrix.LineMatrix, len(points))
+		for i, p := range points {
+			line[i] = p
+		}
+		return buffer.ConvexHull(line), nil
+	}
+	if polygons != nil {
+		h.fillPolygons(polygons)
+	}
+	h.erode(opts)
+
+	polys := h.polygons()
+	if polygons != nil {
+		// the triangulation does not follow the edges of the polygons.
+		result := matrix.Collection{}
+		for _, poly := range polys {
+			result = append(result, poly)
+		}
+		for _, poly := range polygons {
+			result = append(result, poly)
+		}
+		return buffer.Buffer(result, 0, calc.QuadrantSegments), nil
+	}
+	if len(polys) == 1 {
+		return polys[0], nil
+	}
+	result := matrix.Collection{}
+	for _, poly := range polys {
+		result = append(result, poly)
+	}
+	return result, nil
+}
+
+// hullTri a triangle of the triangulation, CCW.
+type hullTri struct {
+	v [3]int
+	// adj the triangle across the edge from v[i] to v[i+1], -1 if none.
+	adj     [3]int
+	removed bool
+	// filled the triangle is inside a polygon and is never removed.
+	filled bool
+}
+
+// hullTriangulation the triangles of the Delaunay triangulation of the points of a concave hull.
+type hullTriangulation struct {
+	vertices []matrix.Matrix
+	tris     []*hullTri
+	border   []bool
+	// covered the vertices of the filled triangles, they stay in the hull whatever triangle is removed.
+	covered []bool
+}
+
+func newHullTriangulation(points []matrix.Matrix) *hullTriangulation {
+	h := &hullTriangulation{}
+	index := map[[2]float64]int{}
+	vertex := func(p matrix.Matrix) int {
+		key := [2]float64{p[0], p[1]}
+		if i, ok := index[key]; ok {
+			return i
+		}
+		index[key] = len(h.vertices)
+		h.vertices = append(h.vertices, matrix.Matrix{p[0], p[1]})
+		return len(h.vertices) - 1
+	}
+	type edgeKey [2]int
+	edges := map[edgeKey][2]int{}
+	for _, triangle := range NewDelaunayTriangulation(points).Triangles() {
+		t := &hullTri{v: [3]int{vertex(triangle[0]), vertex(triangle[1]), vertex(triangle[2])}, adj: [3]int{-1, -1, -1}}
+		if t.v[0] == t.v[1] || t.v[1] == t.v[2] || t.v[2] == t.v[0] {
+			continue
+		}
+		if !isCCW(h.vertices[t.v[0]], h.vertices[t.v[1]], h.vertices[t.v[2]]) {
+			t.v[1], t.v[2] = t.v[2], t.v[1]
+		}
+		for i := 0; i < 3; i++ {
+			a, b := t.v[i], t.v[(i+1)%3]
+			key := edgeKey{a, b}
+			if b < a {
+				key = edgeKey{b, a}
+			}
+			if other, ok := edges[key]; ok {
+				t.adj[i] = other[0]
+				h.tris[other[0]].adj[other[1]] = len(h.tris)
+			} else {
+				edges[key] = [2]int{len(h.tris), i}
+			}
+		}
+		h.tris = append(h.tris, t)
+	}
+	h.border = make([]bool, len(h.vertices))
+	for _, t := range h.tris {
+		for i := 0; i < 3; i++ {
+			if t.adj[i] < 0 {
+				h.border[t.v[i]], h.border[t.v[(i+1)%3]] = true, true
+			}
+		}
+	}
+	return h
+}
+
+func isCCW(a, b, c matrix.Matrix) bool {
+	return (b[0]-a[0])*(c[1]-a[1])-(b[1]-a[1])*(c[0]-a[0]) > 0
+}
+
+// ring returns the closed ring of a triangle.
+func (h *hullTriangulation) ring(t *hullTri) matrix.LineMatrix {
+	return matrix.LineMatrix{h.vertices[t.v[0]], h.vertices[t.v[1]], h.vertices[t.v[2]], h.vertices[t.v[0]]}
+}
+
+// fillPolygons marks the triangles inside the polygons.
+func (h *hullTriangulation) fillPolygons(polygons []matrix.PolygonMatrix) {
+	for _, t := range h.tris {
+		a, b, c := h.vertices[t.v[0]], h.vertices[t.v[1]], h.vertices[t.v[2]]
+		centroid := matrix.Matrix{(a[0] + b[0] + c[0]) / 3, (a[1] + b[1] + c[1]) / 3}
+		for _, poly := range polygons {
+			rings := make([]matrix.LineMatrix, len(poly))
+			for i, ring := range poly {
+				rings[i] = ring
+			}
+			if relate.LocateInRings(centroid, rings...) == calc.ImInterior {
+				t.filled = true
+				break
+			}
+		}
+	}
+	h.covered = make([]bool, len(h.vertices))
+	for _, t := range h.tris {
+		if t.filled {
+			for _, v := range t.v {
+				h.covered[v] = true
+			}
+		}
+	}
+}
+
+// isLive returns true if the triangle exists and is not removed.
+func (h *hullTriangulation) isLive(i int) bool {
+	return i >= 0 && !h.tris[i].removed
+}
+
+// numAdjacent returns the number of live triangles adjacent to a triangle.
+func (h *hullTriangulation) numAdjacent(t *hullTri) int {
+	n := 0
+	for _, a := range t.adj {
+		if h.isLive(a) {
+			n++
+		}
+	}
+	return n
+}
+
+// size returns the measure of a triangle compared to the threshold of the hull: its circumradius for an alpha shape,
+// otherwise the length of its longest border edge, or of its longest edge if it is inside the hull.
+func (h *hullTriangulation) size(t *hullTri, alpha bool) float64 {
+	a, b, c := h.vertices[t.v[0]], h.vertices[t.v[1]], h.vertices[t.v[2]]
+	if alpha {
+		return circumradius(a, b, c)
+	}
+	longest, longestBorder := 0.0, 0.0
+	for i := 0; i < 3; i++ {
+		length := distance(h.vertices[t.v[i]], h.vertices[t.v[(i+1)%3]])
+		longest = math.Max(longest, length)
+		if !h.isLive(t.adj[i]) {
+			longestBorder = math.Max(longestBorder, length)
+		}
+	}
+	if h.numAdjacent(t) < 3 {
+		return longestBorder
+	}
+	return longest
+}
+
+// threshold returns the size above which the triangles are removed.
+func (h *hullTriangulation) threshold(opts ConcaveHullOptions) float64 {
+	if opts.Alpha > 0 {
+		return opts.Alpha
+	}
+	minLength, maxLength := math.Inf(1), 0.0
+	for _, t := range h.tris {
+		for i := 0; i < 3; i++ {
+			length := distance(h.vertices[t.v[i]], h.vertices[t.v[(i+1)%3]])
+			minLength, maxLength = math.Min(minLength, length), math.Max(maxLength, length)
+		}
+	}
+	return minLength + opts.MaxEdgeLengthRatio*(maxLength-minLength)
+}
+
+// isRemovable returns true if removing the triangle keeps the hull as required by the options,
+// the triangles of a single border edge, or inside the hull if holes are allowed, are removable,
+// the others would remove a vertex from the hull, unless their vertices are covered by the polygons.
+func (h *hullTriangulation) isRemovable(t *hullTri, opts ConcaveHullOptions) bool {
+	if t.removed || t.filled {
+		return false
+	}
+	switch h.numAdjacent(t) {
+	case 0, 1:
+		return !opts.SinglePolygon && h.isCovered(t)
+	case 2:
+		if !opts.SinglePolygon {
+			return true
+		}
+		// the vertex opposite the border edge must not be on the border, or the boundary would be pinched.
+		for i := 0; i < 3; i++ {
+			if !h.isLive(t.adj[i]) {
+				return !h.border[t.v[(i+2)%3]]
+			}
+		}
+	case 3:
+		// a hole must not touch the boundary.
+		return opts.HolesAllowed && !h.border[t.v[0]] && !h.border[t.v[1]] && !h.border[t.v[2]]
+	}
+	return false
+}
+
+// isCovered returns true if the vertices of the triangle are vertices of the filled triangles.
+func (h *hullTriangulation) isCovered(t *hullTri) bool {
+	if h.covered == nil {
+		return false
+	}
+	return h.covered[t.v[0]] && h.covered[t.v[1]] && h.covered[t.v[2]]
+}
+
+// erode removes the triangles larger than the threshold, the largest first, from the border of the hull.
+// If holes are allowed, each hole is then grown from the largest remaining triangle inside the hull,
+// before the next one is started.
+func (h *hullTriangulation) erode(opts ConcaveHullOptions) {
+	alpha := opts.Alpha > 0
+	threshold := h.threshold(opts)
+	queue := &hullQueue{}
+	for i, t := range h.tris {
+		if h.numAdjacent(t) < 3 {
+			heap.Push(queue, hullItem{i, h.size(t, alpha)})
+		}
+	}
+	h.drain(queue, threshold, opts)
+	if !opts.HolesAllowed {
+		return
+	}
+	seeds := hullQueue{}
+	for i, t := range h.tris {
+		if !t.removed && h.numAdjacent(t) == 3 {
+			seeds = append(seeds, hullItem{i, h.size(t, alpha)})
+		}
+	}
+	sort.Sort(seeds)
+	for _, seed := range seeds {
+		if seed.size <= threshold {
+			break
+		}
+		if h.isRemovable(h.tris[seed.tri], opts) {
+			h.drain(&hullQueue{seed}, threshold, opts)
+		}
+	}
+}
+
+// drain removes the removable triangles of the queue larger than the threshold,
+// and queues their neighbours.
+func (h *hullTriangulation) drain(queue *hullQueue, threshold float64, opts ConcaveHullOptions) {
+	alpha := opts.Alpha > 0
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(hullItem)
+		t := h.tris[item.tri]
+		if t.removed {
+			continue
+		}
+		// the size of a triangle grows as its neighbours are removed.
+		if size := h.size(t, alpha); size != item.size {
+			heap.Push(queue, hullItem{item.tri, size})
+			continue
+		}
+		if item.size <= threshold || !h.isRemovable(t, opts) {
+			continue
+		}
+		t.removed = true
+		for i := 0; i < 3; i++ {
+			h.border[t.v[i]] = true
+			if h.isLive(t.adj[i]) {
+				heap.Push(queue, hullItem{t.adj[i], h.size(h.tris[t.adj[i]], alpha)})
+			}
+		}
+	}
+}
+
+// polygons traces the rings of the border edges of the triangles, with the hull on their left,
+// the rings are split at the vertices where the boundary is pinched,
+// and a walk through a vertex twice, where a hole touches the boundary, is split into its simple rings.
+// Returns the CCW shells with the CW holes they contain.
+func (h *hullTriangulation) polygons() []matrix.PolygonMatrix {
+	type borderEdge struct{ tri, i int }
+	out := map[int][]borderEdge{}
+	for ti, t := range h.tris {
+		if t.removed {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			if !h.isLive(t.adj[i]) {
+				out[t.v[i]] = append(out[t.v[i]], borderEdge{ti, i})
+			}
+		}
+	}
+	origin := func(e borderEdge) int { return h.tris[e.tri].v[e.i] }
+	dest := func(e borderEdge) int { return h.tris[e.tri].v[(e.i+1)%3] }
+	angle := func(from, to int) float64 {
+		return math.Atan2(h.vertices[to][1]-h.vertices[from][1], h.vertices[to][0]-h.vertices[from][0])
+	}
+	// next returns the border edge leaving the destination of e which is first clockwise from the twin of e,
+	// on the boundary of the same triangles as e.
+	next := func(e borderEdge) borderEdge {
+		v := dest(e)
+		edges := out[v]
+		if len(edges) == 1 {
+			return edges[0]
+		}
+		back := angle(v, origin(e))
+		best, bestTurn := edges[0], math.Inf(1)
+		for _, candidate := range edges {
+			turn := back - angle(v, dest(candidate))
+			for turn <= 0 {
+				turn += 2 * math.Pi
+			}
+			if turn < bestTurn {
+				best, bestTurn = candidate, turn
+			}
+		}
+		return best
+	}
+
+	visited := map[borderEdge]bool{}
+	shells, holes := []matrix.LineMatrix{}, []matrix.LineMatrix{}
+	for v := range h.vertices {
+		for _, start := range out[v] {
+			if visited[start] {
+				continue
+			}
+			walk := []int{}
+			for e := start; !visited[e]; e = next(e) {
+				visited[e] = true
+				walk = append(walk, origin(e))
+			}
+			for _, ring := range h.simpleRings(walk) {
+				if ringArea(ring) > 0 {
+					shells = append(shells, ring)
+				} else {
+					holes = append(holes, ring)
+				}
+			}
+		}
+	}
+	polys := make([]matrix.PolygonMatrix, len(shells))
+	for i, shell := range shells {
+		polys[i] = matrix.PolygonMatrix{shell}
+	}
+	for _, hole := range holes {
+		owner := -1
+		for i, shell := range shells {
+			if (owner < 0 || ringArea(shell) < ringArea(shells[owner])) && isRingInside(hole, shell) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			polys[owner] = append(polys[owner], hole)
+		}
+	}
+	return polys
+}
+
+// simpleRings splits a closed walk of vertices at the vertices it goes through twice.
+func (h *hullTriangulation) simpleRings(walk []int) []matrix.LineMatrix {
+	rings := []matrix.LineMatrix{}
+	stack, at := []int{}, map[int]int{}
+	for _, v := range append(walk, walk[0]) {
+		if i, ok := at[v]; ok {
+			ring := make(matrix.LineMatrix, 0, len(stack)-i+1)
+			for _, w := range stack[i:] {
+				ring = append(ring, h.vertices[w])
+				delete(at, w)
+			}
+			if ring = append(ring, h.vertices[v]); len(ring) >= 4 {
+				rings = append(rings, ring)
+			}
+			stack = stack[:i]
+		}
+		at[v] = len(stack)
+		stack = append(stack, v)
+	}
+	return rings
+}
+
+// ringArea returns the area of a closed ring, positive if it is CCW.
+func ringArea(ring matrix.LineMatrix) float64 {
+	sum := 0.0
+	for i := 1; i < len(ring); i++ {
+		sum += ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]
+	}
+	return sum / 2
+}
+
+// isRingInside returns true if a hole is inside a shell, the rings touch at most at vertices.
+func isRingInside(hole, shell matrix.LineMatrix) bool {
+	for _, p := range hole {
+		if location := relate.LocateInRings(p, shell); location != calc.ImBoundary {
+			return location == calc.ImInterior
+		}
+	}
+	return false
+}
+
+// circumradius returns the radius of the circumcircle of a triangle.
+func circumradius(a, b, c matrix.Matrix) float64 {
+	area2 := math.Abs((b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0]))
+	if area2 == 0 {
+		return math.Inf(1)
+	}
+	return distance(a, b) * distance(b, c) * distance(c, a) / (2 * area2)
+}
+
+func distance(a, b matrix.Matrix) float64 {
+	return math.Hypot(b[0]-a[0], b[1]-a[1])
+}
+
+// hullItem a triangle in the queue, with its size when it was queued.
+type hullItem struct {
+	tri  int
+	size float64
+}
+
+// hullQueue a max-heap of triangles by size.
+type hullQueue []hullItem
+
+func (q hullQueue) Len() int { return len(q) }
+func (q hullQueue) Less(i, j int) bool {
+	if q[i].size != q[j].size {
+		return q[i].size > q[j].size
+	}
+	return q[i].tri < q[j].tri
+}
+func (q hullQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *hullQueue) Push(x interface{}) { *q = append(*q, x.(hullItem)) }
+func (q *hullQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
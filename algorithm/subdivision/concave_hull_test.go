@@ -0,0 +1,170 @@
+package subdivision
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/algorithm/operation"
+)
+
+// cShape the points of a 10 x 10 grid without the middle of its right half.
+func cShape() []matrix.Matrix {
+	points := []matrix.Matrix{}
+	for x := 0; x <= 10; x++ {
+		for y := 0; y <= 10; y++ {
+			if x >= 4 && y >= 4 && y <= 6 {
+				continue
+			}
+			points = append(points, matrix.Matrix{float64(x), float64(y)})
+		}
+	}
+	return points
+}
+
+// ringShape the points of a 10 x 10 grid around a 6 x 6 empty square.
+func ringShape() []matrix.Matrix {
+	points := []matrix.Matrix{}
+	for x := 0; x <= 10; x++ {
+		for y := 0; y <= 10; y++ {
+			if x > 2 && x < 8 && y > 2 && y < 8 {
+				continue
+			}
+			points = append(points, matrix.Matrix{float64(x), float64(y)})
+		}
+	}
+	return points
+}
+
+func TestConcaveHull(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []matrix.Matrix
+		opts   ConcaveHullOptions
+		// minArea, maxArea the bounds of the area of the hull.
+		minArea, maxArea float64
+		rings            int
+	}{
+		{name: "convex", points: cShape(), opts: ConcaveHullOptions{MaxEdgeLengthRatio: 1, SinglePolygon: true}, minArea: 100, maxArea: 100, rings: 1},
+		{name: "concave", points: cShape(), opts: ConcaveHullOptions{MaxEdgeLengthRatio: 0.5, SinglePolygon: true}, minArea: 72, maxArea: 75, rings: 1},
+		{name: "no holes", points: ringShape(), opts: ConcaveHullOptions{SinglePolygon: true}, minArea: 100, maxArea: 100, rings: 1},
+		{name: "holes", points: ringShape(), opts: ConcaveHullOptions{MaxEdgeLengthRatio: 0.2, HolesAllowed: true, SinglePolygon: true}, minArea: 64, maxArea: 70, rings: 2},
+		{name: "alpha", points: cShape(), opts: ConcaveHullOptions{Alpha: 1}, minArea: 72, maxArea: 75, rings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConcaveHull(tt.points, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			poly, ok := got.(matrix.PolygonMatrix)
+			if !ok {
+				t.Fatalf("ConcaveHull() = %v, want a polygon", got)
+			}
+			if area := measure.AreaOfPolygon(poly); len(poly) != tt.rings || area < tt.minArea || area > tt.maxArea {
+				t.Errorf("ConcaveHull() rings = %v area = %v, want %v in [%v, %v]", len(poly), area, tt.rings, tt.minArea, tt.maxArea)
+			}
+		})
+	}
+}
+
+func TestConcaveHull_Degenerate(t *testing.T) {
+	if got, _ := ConcaveHull([]matrix.Matrix{{0, 0}, {1, 1}, {2, 2}}, ConcaveHullOptions{}); !got.Equals(matrix.LineMatrix{{0, 0}, {2, 2}}) {
+		t.Errorf("ConcaveHull() collinear = %v", got)
+	}
+	if got, _ := ConcaveHull([]matrix.Matrix{{1, 1}, {1, 1}}, ConcaveHullOptions{}); !got.Equals(matrix.Matrix{1, 1}) {
+		t.Errorf("ConcaveHull() point = %v", got)
+	}
+	if _, err := ConcaveHull(cShape(), ConcaveHullOptions{MaxEdgeLengthRatio: 2}); err != ErrWrongEdgeLengthRatio {
+		t.Errorf("ConcaveHull() error = %v, want %v", err, ErrWrongEdgeLengthRatio)
+	}
+	if _, err := ConcaveHull(cShape(), ConcaveHullOptions{Alpha: -1}); err != ErrWrongAlpha {
+		t.Errorf("ConcaveHull() error = %v, want %v", err, ErrWrongAlpha)
+	}
+}
+
+func TestConcaveHull_Disjoint(t *testing.T) {
+	// two clusters and a point between them, the points are kept in the hull.
+	points := []matrix.Matrix{}
+	for x := 0; x <= 3; x++ {
+		for y := 0; y <= 3; y++ {
+			points = append(points, matrix.Matrix{float64(x), float64(y)}, matrix.Matrix{float64(x + 10), float64(y)})
+		}
+	}
+	points = append(points, matrix.Matrix{6.5, 1.5})
+	got, err := ConcaveHull(points, ConcaveHullOptions{MaxEdgeLengthRatio: 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coll, ok := got.(matrix.Collection); !ok || len(coll) != 2 {
+		t.Errorf("ConcaveHull() = %v, want 2 polygons", got)
+	}
+	single, _ := ConcaveHull(points, ConcaveHullOptions{MaxEdgeLengthRatio: 0.2, SinglePolygon: true})
+	if _, ok := single.(matrix.PolygonMatrix); !ok {
+		t.Errorf("ConcaveHull() = %v, want a polygon", single)
+	}
+}
+
+func TestConcaveHull_HoleTouchingBorder(t *testing.T) {
+	// holes grown through the border triangles may reach the boundary of the hull at a vertex.
+	for seed := int64(0); seed < 60; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		points := make([]matrix.Matrix, 0, 80)
+		for i := 0; i < 80; i++ {
+			points = append(points, matrix.Matrix{r.Float64() * 100, r.Float64() * 100})
+		}
+		got, err := ConcaveHull(points, ConcaveHullOptions{MaxEdgeLengthRatio: 0.2, HolesAllowed: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if invalid := (&operation.ValidOP{Steric: got}).Validate(); invalid != nil {
+			t.Errorf("ConcaveHull() of seed %v is invalid: %v", seed, invalid)
+		}
+	}
+}
+
+func TestConcaveHullOfPolygons(t *testing.T) {
+	polygons := []matrix.PolygonMatrix{
+		{{{0, 0}, {4, 0}, {4, 1}, {0, 1}, {0, 0}}},
+		{{{0, 3}, {4, 3}, {4, 4}, {0, 4}, {0, 3}}},
+		{{{0, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 1}}},
+	}
+	got, err := ConcaveHullOfPolygons(polygons, ConcaveHullOptions{SinglePolygon: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly, ok := got.(matrix.PolygonMatrix)
+	if !ok {
+		t.Fatalf("ConcaveHullOfPolygons() = %v, want a polygon", got)
+	}
+	if area := measure.AreaOfPolygon(poly); area < 10 || area > 16 {
+		t.Errorf("ConcaveHullOfPolygons() area = %v", area)
+	}
+}
+
+func TestConcaveHullOfPolygons_Disjoint(t *testing.T) {
+	polygons := []matrix.PolygonMatrix{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{9, 0}, {10, 0}, {10, 1}, {9, 1}, {9, 0}}},
+		{{{5, 7}, {6, 7}, {6, 8}, {5, 8}, {5, 7}}},
+	}
+	got, err := ConcaveHullOfPolygons(polygons, ConcaveHullOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll, ok := got.(matrix.Collection)
+	if !ok || len(coll) != 3 {
+		t.Fatalf("ConcaveHullOfPolygons() = %v, want 3 polygons", got)
+	}
+	for _, v := range coll {
+		if area := measure.AreaOfPolygon(v.(matrix.PolygonMatrix)); math.Abs(area-1) > 1e-9 {
+			t.Errorf("ConcaveHullOfPolygons() polygon %v area = %v, want 1", v, area)
+		}
+	}
+	single, _ := ConcaveHullOfPolygons(polygons, ConcaveHullOptions{SinglePolygon: true})
+	if _, ok := single.(matrix.PolygonMatrix); !ok {
+		t.Errorf("ConcaveHullOfPolygons() = %v, want a polygon", single)
+	}
+}
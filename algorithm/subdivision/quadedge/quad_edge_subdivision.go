@@ -294,6 +294,14 @@ func (q *Subdivision) fetchTriangleToVisit(edge *QuadEdge, edgeStack *utils.Stac
 
 }
 
+// GetTriangleCoordinates Returns the closed rings of the vertices of the triangles,
+// the triangles of the frame are included if includeFrame is true.
+func (q *Subdivision) GetTriangleCoordinates(includeFrame bool) []matrix.LineMatrix {
+	visitor := &TriangleCoordinatesVisitor{}
+	q.visitTriangles(visitor, includeFrame)
+	return visitor.triangles
+}
+
 // GetVoronoiCellPolygons ...
 func (q *Subdivision) GetVoronoiCellPolygons() []matrix.PolygonMatrix {
 	q.visitTriangles(&TriangleCircumcentreVisitor{}, true)
@@ -47,3 +47,18 @@ func circumcentrePF(a, b, c matrix.Matrix) matrix.Matrix {
 	)
 	return matrix.Matrix{ccx, ccy}
 }
+
+// TriangleCoordinatesVisitor collects the vertices of the triangles.
+type TriangleCoordinatesVisitor struct {
+	triangles []matrix.LineMatrix
+}
+
+// Visit ...
+func (t *TriangleCoordinatesVisitor) Visit(triEdges []*QuadEdge) {
+	triangle := make(matrix.LineMatrix, 0, 4)
+	for i := 0; i < 3; i++ {
+		triangle = append(triangle, triEdges[i].Origin())
+	}
+	triangle = append(triangle, triangle[0])
+	t.triangles = append(t.triangles, triangle)
+}
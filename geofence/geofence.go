@@ -0,0 +1,257 @@
+// Package geofence a registry of polygon fences which detects the objects entering, exiting and dwelling in them
+// from streaming position updates.
+package geofence
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spatial-go/geoos/algorithm"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/relate"
+	"github.com/spatial-go/geoos/index/rstartree"
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+// EventType the type of a fence event.
+type EventType int
+
+// Types of events.
+const (
+	// Enter the object entered the fence.
+	Enter EventType = iota + 1
+	// Exit the object exited the fence.
+	Exit
+	// Dwell the object stayed in the fence for Options.DwellTime since it entered.
+	Dwell
+)
+
+// String returns the name of the event type.
+func (e EventType) String() string {
+	switch e {
+	case Enter:
+		return "enter"
+	case Exit:
+		return "exit"
+	case Dwell:
+		return "dwell"
+	}
+	return "unknown"
+}
+
+// Event An event of an object and a fence, at the time of the position update which caused it.
+type Event struct {
+	Type     EventType
+	ObjectID string
+	FenceID  string
+	Time     time.Time
+	Position space.Point
+}
+
+// ErrFenceExists ...
+var ErrFenceExists = fmt.Errorf("fence already exists")
+
+// ErrStaleUpdate ...
+var ErrStaleUpdate = fmt.Errorf("position update is older than the last update of the object")
+
+// Options the parameters of a registry.
+type Options struct {
+	// Tolerance the hysteresis distance around the boundaries of the fences:
+	// an object enters a fence when it is inside farther than Tolerance from the boundary,
+	// and exits when it is outside farther than Tolerance, so positions jittering across a boundary do not flap.
+	Tolerance float64
+	// DwellTime the duration after entering a fence at which a Dwell event is emitted, 0 for no Dwell events.
+	DwellTime time.Duration
+}
+
+// Registry A registry of fences, indexed by their envelopes, and of the objects in them.
+// A Registry is safe for concurrent use, updates of different objects locate positions in parallel.
+type Registry struct {
+	opts Options
+
+	fencesMu sync.RWMutex
+	fences   map[string]*fence
+	index    *rstartree.RStarTree
+
+	objectsMu sync.Mutex
+	objects   map[string]*object
+}
+
+// fence a fence and its point locator.
+type fence struct {
+	id      string
+	geom    space.Geometry
+	env     *envelope.Envelope
+	locator *relate.IndexedPointInAreaLocator
+}
+
+// object the last update time of an object and the time it entered the fences it is in.
+type object struct {
+	time   time.Time
+	inside map[string]*stay
+}
+
+// stay an object in a fence.
+type stay struct {
+	entered time.Time
+	dwelled bool
+}
+
+// NewRegistry Returns an empty registry.
+func NewRegistry(opts Options) (*Registry, error) {
+	if opts.Tolerance < 0 {
+		return nil, algorithm.ErrWrongTolerance
+	}
+	return &Registry{
+		opts:    opts,
+		fences:  map[string]*fence{},
+		index:   rstartree.NewRStarTree(),
+		objects: map[string]*object{},
+	}, nil
+}
+
+// AddFence Adds a polygonal fence, a Polygon, a MultiPolygon, a Bound or a collection of them.
+func (r *Registry) AddFence(id string, geom space.Geometry) error {
+	if geom == nil || geom.IsEmpty() {
+		return spaceerr.ErrNilGeometry
+	}
+	locator, err := relate.NewIndexedPointInAreaLocator(geom.ToMatrix(), r.opts.Tolerance)
+	if err != nil {
+		return spaceerr.ErrNotPolygon
+	}
+	bound := geom.Bound()
+	f := &fence{
+		id:      id,
+		geom:    geom,
+		env:     envelope.TwoMatrix(matrix.Matrix(bound.Min), matrix.Matrix(bound.Max)),
+		locator: locator,
+	}
+	r.fencesMu.Lock()
+	defer r.fencesMu.Unlock()
+	if _, ok := r.fences[id]; ok {
+		return ErrFenceExists
+	}
+	r.fences[id] = f
+	return r.index.Insert(f.env, f)
+}
+
+// RemoveFence Removes a fence, the objects in it are forgotten without Exit events.
+func (r *Registry) RemoveFence(id string) bool {
+	r.fencesMu.Lock()
+	defer r.fencesMu.Unlock()
+	f, ok := r.fences[id]
+	if !ok {
+		return false
+	}
+	delete(r.fences, id)
+	r.index.Remove(f.env, f)
+	r.objectsMu.Lock()
+	defer r.objectsMu.Unlock()
+	for _, o := range r.objects {
+		delete(o.inside, id)
+	}
+	return true
+}
+
+// Fence Returns the geometry of a fence.
+func (r *Registry) Fence(id string) (space.Geometry, bool) {
+	r.fencesMu.RLock()
+	defer r.fencesMu.RUnlock()
+	f, ok := r.fences[id]
+	if !ok {
+		return nil, false
+	}
+	return f.geom, true
+}
+
+// Update Updates the position of an object at a time, and returns the events it causes:
+// the Exit events, then the Enter events, then the Dwell events, each ordered by fence id.
+// Updates of an object must be in time order, an older update returns ErrStaleUpdate.
+func (r *Registry) Update(objectID string, position space.Point, t time.Time) ([]Event, error) {
+	if position.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	r.fencesMu.RLock()
+	defer r.fencesMu.RUnlock()
+
+	env := envelope.Matrix(matrix.Matrix(position))
+	env.ExpandBy(r.opts.Tolerance)
+	locations := map[string]int{}
+	for _, item := range r.index.Query(env).([]interface{}) {
+		f := item.(*fence)
+		locations[f.id] = f.locator.Locate(matrix.Matrix(position))
+	}
+
+	r.objectsMu.Lock()
+	defer r.objectsMu.Unlock()
+	o, ok := r.objects[objectID]
+	if !ok {
+		o = &object{time: t, inside: map[string]*stay{}}
+		r.objects[objectID] = o
+	}
+	if t.Before(o.time) {
+		return nil, ErrStaleUpdate
+	}
+	o.time = t
+
+	var exits, enters, dwells []string
+	for id := range o.inside {
+		// positions in the tolerance band keep the object in.
+		if location, ok := locations[id]; !ok || location == calc.ImExterior {
+			exits = append(exits, id)
+			delete(o.inside, id)
+		}
+	}
+	for id, location := range locations {
+		if _, in := o.inside[id]; !in && location == calc.ImInterior {
+			enters = append(enters, id)
+			o.inside[id] = &stay{entered: t}
+		}
+	}
+	if r.opts.DwellTime > 0 {
+		for id, s := range o.inside {
+			if !s.dwelled && t.Sub(s.entered) >= r.opts.DwellTime {
+				dwells = append(dwells, id)
+				s.dwelled = true
+			}
+		}
+	}
+
+	events := []Event{}
+	for _, batch := range []struct {
+		eventType EventType
+		ids       []string
+	}{{Exit, exits}, {Enter, enters}, {Dwell, dwells}} {
+		sort.Strings(batch.ids)
+		for _, id := range batch.ids {
+			events = append(events, Event{Type: batch.eventType, ObjectID: objectID, FenceID: id, Time: t, Position: position})
+		}
+	}
+	return events, nil
+}
+
+// Inside Returns the ids of the fences an object is in, sorted.
+func (r *Registry) Inside(objectID string) []string {
+	r.objectsMu.Lock()
+	defer r.objectsMu.Unlock()
+	ids := []string{}
+	if o, ok := r.objects[objectID]; ok {
+		for id := range o.inside {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// RemoveObject Forgets an object, without Exit events.
+func (r *Registry) RemoveObject(objectID string) {
+	r.objectsMu.Lock()
+	defer r.objectsMu.Unlock()
+	delete(r.objects, objectID)
+}
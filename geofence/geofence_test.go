@@ -0,0 +1,140 @@
+package geofence
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+func square(minX, minY, size float64) space.Polygon {
+	return space.Polygon{{{minX, minY}, {minX + size, minY}, {minX + size, minY + size}, {minX, minY + size}, {minX, minY}}}
+}
+
+type event struct {
+	eventType EventType
+	fenceID   string
+}
+
+func TestRegistry_Update(t *testing.T) {
+	r, err := NewRegistry(Options{Tolerance: 0.5, DwellTime: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.AddFence("a", square(0, 0, 10))
+	_ = r.AddFence("b", square(10, 0, 10))
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		position space.Point
+		after    time.Duration
+		want     []event
+		inside   []string
+	}{
+		{"enter", space.Point{5, 5}, 0, []event{{Enter, "a"}}, []string{"a"}},
+		{"near boundary", space.Point{9.8, 5}, 10 * time.Second, []event{}, []string{"a"}},
+		{"across boundary", space.Point{10.2, 5}, 20 * time.Second, []event{}, []string{"a"}},
+		{"back", space.Point{9.7, 5}, 30 * time.Second, []event{}, []string{"a"}},
+		{"move", space.Point{15, 5}, 2 * time.Minute, []event{{Exit, "a"}, {Enter, "b"}}, []string{"b"}},
+		{"dwell", space.Point{15, 6}, 3 * time.Minute, []event{{Dwell, "b"}}, []string{"b"}},
+		{"dwell once", space.Point{15, 7}, 4 * time.Minute, []event{}, []string{"b"}},
+		{"leave", space.Point{30, 30}, 5 * time.Minute, []event{{Exit, "b"}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := r.Update("truck", tt.position, start.Add(tt.after))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []event{}
+			for _, e := range events {
+				if e.ObjectID != "truck" || !e.Time.Equal(start.Add(tt.after)) {
+					t.Errorf("Registry.Update() event = %+v", e)
+				}
+				got = append(got, event{e.Type, e.FenceID})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Registry.Update() = %v, want %v", got, tt.want)
+			}
+			if inside := r.Inside("truck"); !reflect.DeepEqual(inside, tt.inside) {
+				t.Errorf("Registry.Inside() = %v, want %v", inside, tt.inside)
+			}
+		})
+	}
+	if _, err := r.Update("truck", space.Point{5, 5}, start); err != ErrStaleUpdate {
+		t.Errorf("Registry.Update() error = %v, want %v", err, ErrStaleUpdate)
+	}
+}
+
+func TestRegistry_Fences(t *testing.T) {
+	r, _ := NewRegistry(Options{})
+	if err := r.AddFence("a", square(0, 0, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddFence("a", square(0, 0, 10)); err != ErrFenceExists {
+		t.Errorf("Registry.AddFence() error = %v, want %v", err, ErrFenceExists)
+	}
+	if err := r.AddFence("line", space.LineString{{0, 0}, {1, 1}}); err != spaceerr.ErrNotPolygon {
+		t.Errorf("Registry.AddFence() error = %v, want %v", err, spaceerr.ErrNotPolygon)
+	}
+	if err := r.AddFence("holes", space.MultiPolygon{
+		{{{20, 0}, {30, 0}, {30, 10}, {20, 10}, {20, 0}}, {{22, 2}, {28, 2}, {28, 8}, {22, 8}, {22, 2}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if events, _ := r.Update("car", space.Point{25, 5}, now); len(events) != 0 {
+		t.Errorf("Registry.Update() in hole = %v", events)
+	}
+	if events, _ := r.Update("car", space.Point{5, 5}, now); len(events) != 1 {
+		t.Errorf("Registry.Update() = %v", events)
+	}
+	if !r.RemoveFence("a") || r.RemoveFence("a") {
+		t.Errorf("Registry.RemoveFence() failed")
+	}
+	if inside := r.Inside("car"); len(inside) != 0 {
+		t.Errorf("Registry.Inside() = %v after remove", inside)
+	}
+	if events, _ := r.Update("car", space.Point{5, 6}, now); len(events) != 0 {
+		t.Errorf("Registry.Update() = %v after remove", events)
+	}
+	if _, ok := r.Fence("holes"); !ok {
+		t.Errorf("Registry.Fence() not found")
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	r, _ := NewRegistry(Options{Tolerance: 0.1})
+	for i := 0; i < 100; i++ {
+		_ = r.AddFence(fmt.Sprint(i), square(float64(i%10)*10, float64(i/10)*10, 10))
+	}
+	start := time.Now()
+	var wg sync.WaitGroup
+	counts := make([]int, 8)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			id := fmt.Sprint("object", w)
+			// a diagonal crossing 10 fences.
+			for i := 0; i < 100; i++ {
+				events, err := r.Update(id, space.Point{float64(i) + 0.5, float64(i) + 0.5}, start.Add(time.Duration(i)*time.Second))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				counts[w] += len(events)
+			}
+		}(w)
+	}
+	wg.Wait()
+	for w, count := range counts {
+		if count != 19 {
+			t.Errorf("object %v events = %v, want %v", w, count, 19)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package geohash
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/planar"
+	"github.com/spatial-go/geoos/space"
+)
+
+// Cover returns the geohash cells at the precision which intersect the geometry.
+func Cover(geom space.Geometry, precision int) ([]string, error) {
+	return CoverMixed(geom, precision, precision)
+}
+
+// CoverMixed returns a minimal set of geohash cells whose interiors intersect the geometry,
+// the cells which only touch the boundary of the geometry are left out.
+// Cells are refined from minPrecision down to maxPrecision,
+// a cell covered by the geometry is kept without refining,
+// and 32 sibling cells all kept are merged into their parent while the parent is not coarser than minPrecision.
+func CoverMixed(geom space.Geometry, minPrecision, maxPrecision int) ([]string, error) {
+	if minPrecision < MinPrecision || maxPrecision > MaxPrecision || minPrecision > maxPrecision {
+		return nil, ErrInvalidPrecision
+	}
+	if geom == nil || geom.IsEmpty() {
+		return nil, nil
+	}
+	c := &coverer{geom: geom, parts: parts(geom), maxPrecision: maxPrecision, strategy: planar.NormalStrategy()}
+	seeds, err := boundCells(geom.Bound(), minPrecision)
+	if err != nil {
+		return nil, err
+	}
+	for _, hash := range seeds {
+		if err := c.refine(hash); err != nil {
+			return nil, err
+		}
+	}
+	cells := compact(c.cells, minPrecision)
+	sort.Strings(cells)
+	return cells, nil
+}
+
+// coverer collects cells while refining.
+type coverer struct {
+	geom         space.Geometry
+	parts        []space.Geometry
+	maxPrecision int
+	strategy     planar.Algorithm
+	cells        []string
+}
+
+func (c *coverer) refine(hash string) error {
+	bound, err := Decode(hash)
+	if err != nil {
+		return err
+	}
+	cell := bound.ToPolygon()
+	if !bound.IntersectsBound(c.geom.Bound()) {
+		return nil
+	}
+	if c.geom.Dimensions() == 2 {
+		if ok, err := c.strategy.Covers(c.geom, cell); err != nil {
+			return err
+		} else if ok {
+			c.cells = append(c.cells, hash)
+			return nil
+		}
+	}
+	if ok, err := c.meetsInterior(hash, bound); err != nil || !ok {
+		return err
+	}
+	if len(hash) >= c.maxPrecision {
+		c.cells = append(c.cells, hash)
+		return nil
+	}
+	for _, child := range Children(hash) {
+		if err := c.refine(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// meetsInterior returns true if the geometry meets the interior of the cell, not only its boundary:
+// a polygon overlaps it with a positive area, a line with a positive length,
+// and a point is encoded to it.
+func (c *coverer) meetsInterior(hash string, bound space.Bound) (bool, error) {
+	cell := bound.ToPolygon()
+	for _, part := range c.parts {
+		if !bound.IntersectsBound(part.Bound()) {
+			continue
+		}
+		if point, ok := part.(space.Point); ok {
+			if h, err := Encode(point, len(hash)); err == nil && h == hash {
+				return true, nil
+			}
+			continue
+		}
+		if ok, err := c.strategy.Intersects(cell, part); err != nil {
+			return false, err
+		} else if !ok {
+			continue
+		}
+		intersection, err := c.strategy.Intersection(cell, part)
+		if err != nil {
+			return false, err
+		}
+		if intersection == nil || intersection.IsEmpty() {
+			continue
+		}
+		var measure float64
+		if part.Dimensions() == 2 {
+			measure, err = c.strategy.Area(intersection)
+		} else {
+			measure, err = c.strategy.Length(intersection)
+		}
+		if err != nil {
+			return false, err
+		}
+		if measure > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parts returns the single geometries of a multi geometry or collection.
+func parts(geom space.Geometry) []space.Geometry {
+	result := []space.Geometry{}
+	switch g := geom.(type) {
+	case space.MultiPoint:
+		for _, v := range g {
+			result = append(result, v)
+		}
+	case space.MultiLineString:
+		for _, v := range g {
+			result = append(result, v)
+		}
+	case space.MultiPolygon:
+		for _, v := range g {
+			result = append(result, v)
+		}
+	case space.Collection:
+		for _, v := range g {
+			result = append(result, parts(v)...)
+		}
+	default:
+		result = append(result, geom)
+	}
+	return result
+}
+
+// boundCells returns the cells at the precision which intersect the bound.
+func boundCells(bound space.Bound, precision int) ([]string, error) {
+	width, height := CellSize(precision)
+	minLon, minLat := math.Max(bound.Min.X(), -180), math.Max(bound.Min.Y(), -90)
+	maxLon, maxLat := math.Min(bound.Max.X(), 180), math.Min(bound.Max.Y(), 90)
+
+	startX := math.Min(math.Floor((minLon+180)/width), 360/width-1)
+	endX := math.Min(math.Floor((maxLon+180)/width), 360/width-1)
+	startY := math.Min(math.Floor((minLat+90)/height), 180/height-1)
+	endY := math.Min(math.Floor((maxLat+90)/height), 180/height-1)
+
+	cells := []string{}
+	for y := startY; y <= endY; y++ {
+		for x := startX; x <= endX; x++ {
+			center := space.Point{-180 + (x+0.5)*width, -90 + (y+0.5)*height}
+			hash, err := Encode(center, precision)
+			if err != nil {
+				return nil, err
+			}
+			cells = append(cells, hash)
+		}
+	}
+	return cells, nil
+}
+
+// compact merges complete sets of 32 sibling cells into their parent,
+// parents coarser than minPrecision are not produced.
+func compact(cells []string, minPrecision int) []string {
+	set := make(map[string]bool, len(cells))
+	for _, cell := range cells {
+		set[cell] = true
+	}
+	for {
+		parents := map[string]int{}
+		for cell := range set {
+			if len(cell) > minPrecision {
+				parents[cell[:len(cell)-1]]++
+			}
+		}
+		merged := false
+		for parent, count := range parents {
+			if count < len(base32) {
+				continue
+			}
+			for _, child := range Children(parent) {
+				delete(set, child)
+			}
+			set[parent] = true
+			merged = true
+		}
+		if !merged {
+			break
+		}
+	}
+	result := make([]string, 0, len(set))
+	for cell := range set {
+		result = append(result, cell)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package geohash
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+func TestCover(t *testing.T) {
+	type args struct {
+		geom      space.Geometry
+		precision int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "cover point", args: args{space.Point{-5.6, 42.6}, 5}, want: []string{"ezs42"}},
+		{name: "cover line", args: args{space.LineString{{1, 1}, {50, 1}}, 1}, want: []string{"s", "t"}},
+		{name: "cover polygon", args: args{space.Polygon{{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}, {-10, -10}}}, 1},
+			want: []string{"7", "e", "k", "s"}},
+		{name: "cover touching polygon", args: args{space.Polygon{{{0, 0}, {45, 0}, {45, 45}, {0, 45}, {0, 0}}}, 1},
+			want: []string{"s"}},
+		{name: "cover multi point", args: args{space.MultiPoint{{1, 1}, {-1, -1}}, 1}, want: []string{"7", "s"}},
+		{name: "cover east longitude", args: args{space.Point{180, 0}, 3}, want: []string{"xbp"}},
+		{name: "cover north latitude", args: args{space.Point{0, 90}, 3}, want: []string{"upb"}},
+		{name: "cover north east corner", args: args{space.Point{180, 90}, 2}, want: []string{"zz"}},
+		{name: "cover precision err", args: args{space.Point{0, 0}, 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Cover(tt.args.geom, tt.args.precision)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Cover() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cover() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoverMixed(t *testing.T) {
+	square := space.Polygon{{{0, 0}, {45, 0}, {45, 45}, {0, 45}, {0, 0}}}
+	type args struct {
+		geom                       space.Geometry
+		minPrecision, maxPrecision int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "mixed cell polygon", args: args{square, 1, 3}, want: []string{"s"}},
+		{name: "mixed cell polygon min precision", args: args{square, 2, 3},
+			want: []string{"s0", "s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8", "s9", "sb", "sc", "sd", "se", "sf", "sg",
+				"sh", "sj", "sk", "sm", "sn", "sp", "sq", "sr", "ss", "st", "su", "sv", "sw", "sx", "sy", "sz"}},
+		{name: "mixed half cell polygon", args: args{space.Polygon{{{0, 0}, {22.5, 0}, {22.5, 45}, {0, 45}, {0, 0}}}, 1, 3},
+			want: []string{"s0", "s1", "s2", "s3", "s4", "s5", "s6", "s7", "sh", "sj", "sk", "sm", "sn", "sp", "sq", "sr"}},
+		{name: "mixed polygon", args: args{space.Polygon{{{0, 0}, {12, 0}, {12, 6}, {0, 6}, {0, 0}}}, 1, 2},
+			want: []string{"s0", "s1", "s2", "s3"}},
+		{name: "mixed line", args: args{space.LineString{{1, 1}, {50, 1}}, 1, 2}, want: []string{"s0", "s2", "s8", "sb", "t0"}},
+		{name: "mixed point", args: args{space.Point{-5.6, 42.6}, 1, 5}, want: []string{"ezs42"}},
+		{name: "mixed precision err", args: args{square, 3, 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoverMixed(tt.args.geom, tt.args.minPrecision, tt.args.maxPrecision)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CoverMixed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CoverMixed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
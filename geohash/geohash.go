@@ -0,0 +1,228 @@
+// Package geohash encodes and decodes geohash strings,
+// include neighbours of a cell and covering a geometry with cells.
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+// const geohash parameter.
+const (
+	MinPrecision = 1
+	MaxPrecision = 12
+
+	base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// Direction of a neighbour cell.
+const (
+	North = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// ErrInvalidHash geohash is empty, too long or contains characters out of the base32 alphabet.
+var ErrInvalidHash = fmt.Errorf("geohash is invalid")
+
+// ErrInvalidPrecision precision is out of range [MinPrecision,MaxPrecision].
+var ErrInvalidPrecision = fmt.Errorf("geohash precision is out of range")
+
+// ErrInvalidPoint point has less than two coordinates.
+var ErrInvalidPoint = fmt.Errorf("point is not correct")
+
+// ErrOutOfRange longitude or latitude is out of range [-180,180]x[-90,90] or not a number.
+var ErrOutOfRange = fmt.Errorf("point is out of range")
+
+// ErrInvalidDirection direction is not one of North ... NorthWest.
+var ErrInvalidDirection = fmt.Errorf("direction is invalid")
+
+var decodeMap [256]int
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(base32); i++ {
+		decodeMap[base32[i]] = i
+	}
+}
+
+// Encode returns the geohash of the point at the given precision.
+// The point is longitude,latitude in [-180,180]x[-90,90].
+func Encode(point space.Point, precision int) (string, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return "", ErrInvalidPrecision
+	}
+	if !point.IsCorrect() {
+		return "", ErrInvalidPoint
+	}
+	lon, lat := point.Lon(), point.Lat()
+	// the negated comparisons are also true for NaN.
+	if !(lon >= -180 && lon <= 180 && lat >= -90 && lat <= 90) {
+		return "", ErrOutOfRange
+	}
+	lonMin, lonMax := -180.0, 180.0
+	latMin, latMax := -90.0, 90.0
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	isLon := true
+	bit, ch := 0, 0
+	for sb.Len() < precision {
+		if isLon {
+			mid := (lonMin + lonMax) / 2
+			if lon >= mid {
+				ch = ch<<1 | 1
+				lonMin = mid
+			} else {
+				ch <<= 1
+				lonMax = mid
+			}
+		} else {
+			mid := (latMin + latMax) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latMin = mid
+			} else {
+				ch <<= 1
+				latMax = mid
+			}
+		}
+		isLon = !isLon
+		if bit++; bit == 5 {
+			sb.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String(), nil
+}
+
+// Decode returns the bound of the cell identified by the geohash.
+func Decode(hash string) (space.Bound, error) {
+	if len(hash) < MinPrecision || len(hash) > MaxPrecision {
+		return space.Bound{}, ErrInvalidHash
+	}
+	lonMin, lonMax := -180.0, 180.0
+	latMin, latMax := -90.0, 90.0
+	isLon := true
+	for i := 0; i < len(hash); i++ {
+		ch := decodeMap[hash[i]]
+		if ch < 0 {
+			return space.Bound{}, ErrInvalidHash
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			if isLon {
+				mid := (lonMin + lonMax) / 2
+				if ch&mask != 0 {
+					lonMin = mid
+				} else {
+					lonMax = mid
+				}
+			} else {
+				mid := (latMin + latMax) / 2
+				if ch&mask != 0 {
+					latMin = mid
+				} else {
+					latMax = mid
+				}
+			}
+			isLon = !isLon
+		}
+	}
+	return space.Bound{Min: space.Point{lonMin, latMin}, Max: space.Point{lonMax, latMax}}, nil
+}
+
+// Center returns the centre point of the cell identified by the geohash.
+func Center(hash string) (space.Point, error) {
+	bound, err := Decode(hash)
+	if err != nil {
+		return nil, err
+	}
+	return space.Point{(bound.Min.X() + bound.Max.X()) / 2, (bound.Min.Y() + bound.Max.Y()) / 2}, nil
+}
+
+// CellSize returns the width (longitude degrees) and height (latitude degrees) of a cell at the precision.
+func CellSize(precision int) (width, height float64) {
+	bits := 5 * precision
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 360.0 / math.Exp2(float64(lonBits)), 180.0 / math.Exp2(float64(latBits))
+}
+
+// Neighbour returns the adjacent cell of the geohash in the direction.
+// Longitude wraps around the antimeridian, an empty string is returned beyond the poles.
+func Neighbour(hash string, direction int) (string, error) {
+	center, err := Center(hash)
+	if err != nil {
+		return "", err
+	}
+	width, height := CellSize(len(hash))
+	lon, lat := center.Lon(), center.Lat()
+	switch direction {
+	case North:
+		lat += height
+	case NorthEast:
+		lon, lat = lon+width, lat+height
+	case East:
+		lon += width
+	case SouthEast:
+		lon, lat = lon+width, lat-height
+	case South:
+		lat -= height
+	case SouthWest:
+		lon, lat = lon-width, lat-height
+	case West:
+		lon -= width
+	case NorthWest:
+		lon, lat = lon-width, lat+height
+	default:
+		return "", ErrInvalidDirection
+	}
+	if lat > 90 || lat < -90 {
+		return "", nil
+	}
+	return Encode(space.Point{wrapLongitude(lon), lat}, len(hash))
+}
+
+// Neighbours returns the 8 adjacent cells of the geohash,
+// in the order North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest.
+func Neighbours(hash string) ([]string, error) {
+	neighbours := make([]string, 8)
+	for direction := North; direction <= NorthWest; direction++ {
+		n, err := Neighbour(hash, direction)
+		if err != nil {
+			return nil, err
+		}
+		neighbours[direction] = n
+	}
+	return neighbours, nil
+}
+
+// Children returns the 32 cells of the next precision inside the geohash.
+func Children(hash string) []string {
+	children := make([]string, len(base32))
+	for i := 0; i < len(base32); i++ {
+		children[i] = hash + string(base32[i])
+	}
+	return children
+}
+
+// wrapLongitude normalizes longitude into [-180,180).
+func wrapLongitude(lon float64) float64 {
+	for lon >= 180 {
+		lon -= 360
+	}
+	for lon < -180 {
+		lon += 360
+	}
+	return lon
+}
@@ -0,0 +1,109 @@
+package geohash
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+func TestEncode(t *testing.T) {
+	type args struct {
+		point     space.Point
+		precision int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "encode 5", args: args{space.Point{-5.6, 42.6}, 5}, want: "ezs42"},
+		{name: "encode 11", args: args{space.Point{10.40744, 57.64911}, 11}, want: "u4pruydqqvj"},
+		{name: "encode origin", args: args{space.Point{0, 0}, 1}, want: "s"},
+		{name: "encode precision err", args: args{space.Point{0, 0}, 13}, wantErr: true},
+		{name: "encode point err", args: args{space.Point{0}, 5}, wantErr: true},
+		{name: "encode corner", args: args{space.Point{180, 90}, 2}, want: "zz"},
+		{name: "encode longitude err", args: args{space.Point{180.1, 0}, 5}, wantErr: true},
+		{name: "encode latitude err", args: args{space.Point{0, -90.1}, 5}, wantErr: true},
+		{name: "encode nan err", args: args{space.Point{math.NaN(), 0}, 5}, wantErr: true},
+		{name: "encode inf err", args: args{space.Point{0, math.Inf(1)}, 5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.args.point, tt.args.precision)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Encode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    string
+		want    space.Bound
+		wantErr bool
+	}{
+		{name: "decode s", hash: "s", want: space.Bound{Min: space.Point{0, 0}, Max: space.Point{45, 45}}},
+		{name: "decode 7", hash: "7", want: space.Bound{Min: space.Point{-45, -45}, Max: space.Point{0, 0}}},
+		{name: "decode err", hash: "sa", wantErr: true},
+		{name: "decode empty", hash: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.EqualsBound(tt.want) {
+				t.Errorf("Decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	point := space.Point{116.3912, 39.9075}
+	for precision := MinPrecision; precision <= MaxPrecision; precision++ {
+		hash, _ := Encode(point, precision)
+		bound, _ := Decode(hash)
+		if !bound.Contains(point) {
+			t.Errorf("Decode(%v) = %v, not contains %v", hash, bound, point)
+		}
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "neighbours", hash: "ezs42",
+			want: []string{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"}},
+		{name: "neighbours antimeridian", hash: "2",
+			want: []string{"8", "9", "3", "1", "0", "p", "r", "x"}},
+		{name: "neighbours pole", hash: "b",
+			want: []string{"", "", "c", "9", "8", "x", "z", ""}},
+		{name: "neighbours err", hash: "a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Neighbours(tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Neighbours() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Neighbours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
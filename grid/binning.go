@@ -0,0 +1,196 @@
+package grid
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/clusters"
+	"github.com/spatial-go/geoos/geoencoding/geojson"
+	"github.com/spatial-go/geoos/index/quadtree"
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+// Property names of the statistics of a bin feature.
+const (
+	PropertyCount = "count"
+	PropertySum   = "sum"
+	PropertyMean  = "mean"
+	PropertyMin   = "min"
+	PropertyMax   = "max"
+)
+
+// ErrValuesLength values must be nil or as many as points.
+var ErrValuesLength = fmt.Errorf("values and points must have the same length")
+
+// Statistics of the points inside a cell.
+// Sum, Mean, Min and Max are of the values of the points, they are zero when no values are given.
+type Statistics struct {
+	Count               int
+	Sum, Mean, Min, Max float64
+}
+
+// add adds a point with the value to the statistics.
+func (s *Statistics) add(value float64, hasValue bool) {
+	s.Count++
+	if !hasValue {
+		return
+	}
+	if s.Count == 1 {
+		s.Min, s.Max = value, value
+	} else {
+		s.Min, s.Max = math.Min(s.Min, value), math.Max(s.Max, value)
+	}
+	s.Sum += value
+	s.Mean = s.Sum / float64(s.Count)
+}
+
+// Bin is a grid cell with the statistics of the points inside it.
+type Bin struct {
+	Grid
+	Statistics
+}
+
+// Bins is a slice of Bin.
+type Bins []Bin
+
+// Flatten returns the cells of a grid column by column.
+func Flatten(gridGeoms [][]Grid) []Grid {
+	cells := []Grid{}
+	for _, rows := range gridGeoms {
+		cells = append(cells, rows...)
+	}
+	return cells
+}
+
+// BinPoints aggregates the points into the cells.
+// values are the numeric values of the points, nil for counting only.
+// A point inside several cells (on a shared edge) is counted once, in the first of them.
+func BinPoints(cells []Grid, points clusters.PointList, values []float64) (Bins, error) {
+	if values != nil && len(values) != len(points) {
+		return nil, ErrValuesLength
+	}
+	binner, err := newBinner(cells)
+	if err != nil {
+		return nil, err
+	}
+	for i, point := range points {
+		if values != nil {
+			binner.add(point, values[i], true)
+		} else {
+			binner.add(point, 0, false)
+		}
+	}
+	return binner.bins, nil
+}
+
+// BinFeatures aggregates the point features into the cells,
+// and returns a FeatureCollection of cells with the statistics of the property as properties.
+// Features without a numeric property are skipped, unless property is empty for counting only.
+// Features must be points or multipoints.
+func BinFeatures(cells []Grid, fc *geojson.FeatureCollection, property string) (*geojson.FeatureCollection, error) {
+	binner, err := newBinner(cells)
+	if err != nil {
+		return nil, err
+	}
+	for _, feature := range fc.Features {
+		value, hasValue := 0.0, false
+		if property != "" {
+			if value, hasValue = numericProperty(feature.Properties, property); !hasValue {
+				continue
+			}
+		}
+		switch geom := feature.Geometry.Geometry().(type) {
+		case space.Point:
+			binner.add(geom, value, hasValue)
+		case space.MultiPoint:
+			for _, point := range geom {
+				binner.add(point, value, hasValue)
+			}
+		default:
+			return nil, spaceerr.ErrNotSupportGeometry
+		}
+	}
+	return binner.bins.FeatureCollection(), nil
+}
+
+// FeatureCollection returns a FeatureCollection of the cells with the statistics as properties,
+// the id of a feature is the index of its cell.
+func (b Bins) FeatureCollection() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for i, bin := range b {
+		feature := geojson.NewFeature(*geojson.NewGeometry(bin.Geometry))
+		feature.ID = i
+		feature.Properties[PropertyCount] = bin.Count
+		if bin.Count > 0 {
+			feature.Properties[PropertySum] = bin.Sum
+			feature.Properties[PropertyMean] = bin.Mean
+			feature.Properties[PropertyMin] = bin.Min
+			feature.Properties[PropertyMax] = bin.Max
+		}
+		fc.Append(feature)
+	}
+	return fc
+}
+
+// binner locates points in cells with a quadtree over the cell envelopes.
+type binner struct {
+	bins     Bins
+	polygons [][]matrix.PolygonMatrix
+	tree     *quadtree.Quadtree
+}
+
+func newBinner(cells []Grid) (*binner, error) {
+	b := &binner{
+		bins:     make(Bins, len(cells)),
+		polygons: make([][]matrix.PolygonMatrix, len(cells)),
+		tree:     quadtree.NewQuadtree(),
+	}
+	for i, cell := range cells {
+		polygons, err := polygonMatrixes(cell.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		b.bins[i].Grid = cell
+		b.polygons[i] = polygons
+		bound := cell.Geometry.Bound()
+		if err := b.tree.Insert(envelope.TwoMatrix(matrix.Matrix(bound.Min), matrix.Matrix(bound.Max)), i); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// add adds the point to the first cell containing it.
+func (b *binner) add(point space.Point, value float64, hasValue bool) {
+	candidates, _ := b.tree.Query(envelope.Matrix(matrix.Matrix(point))).([]interface{})
+	indexes := make([]int, 0, len(candidates))
+	for _, c := range candidates {
+		indexes = append(indexes, c.(int))
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		if inPolygons(matrix.Matrix(point), b.polygons[i]) {
+			b.bins[i].add(value, hasValue)
+			return
+		}
+	}
+}
+
+// numericProperty returns the property as float64 if it is a number.
+func numericProperty(properties geojson.Properties, key string) (float64, bool) {
+	switch v := properties[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/spatial-go/geoos/clusters"
+	"github.com/spatial-go/geoos/geoencoding/geojson"
+	"github.com/spatial-go/geoos/space"
+)
+
+func TestBinPoints(t *testing.T) {
+	cells := Flatten(squareGrid(space.Bound{Min: space.Point{0, 0}, Max: space.Point{4, 4}}, 2, 2))
+	points := clusters.PointList{{0.5, 0.5}, {1.5, 1.5}, {2.5, 0.5}, {3.5, 3.5}, {3, 3}, {2, 1}, {9, 9}}
+	values := []float64{1, 2, 3, 4, 5, 6, 7}
+	bins, err := BinPoints(cells, points, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cells are column by column: (0,0) (0,1) (1,0) (1,1)
+	want := []Statistics{
+		{Count: 2, Sum: 3, Mean: 1.5, Min: 1, Max: 2},
+		{Count: 0},
+		{Count: 2, Sum: 9, Mean: 4.5, Min: 3, Max: 6},
+		{Count: 2, Sum: 9, Mean: 4.5, Min: 4, Max: 5},
+	}
+	total := 0
+	for i, bin := range bins {
+		total += bin.Count
+		if bin.Statistics != want[i] {
+			t.Errorf("BinPoints() bin %v = %v, want %v", i, bin.Statistics, want[i])
+		}
+	}
+	if total != 6 {
+		t.Errorf("BinPoints() total = %v, want %v", total, 6)
+	}
+	if _, err := BinPoints(cells, points, values[:2]); err == nil {
+		t.Errorf("BinPoints() error = nil, want %v", ErrValuesLength)
+	}
+}
+
+func TestBinFeatures(t *testing.T) {
+	cells := Flatten(squareGrid(space.Bound{Min: space.Point{0, 0}, Max: space.Point{4, 4}}, 2, 2))
+	fc := geojson.NewFeatureCollection()
+	for i, geom := range []space.Geometry{space.Point{0.5, 0.5}, space.MultiPoint{{1, 1}, {3, 3}}, space.Point{3, 1}, space.Point{3.5, 1}} {
+		feature := geojson.NewFeature(*geojson.NewGeometry(geom))
+		if i != 3 {
+			feature.Properties["speed"] = float64(10 * (i + 1))
+		}
+		fc.Append(feature)
+	}
+	got, err := BinFeatures(cells, fc, "speed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Features) != 4 {
+		t.Fatalf("BinFeatures() len = %v, want %v", len(got.Features), 4)
+	}
+	first := got.Features[0].Properties
+	if first.MustInt(PropertyCount) != 2 || first.MustFloat64(PropertyMean) != 15 || first.MustFloat64(PropertyMax) != 20 {
+		t.Errorf("BinFeatures() properties = %v", first)
+	}
+	if _, ok := got.Features[1].Properties[PropertyMean]; ok {
+		t.Errorf("BinFeatures() empty cell has mean %v", got.Features[1].Properties)
+	}
+	if got.Features[2].Properties.MustInt(PropertyCount) != 1 {
+		t.Errorf("BinFeatures() properties = %v", got.Features[2].Properties)
+	}
+
+	counts, _ := BinFeatures(cells, fc, "")
+	if counts.Features[2].Properties.MustInt(PropertyCount) != 2 {
+		t.Errorf("BinFeatures() properties = %v", counts.Features[2].Properties)
+	}
+
+	fc.Append(geojson.NewFeature(*geojson.NewGeometry(space.LineString{{0, 0}, {1, 1}})))
+	if _, err := BinFeatures(cells, fc, ""); err == nil {
+		t.Errorf("BinFeatures() error = nil, want not support")
+	}
+}
@@ -0,0 +1,77 @@
+package grid
+
+import (
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/graph/clipping"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/space"
+)
+
+// clipToConvex clips the polygonal mask to the convex cell.
+// The mask is split by the ring of the cell, the parts inside the cell are its intersection with the mask,
+// parts of zero area are dropped, a nil geometry is returned if nothing is left.
+func clipToConvex(mask space.Geometry, cell space.Ring) space.Geometry {
+	parts, err := clipping.Split(mask.ToMatrix(), matrix.LineMatrix(cell))
+	if err != nil {
+		return nil
+	}
+	sign := 1.0
+	if signedArea(cell) < 0 {
+		sign = -1.0
+	}
+	result := space.MultiPolygon{}
+	for _, part := range parts {
+		polygon, ok := part.(matrix.PolygonMatrix)
+		if !ok || len(polygon) == 0 || !inConvex(polygon[0], cell, sign) {
+			continue
+		}
+		area := 0.0
+		for i, ring := range polygon {
+			if i == 0 {
+				area += math.Abs(signedArea(space.Ring(ring)))
+			} else {
+				area -= math.Abs(signedArea(space.Ring(ring)))
+			}
+		}
+		if area > 0 {
+			result = append(result, space.Polygon(polygon))
+		}
+	}
+	switch len(result) {
+	case 0:
+		return nil
+	case 1:
+		return result[0]
+	}
+	return result
+}
+
+// inConvex returns true if the points of the ring are inside or on the convex window,
+// the parts of the mask split by the window are either inside it or have a point outside it.
+func inConvex(ring matrix.LineMatrix, window space.Ring, sign float64) bool {
+	for i := 0; i < len(window)-1; i++ {
+		a, b := window[i], window[i+1]
+		tolerance := 1e-9 * (math.Abs(b[0]-a[0]) + math.Abs(b[1]-a[1]))
+		for _, p := range ring {
+			if sign*cross(a, b, p) < -tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cross returns the cross product of ab and ap, positive if p is left of ab.
+func cross(a, b, p space.Point) float64 {
+	return (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+}
+
+// signedArea returns the signed area of the ring, positive if it is counter-clockwise.
+func signedArea(ring space.Ring) float64 {
+	area := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
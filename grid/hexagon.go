@@ -3,8 +3,6 @@ package grid
 import (
 	"math"
 
-	"github.com/spatial-go/geoos/algorithm/matrix"
-	"github.com/spatial-go/geoos/algorithm/measure"
 	"github.com/spatial-go/geoos/space"
 )
 
@@ -17,6 +15,12 @@ const (
 
 // HexagonGrid Draw a grid according to the distance, including the given area
 func HexagonGrid(bound space.Bound, cellSize float64) (gridGeoms [][]Grid) {
+	cellWidth, cellHeight := cellSizeInDegree(bound, cellSize)
+	return hexagonGrid(bound, cellWidth, cellHeight)
+}
+
+// hexagonGrid Draw a hexagon grid of cellWidth and cellHeight in the units of the bound.
+func hexagonGrid(bound space.Bound, cellWidth, cellHeight float64) (gridGeoms [][]Grid) {
 	var (
 		minPoint = bound.Min
 		maxPoint = bound.Max
@@ -31,10 +35,6 @@ func HexagonGrid(bound space.Bound, cellSize float64) (gridGeoms [][]Grid) {
 	boundHeight := north - south
 	boundWidth := east - west
 
-	// Calculate the latitude and longitude corresponding to the length cellSize.
-	cellHeight := cellSize * (boundHeight / measure.SpheroidDistance(matrix.Matrix{west, north}, matrix.Matrix{west, south}))
-	cellWidth := cellSize * (boundWidth / measure.SpheroidDistance(matrix.Matrix{west, south}, matrix.Matrix{east, south}))
-
 	// Get the number of rows and columns of the grid to be drawn in the bound range
 	columns := math.Ceil(boundWidth/(cellHeight+cellHeight*Cos60) + 1)
 	rows := math.Ceil(boundHeight/(2*cellWidth*Sin60) + 1)
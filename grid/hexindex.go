@@ -0,0 +1,422 @@
+package grid
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/algorithm/relate"
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+// const hexagon index parameter.
+const (
+	// MaxResolution is the finest resolution of the hexagon index.
+	MaxResolution = 15
+
+	// EdgeLengthRes0 is the hexagon edge length in meters at resolution 0,
+	// each finer resolution divides it by sqrt(7).
+	// It puts exactly EquatorCellsRes0 cells along the equator, so the lattice repeats every 360 degrees.
+	EdgeLengthRes0 = 2 * math.Pi * measure.R / EquatorCellsRes0 / 1.7320508075688772
+
+	// EquatorCellsRes0 is the number of cells along the equator at resolution 0,
+	// each finer resolution multiplies it by 7 along a rotated row.
+	EquatorCellsRes0 = 21
+
+	// InvalidCell is the zero value of CellID and is never a valid cell.
+	InvalidCell CellID = 0
+
+	cellFlag      = uint64(1) << 62
+	cellResShift  = 58
+	cellQShift    = 29
+	cellCoordBits = 29
+	cellCoordMask = uint64(1)<<cellCoordBits - 1
+	cellOffset    = int64(1) << (cellCoordBits - 1)
+)
+
+var (
+	sqrt3 = math.Sqrt(3)
+	sqrt7 = math.Sqrt(7)
+
+	// rotation of the lattice between two successive resolutions, arg(2+e^(i*pi/3)).
+	apertureAngle = math.Atan2(sqrt3/2, 2.5)
+
+	// axial offsets of the six neighbours of a hexagon.
+	hexDirections = [6][2]int64{{1, 0}, {0, 1}, {-1, 1}, {-1, 0}, {0, -1}, {1, -1}}
+)
+
+// ErrInvalidResolution resolution is out of range [0,MaxResolution].
+var ErrInvalidResolution = fmt.Errorf("resolution is out of range")
+
+// ErrInvalidCell cell id is not a valid hexagon cell.
+var ErrInvalidCell = fmt.Errorf("cell id is not valid")
+
+// CellID is a global hierarchical hexagon cell identifier.
+// Cells are laid out on the Lambert cylindrical equal-area projection of the sphere,
+// so that all cells of a resolution have the same area.
+// Each resolution is an aperture 7 refinement of the coarser one:
+// every cell has exactly 7 children, the centre child sharing the centre of its parent.
+// Ids are stable, the same point always maps to the same cell at a resolution.
+// The lattice repeats every 360 degrees of longitude, a cell is identified by its copy
+// whose centre lies in longitudes [-180,180), so that the hierarchy and neighbours continue across the antimeridian.
+type CellID uint64
+
+// PointToCell returns the cell containing the point (longitude,latitude) at the resolution.
+func PointToCell(point space.Point, resolution int) (CellID, error) {
+	if resolution < 0 || resolution > MaxResolution {
+		return InvalidCell, ErrInvalidResolution
+	}
+	if !point.IsCorrect() {
+		return InvalidCell, spaceerr.ErrNotSupportGeometry
+	}
+	x, y := projectEqualArea(point)
+	q, r := pixelToAxial(x, y, resolution)
+	return wrapCell(resolution, q, r), nil
+}
+
+// CellFromString parses a cell id from its hexadecimal form.
+func CellFromString(s string) (CellID, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return InvalidCell, err
+	}
+	c := CellID(v)
+	if !c.IsValid() {
+		return InvalidCell, ErrInvalidCell
+	}
+	return c, nil
+}
+
+// String returns the hexadecimal form of the cell id.
+func (c CellID) String() string {
+	return strconv.FormatUint(uint64(c), 16)
+}
+
+// IsValid returns true if the cell id is valid.
+func (c CellID) IsValid() bool {
+	return uint64(c)>>(cellResShift+4) == cellFlag>>(cellResShift+4) && c.Resolution() <= MaxResolution
+}
+
+// Resolution returns the resolution of the cell.
+func (c CellID) Resolution() int {
+	return int(uint64(c) >> cellResShift & 0xF)
+}
+
+// Center returns the centre point (longitude,latitude) of the cell.
+func (c CellID) Center() space.Point {
+	q, r := c.axial()
+	x, y := axialToPixel(float64(q), float64(r), c.Resolution())
+	return unprojectEqualArea(x, y)
+}
+
+// Boundary returns the hexagon of the cell as a polygon in longitude,latitude,
+// along the antimeridian its longitudes may go beyond -180 or 180.
+func (c CellID) Boundary() space.Polygon {
+	q, r := c.axial()
+	res := c.Resolution()
+	x, y := axialToPixel(float64(q), float64(r), res)
+	radius := cellSpacing(res) / sqrt3
+	rotation := latticeRotation(res)
+	ring := make(space.Ring, 0, 7)
+	for i := 0; i < 6; i++ {
+		angle := rotation + math.Pi/6 + float64(i)*math.Pi/3
+		ring = append(ring, unprojectEqualArea(x+radius*math.Cos(angle), y+radius*math.Sin(angle)))
+	}
+	ring = append(ring, ring[0])
+	return space.Polygon{ring}
+}
+
+// Area returns the area of cells at the resolution in square meters.
+func Area(resolution int) float64 {
+	d := cellSpacing(resolution)
+	return d * d * sqrt3 / 2
+}
+
+// Parent returns the ancestor of the cell at the coarser resolution.
+func (c CellID) Parent(resolution int) (CellID, error) {
+	res := c.Resolution()
+	if resolution < 0 || resolution > res {
+		return InvalidCell, ErrInvalidResolution
+	}
+	q, r := c.axial()
+	for ; res > resolution; res-- {
+		// divide by 2+e^(i*pi/3) in the complex plane and round back to the lattice.
+		x, y := float64(q)+float64(r)/2, float64(r)*sqrt3/2
+		px, py := (x*2.5+y*sqrt3/2)/7, (y*2.5-x*sqrt3/2)/7
+		q, r = axialRound(px-py/sqrt3, py*2/sqrt3)
+	}
+	return wrapCell(resolution, q, r), nil
+}
+
+// Children returns the descendants of the cell at the finer resolution.
+func (c CellID) Children(resolution int) ([]CellID, error) {
+	res := c.Resolution()
+	if resolution < res || resolution > MaxResolution {
+		return nil, ErrInvalidResolution
+	}
+	q, r := c.axial()
+	cells := [][2]int64{{q, r}}
+	for ; res < resolution; res++ {
+		next := make([][2]int64, 0, len(cells)*7)
+		for _, cell := range cells {
+			// multiply by 2+e^(i*pi/3), the centre child shares the centre of its parent.
+			cq, cr := 2*cell[0]-cell[1], cell[0]+3*cell[1]
+			next = append(next, [2]int64{cq, cr})
+			for _, d := range hexDirections {
+				next = append(next, [2]int64{cq + d[0], cr + d[1]})
+			}
+		}
+		cells = next
+	}
+	children := make([]CellID, len(cells))
+	for i, cell := range cells {
+		children[i] = wrapCell(resolution, cell[0], cell[1])
+	}
+	return children, nil
+}
+
+// Neighbours returns the six cells adjacent to the cell.
+func (c CellID) Neighbours() []CellID {
+	q, r := c.axial()
+	res := c.Resolution()
+	neighbours := make([]CellID, 6)
+	for i, d := range hexDirections {
+		neighbours[i] = wrapCell(res, q+d[0], r+d[1])
+	}
+	return neighbours
+}
+
+// KRing returns the cells within grid distance k of the cell, the cell itself included.
+func (c CellID) KRing(k int) []CellID {
+	q, r := c.axial()
+	res := c.Resolution()
+	n := int64(k)
+	cells := make([]CellID, 0, 3*k*(k+1)+1)
+	added := map[CellID]bool{}
+	for dq := -n; dq <= n; dq++ {
+		for dr := maxInt64(-n, -dq-n); dr <= minInt64(n, -dq+n); dr++ {
+			// a ring around the whole globe meets itself.
+			if cell := wrapCell(res, q+dq, r+dr); !added[cell] {
+				added[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+// GridDistance returns the number of steps between two cells of the same resolution,
+// the shorter way around the globe.
+func (c CellID) GridDistance(other CellID) (int, error) {
+	if c.Resolution() != other.Resolution() {
+		return 0, ErrInvalidResolution
+	}
+	q1, r1 := c.axial()
+	q2, r2 := other.axial()
+	pq, pr := period(c.Resolution())
+	distance := int64(math.MaxInt64)
+	for k := int64(-1); k <= 1; k++ {
+		dq, dr := q1-q2+k*pq, r1-r2+k*pr
+		distance = minInt64(distance, (absInt64(dq)+absInt64(dr)+absInt64(dq+dr))/2)
+	}
+	return int(distance), nil
+}
+
+// Polyfill returns the cells at the resolution whose centres lie inside the polygonal geometry.
+func Polyfill(geom space.Geometry, resolution int) ([]CellID, error) {
+	if resolution < 0 || resolution > MaxResolution {
+		return nil, ErrInvalidResolution
+	}
+	polygons, err := polygonMatrixes(geom)
+	if err != nil {
+		return nil, err
+	}
+	bound := geom.Bound()
+	minQ, minR := int64(math.MaxInt64), int64(math.MaxInt64)
+	maxQ, maxR := int64(math.MinInt64), int64(math.MinInt64)
+	for _, corner := range []space.Point{bound.Min, bound.Max, bound.LeftTop(), bound.RightBottom()} {
+		x, y := projectEqualArea(corner)
+		q, r := pixelToAxial(x, y, resolution)
+		minQ, maxQ = minInt64(minQ, q-1), maxInt64(maxQ, q+1)
+		minR, maxR = minInt64(minR, r-1), maxInt64(maxR, r+1)
+	}
+	cells := []CellID{}
+	added := map[CellID]bool{}
+	for q := minQ; q <= maxQ; q++ {
+		for r := minR; r <= maxR; r++ {
+			// the centres beyond the antimeridian are those of the cells across it.
+			center := unprojectEqualArea(axialToPixel(float64(q), float64(r), resolution))
+			if !bound.Contains(center) {
+				continue
+			}
+			if cell := wrapCell(resolution, q, r); !added[cell] && inPolygons(matrix.Matrix(center), polygons) {
+				added[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells, nil
+}
+
+// polygonMatrixes returns the polygons of a polygonal geometry.
+func polygonMatrixes(geom space.Geometry) ([]matrix.PolygonMatrix, error) {
+	switch g := geom.(type) {
+	case space.Polygon:
+		return []matrix.PolygonMatrix{g.ToMatrix().(matrix.PolygonMatrix)}, nil
+	case space.MultiPolygon:
+		polygons := make([]matrix.PolygonMatrix, 0, len(g))
+		for _, p := range g {
+			polygons = append(polygons, p.ToMatrix().(matrix.PolygonMatrix))
+		}
+		return polygons, nil
+	case space.Bound:
+		return []matrix.PolygonMatrix{g.ToMatrix().(matrix.PolygonMatrix)}, nil
+	case *space.Circle:
+		return []matrix.PolygonMatrix{g.Polygon.ToMatrix().(matrix.PolygonMatrix)}, nil
+	}
+	return nil, spaceerr.ErrNotPolygon
+}
+
+// inPolygons returns true if the point is inside the shell and outside the holes of any polygon.
+func inPolygons(point matrix.Matrix, polygons []matrix.PolygonMatrix) bool {
+	for _, polygon := range polygons {
+		if len(polygon) == 0 || !relate.InPolygon(point, matrix.LineMatrix(polygon[0])) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if relate.InPolygon(point, matrix.LineMatrix(hole)) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapCell returns the cell at the axial coordinates moved by whole turns of the globe
+// until its centre lies in longitudes [-180,180).
+func wrapCell(resolution int, q, r int64) CellID {
+	a, b := rotationUnit(resolution)
+	pq, pr := period(resolution)
+	// the centre x over half the equator length is num/half, exactly in integers:
+	// num is twice the real part of (q+r*w)*conj(a+b*w) with w=e^(i*pi/3), half is 21*|a+b*w|^2.
+	num := 2*q*a + 2*r*b + q*b + r*a
+	half := EquatorCellsRes0 * (a*a + a*b + b*b)
+	turns := floorDiv(num+half, 2*half)
+	return newCellID(resolution, q-turns*pq, r-turns*pr)
+}
+
+// period returns the axial coordinates of the lattice vector of one turn of the globe at the resolution.
+func period(resolution int) (int64, int64) {
+	a, b := rotationUnit(resolution)
+	return EquatorCellsRes0 * a, EquatorCellsRes0 * b
+}
+
+// rotationUnit returns the axial coordinates of (2+e^(i*pi/3))^resolution,
+// the lattice vector at the resolution of the unit step along the equator at resolution 0.
+func rotationUnit(resolution int) (a, b int64) {
+	a, b = 1, 0
+	for i := 0; i < resolution; i++ {
+		a, b = 2*a-b, a+3*b
+	}
+	return
+}
+
+func floorDiv(a, b int64) int64 {
+	d := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		d--
+	}
+	return d
+}
+
+func newCellID(resolution int, q, r int64) CellID {
+	return CellID(cellFlag | uint64(resolution)<<cellResShift |
+		(uint64(q+cellOffset)&cellCoordMask)<<cellQShift | uint64(r+cellOffset)&cellCoordMask)
+}
+
+func (c CellID) axial() (q, r int64) {
+	q = int64(uint64(c)>>cellQShift&cellCoordMask) - cellOffset
+	r = int64(uint64(c)&cellCoordMask) - cellOffset
+	return
+}
+
+// cellSpacing returns the distance between the centres of two adjacent cells at the resolution.
+func cellSpacing(resolution int) float64 {
+	return EdgeLengthRes0 * sqrt3 / math.Pow(sqrt7, float64(resolution))
+}
+
+// latticeRotation returns the rotation of the lattice axes at the resolution.
+func latticeRotation(resolution int) float64 {
+	return -float64(resolution) * apertureAngle
+}
+
+// axialToPixel converts axial coordinates to projected coordinates.
+func axialToPixel(q, r float64, resolution int) (x, y float64) {
+	d := cellSpacing(resolution)
+	u, v := (q+r/2)*d, r*sqrt3/2*d
+	sin, cos := math.Sincos(latticeRotation(resolution))
+	return u*cos - v*sin, u*sin + v*cos
+}
+
+// pixelToAxial converts projected coordinates to the axial coordinates of the containing cell.
+func pixelToAxial(x, y float64, resolution int) (q, r int64) {
+	d := cellSpacing(resolution)
+	sin, cos := math.Sincos(-latticeRotation(resolution))
+	u, v := (x*cos-y*sin)/d, (x*sin+y*cos)/d
+	fr := v * 2 / sqrt3
+	return axialRound(u-fr/2, fr)
+}
+
+// axialRound rounds fractional axial coordinates to the nearest hexagon.
+func axialRound(q, r float64) (int64, int64) {
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	if dq > dr && dq > ds {
+		rq = -rr - rs
+	} else if dr > ds {
+		rr = -rq - rs
+	}
+	return int64(rq), int64(rr)
+}
+
+// projectEqualArea projects longitude,latitude to the Lambert cylindrical equal-area projection in meters.
+func projectEqualArea(point space.Point) (x, y float64) {
+	lat := math.Max(-90, math.Min(90, point.Lat()))
+	return measure.R * point.Lon() * math.Pi / 180, measure.R * math.Sin(lat*math.Pi/180)
+}
+
+// unprojectEqualArea is the inverse of projectEqualArea.
+func unprojectEqualArea(x, y float64) space.Point {
+	s := math.Max(-1, math.Min(1, y/measure.R))
+	return space.Point{x / measure.R * 180 / math.Pi, math.Asin(s) * 180 / math.Pi}
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt64(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,240 @@
+package grid
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/space"
+)
+
+func TestPointToCell(t *testing.T) {
+	points := []space.Point{{116.3912, 39.9075}, {-73.9857, 40.7484}, {0, 0}, {151.2093, -33.8688}, {-179.9, 89.9}}
+	for _, point := range points {
+		for res := 0; res <= MaxResolution; res++ {
+			cell, err := PointToCell(point, res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cell.IsValid() || cell.Resolution() != res {
+				t.Errorf("PointToCell(%v, %v) = %v, not valid", point, res, cell)
+			}
+			again, _ := PointToCell(cell.Center(), res)
+			if again != cell {
+				t.Errorf("PointToCell(Center()) = %v, want %v", again, cell)
+			}
+			parsed, err := CellFromString(cell.String())
+			if err != nil || parsed != cell {
+				t.Errorf("CellFromString() = %v, want %v", parsed, cell)
+			}
+		}
+	}
+	if _, err := PointToCell(space.Point{0, 0}, MaxResolution+1); err == nil {
+		t.Errorf("PointToCell() error = nil, want %v", ErrInvalidResolution)
+	}
+	if _, err := CellFromString("1"); err == nil {
+		t.Errorf("CellFromString() error = nil, want %v", ErrInvalidCell)
+	}
+}
+
+func TestCellID_Hierarchy(t *testing.T) {
+	cell, _ := PointToCell(space.Point{116.3912, 39.9075}, 9)
+	children, err := cell.Children(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 49 {
+		t.Errorf("Children() len = %v, want %v", len(children), 49)
+	}
+	for _, child := range children {
+		if parent, _ := child.Parent(9); parent != cell {
+			t.Errorf("Parent() = %v, want %v", parent, cell)
+		}
+	}
+	// the centre child shares the centre of its parent.
+	center, _ := cell.Children(10)
+	if !center[0].Center().EqualsExact(cell.Center(), 1e-9) {
+		t.Errorf("Children()[0].Center() = %v, want %v", center[0].Center(), cell.Center())
+	}
+	for res := 0; res <= 9; res++ {
+		parent, _ := cell.Parent(res)
+		if parent.Resolution() != res {
+			t.Errorf("Parent() resolution = %v, want %v", parent.Resolution(), res)
+		}
+	}
+	if _, err := cell.Parent(10); err == nil {
+		t.Errorf("Parent() error = nil, want %v", ErrInvalidResolution)
+	}
+}
+
+func TestCellID_KRing(t *testing.T) {
+	cell, _ := PointToCell(space.Point{-73.9857, 40.7484}, 8)
+	for k := 0; k <= 3; k++ {
+		ring := cell.KRing(k)
+		if len(ring) != 3*k*(k+1)+1 {
+			t.Errorf("KRing(%v) len = %v, want %v", k, len(ring), 3*k*(k+1)+1)
+		}
+		for _, c := range ring {
+			if d, _ := cell.GridDistance(c); d > k {
+				t.Errorf("GridDistance() = %v, want <= %v", d, k)
+			}
+		}
+	}
+	for _, n := range cell.Neighbours() {
+		if d, _ := cell.GridDistance(n); d != 1 {
+			t.Errorf("GridDistance() = %v, want %v", d, 1)
+		}
+	}
+}
+
+func TestCellID_Antimeridian(t *testing.T) {
+	contains := func(cells []CellID, cell CellID) bool {
+		for _, c := range cells {
+			if c == cell {
+				return true
+			}
+		}
+		return false
+	}
+	// inBoundary tests the point against the boundary moved by a turn of the globe either way.
+	inBoundary := func(point space.Point, cell CellID) bool {
+		polygons, _ := polygonMatrixes(cell.Boundary())
+		for _, shift := range []float64{-360, 0, 360} {
+			if inPolygons(matrix.Matrix{point[0] + shift, point[1]}, polygons) {
+				return true
+			}
+		}
+		return false
+	}
+	points := []space.Point{{179.32, -19.64}, {179.9, 10}, {-179.5, 45}, {179.99, -60}, {-179.01, 0}, {180, 0.3}, {-180, 30.1}}
+	for _, point := range points {
+		for res := 0; res <= MaxResolution; res++ {
+			cell, _ := PointToCell(point, res)
+			if !inBoundary(point, cell) {
+				t.Errorf("PointToCell(%v, %v) = %v, boundary %v not contains the point", point, res, cell, cell.Boundary())
+			}
+			if again, _ := PointToCell(cell.Center(), res); again != cell {
+				t.Errorf("PointToCell(Center()) = %v, want %v", again, cell)
+			}
+			for parentRes := 0; parentRes < res && res-parentRes <= 3; parentRes++ {
+				parent, _ := cell.Parent(parentRes)
+				if children, _ := parent.Children(res); !contains(children, cell) {
+					t.Errorf("Parent(%v) of %v = %v, its children not contain the cell", parentRes, cell, parent)
+				}
+			}
+			neighbours := cell.Neighbours()
+			if ring := cell.KRing(1); len(ring) != 7 {
+				t.Errorf("KRing(1) of %v len = %v, want %v", cell, len(ring), 7)
+			}
+			for _, n := range neighbours {
+				if !contains(n.Neighbours(), cell) {
+					t.Errorf("Neighbours() of %v not contains %v", n, cell)
+				}
+				if d, _ := cell.GridDistance(n); d != 1 {
+					t.Errorf("GridDistance() = %v, want %v", d, 1)
+				}
+				if lon := n.Center().Lon(); lon < -180-1e-9 || lon >= 180 {
+					t.Errorf("Neighbours() cell %v centre %v beyond the antimeridian", n, n.Center())
+				}
+			}
+		}
+	}
+	for res := 0; res <= MaxResolution; res++ {
+		for lat := -80.0; lat <= 80; lat += 10 {
+			east, _ := PointToCell(space.Point{179.9999999, lat}, res)
+			west, _ := PointToCell(space.Point{-179.9999999, lat}, res)
+			if d, _ := east.GridDistance(west); d > 1 {
+				t.Errorf("GridDistance() of %v and %v at %v = %v, want <= 1", east, west, res, d)
+			}
+		}
+	}
+	wrapped, _ := PointToCell(space.Point{190, 10}, 5)
+	if cell, _ := PointToCell(space.Point{-170, 10}, 5); wrapped != cell {
+		t.Errorf("PointToCell() = %v, want %v", wrapped, cell)
+	}
+
+	res := 6
+	cells, err := Polyfill(space.Polygon{{{179, -1}, {181, -1}, {181, 1}, {179, 1}, {179, -1}}}, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x0, y0 := projectEqualArea(space.Point{179, -1})
+	x1, y1 := projectEqualArea(space.Point{181, 1})
+	want := (x1 - x0) * (y1 - y0) / Area(res)
+	if math.Abs(float64(len(cells))-want) > want*0.1 {
+		t.Errorf("Polyfill() len = %v, want about %v", len(cells), want)
+	}
+	east := 0
+	for _, cell := range cells {
+		if cell.Center().Lon() > 0 {
+			east++
+		}
+	}
+	if east == 0 || east == len(cells) {
+		t.Errorf("Polyfill() %v of %v cells east of the antimeridian", east, len(cells))
+	}
+}
+
+func TestPointToCell_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		point := space.Point{r.Float64()*360 - 180, r.Float64()*170 - 85}
+		res := r.Intn(MaxResolution + 1)
+		cell, _ := PointToCell(point, res)
+		polygons, _ := polygonMatrixes(cell.Boundary())
+		inside := false
+		for _, shift := range []float64{-360, 0, 360} {
+			inside = inside || inPolygons(matrix.Matrix{point[0] + shift, point[1]}, polygons)
+		}
+		if !inside {
+			t.Errorf("PointToCell(%v, %v) = %v, boundary not contains the point", point, res, cell)
+		}
+	}
+}
+
+func TestCellID_Boundary(t *testing.T) {
+	cell, _ := PointToCell(space.Point{116.3912, 39.9075}, 7)
+	boundary := cell.Boundary()
+	if len(boundary[0]) != 7 || !boundary.IsClosed() {
+		t.Errorf("Boundary() = %v, want closed hexagon", boundary)
+	}
+	polygons, _ := polygonMatrixes(boundary)
+	if !inPolygons(cell.Center().ToMatrix().(matrix.Matrix), polygons) {
+		t.Errorf("Boundary() = %v, not contains centre %v", boundary, cell.Center())
+	}
+	for _, n := range cell.Neighbours() {
+		if inPolygons(n.Center().ToMatrix().(matrix.Matrix), polygons) {
+			t.Errorf("Boundary() = %v, contains neighbour centre %v", boundary, n.Center())
+		}
+	}
+}
+
+func TestPolyfill(t *testing.T) {
+	polygon := space.Polygon{{{116, 39}, {117, 39}, {117, 40}, {116, 40}, {116, 39}}}
+	res := 6
+	cells, err := Polyfill(polygon, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// area of the polygon on the equal-area projection.
+	x0, y0 := projectEqualArea(space.Point{116, 39})
+	x1, y1 := projectEqualArea(space.Point{117, 40})
+	want := (x1 - x0) * (y1 - y0) / Area(res)
+	if math.Abs(float64(len(cells))-want) > want*0.1 {
+		t.Errorf("Polyfill() len = %v, want about %v", len(cells), want)
+	}
+	seen := map[CellID]bool{}
+	for _, cell := range cells {
+		if seen[cell] {
+			t.Errorf("Polyfill() duplicate cell %v", cell)
+		}
+		seen[cell] = true
+		if !polygon.Bound().Contains(cell.Center()) {
+			t.Errorf("Polyfill() cell %v outside polygon", cell)
+		}
+	}
+	if _, err := Polyfill(space.LineString{{1, 1}, {2, 2}}, res); err == nil {
+		t.Errorf("Polyfill() error = nil, want %v", "not polygon")
+	}
+}
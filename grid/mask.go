@@ -0,0 +1,103 @@
+package grid
+
+import (
+	"fmt"
+
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+// ErrCellSize cell size must be positive.
+var ErrCellSize = fmt.Errorf("cell size must be positive")
+
+// Options specifies how a grid clipped to a mask is generated.
+type Options struct {
+	// Clip clips the geometry of the cells on the border to the mask.
+	Clip bool
+
+	// Planar means the mask is already in projected coordinates and cellSize is in the same units,
+	// otherwise the mask is in longitude,latitude and cellSize is in meters.
+	Planar bool
+}
+
+// MaskGrid is a cell of a grid clipped to a mask.
+// Column and Row are the indexes of the cell in the grid of the mask bound,
+// so they are the same for the same mask, cell size and options.
+type MaskGrid struct {
+	Grid
+	Column, Row int
+}
+
+// ID returns the identifier of the cell as "column_row".
+func (m MaskGrid) ID() string {
+	return fmt.Sprintf("%d_%d", m.Column, m.Row)
+}
+
+// SquareGridWithMask Draw a square grid on the bound of the mask, keep only cells intersecting the mask.
+func SquareGridWithMask(mask space.Geometry, cellSize float64, opts Options) ([]MaskGrid, error) {
+	return gridWithMask(mask, cellSize, opts, squareGrid)
+}
+
+// HexagonGridWithMask Draw a hexagon grid on the bound of the mask, keep only cells intersecting the mask.
+func HexagonGridWithMask(mask space.Geometry, cellSize float64, opts Options) ([]MaskGrid, error) {
+	return gridWithMask(mask, cellSize, opts, hexagonGrid)
+}
+
+// TriangleGridWithMask Draw a triangle grid on the bound of the mask, keep only cells intersecting the mask.
+func TriangleGridWithMask(mask space.Geometry, cellSize float64, opts Options) ([]MaskGrid, error) {
+	return gridWithMask(mask, cellSize, opts, triangleGrid)
+}
+
+// RotatedSquareGridWithMask Draw a square grid rotated by angleDegrees on the bound of the mask,
+// keep only cells intersecting the mask.
+func RotatedSquareGridWithMask(mask space.Geometry, cellSize, angleDegrees float64, opts Options) ([]MaskGrid, error) {
+	return gridWithMask(mask, cellSize, opts, func(bound space.Bound, cellWidth, cellHeight float64) [][]Grid {
+		return rotatedSquareGrid(bound, cellWidth, cellHeight, angleDegrees)
+	})
+}
+
+// gridWithMask generates the grid by draw and keeps cells whose interior intersects the mask.
+func gridWithMask(mask space.Geometry, cellSize float64, opts Options,
+	draw func(bound space.Bound, cellWidth, cellHeight float64) [][]Grid) ([]MaskGrid, error) {
+	if mask == nil || mask.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	switch m := mask.(type) {
+	case space.Polygon, space.MultiPolygon:
+	case space.Bound:
+		mask = m.ToPolygon()
+	default:
+		return nil, spaceerr.ErrNotPolygon
+	}
+	if cellSize <= 0 {
+		return nil, ErrCellSize
+	}
+	bound := mask.Bound()
+	cellWidth, cellHeight := cellSize, cellSize
+	if !opts.Planar {
+		cellWidth, cellHeight = cellSizeInDegree(bound, cellSize)
+	}
+	grids := []MaskGrid{}
+	for column, rows := range draw(bound, cellWidth, cellHeight) {
+		for row, cell := range rows {
+			if !cell.Geometry.Bound().IntersectsBound(bound) {
+				continue
+			}
+			// cells are convex, the part of the mask inside the cell decides whether the cell is kept.
+			clipped := clipToConvex(mask, cell.Geometry.(space.Polygon)[0])
+			if clipped == nil {
+				continue
+			}
+			if opts.Clip {
+				cellArea, _ := cell.Geometry.Area()
+				clippedArea, _ := clipped.Area()
+				if clippedArea < cellArea*(1-calc.DefaultTolerance9) {
+					cell = Grid{Geometry: clipped}
+				}
+			}
+			grids = append(grids, MaskGrid{Grid: cell, Column: column, Row: row})
+		}
+	}
+	return grids, nil
+}
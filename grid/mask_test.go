@@ -0,0 +1,169 @@
+package grid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+func TestSquareGridWithMask(t *testing.T) {
+	mask := space.Polygon{{{0, 0}, {10, 0}, {0, 10}, {0, 0}}}
+	type args struct {
+		mask     space.Geometry
+		cellSize float64
+		opts     Options
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantNum  int
+		wantArea float64
+		wantErr  bool
+	}{
+		{name: "mask", args: args{mask, 2, Options{Planar: true}}, wantNum: 15, wantArea: 60},
+		{name: "mask clip", args: args{mask, 2, Options{Planar: true, Clip: true}}, wantNum: 15, wantArea: 50},
+		{name: "mask line", args: args{space.LineString{{0, 0}, {1, 1}}, 2, Options{Planar: true}}, wantErr: true},
+		{name: "mask cell size", args: args{mask, 0, Options{Planar: true}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SquareGridWithMask(tt.args.mask, tt.args.cellSize, tt.args.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SquareGridWithMask() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantNum {
+				t.Errorf("SquareGridWithMask() len = %v, want %v", len(got), tt.wantNum)
+			}
+			area := 0.0
+			ids := map[string]bool{}
+			for _, cell := range got {
+				a, _ := cell.Geometry.Area()
+				area += a
+				if cell.Column+cell.Row >= 5 {
+					t.Errorf("SquareGridWithMask() cell %v outside mask", cell.ID())
+				}
+				ids[cell.ID()] = true
+			}
+			if math.Abs(area-tt.wantArea) > 1e-9 {
+				t.Errorf("SquareGridWithMask() area = %v, want %v", area, tt.wantArea)
+			}
+			if len(ids) != len(got) {
+				t.Errorf("SquareGridWithMask() ids not unique")
+			}
+		})
+	}
+}
+
+func TestHexagonGridWithMask(t *testing.T) {
+	mask := space.Polygon{{{1, 1}, {1.5, 1}, {1.5, 1.5}, {1, 1.5}, {1, 1}}}
+	all := HexagonGrid(mask.Bound(), 5000)
+	got, err := HexagonGridWithMask(mask, 5000, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, rows := range all {
+		total += len(rows)
+	}
+	if len(got) == 0 || len(got) >= total {
+		t.Errorf("HexagonGridWithMask() len = %v, want in (0,%v)", len(got), total)
+	}
+	for _, cell := range got {
+		if !all[cell.Column][cell.Row].Geometry.Equals(cell.Geometry) {
+			t.Errorf("HexagonGridWithMask() cell %v = %v, want %v", cell.ID(), cell.Geometry, all[cell.Column][cell.Row].Geometry)
+		}
+	}
+
+	clipped, err := HexagonGridWithMask(mask, 5000, Options{Clip: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	area := 0.0
+	for _, cell := range clipped {
+		a, _ := cell.Geometry.Area()
+		area += a
+	}
+	if math.Abs(area-0.25) > 1e-6 {
+		t.Errorf("HexagonGridWithMask() area = %v, want %v", area, 0.25)
+	}
+}
+
+func TestSquareGridWithMaskHole(t *testing.T) {
+	mask := space.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}}
+	for _, clip := range []bool{false, true} {
+		got, err := SquareGridWithMask(mask, 1, Options{Planar: true, Clip: clip})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 64 {
+			t.Errorf("SquareGridWithMask() len = %v, want %v", len(got), 64)
+		}
+		for _, cell := range got {
+			if cell.Column >= 2 && cell.Column < 8 && cell.Row >= 2 && cell.Row < 8 {
+				t.Errorf("SquareGridWithMask() cell %v in the hole", cell.ID())
+			}
+			if area, _ := cell.Geometry.Area(); math.Abs(area-1) > 1e-9 {
+				t.Errorf("SquareGridWithMask() cell %v area = %v, want %v", cell.ID(), area, 1)
+			}
+		}
+	}
+}
+
+func Test_clipToConvex(t *testing.T) {
+	cell := space.Ring{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}
+	tests := []struct {
+		name     string
+		mask     space.Geometry
+		wantArea float64
+		wantNil  bool
+	}{
+		{name: "clip inside", mask: space.Polygon{{{-1, -1}, {3, -1}, {3, 3}, {-1, 3}, {-1, -1}}}, wantArea: 4},
+		{name: "clip half", mask: space.Polygon{{{1, -1}, {3, -1}, {3, 3}, {1, 3}, {1, -1}}}, wantArea: 2},
+		{name: "clip hole", mask: space.Polygon{{{-1, -1}, {3, -1}, {3, 3}, {-1, 3}, {-1, -1}}, {{0.5, 0.5}, {1.5, 0.5}, {1.5, 1.5}, {0.5, 1.5}, {0.5, 0.5}}}, wantArea: 3},
+		{name: "clip multi", mask: space.MultiPolygon{{{{1, -1}, {3, -1}, {3, 3}, {1, 3}, {1, -1}}}, {{{-1, 0}, {0.5, 0}, {0.5, 1}, {-1, 1}, {-1, 0}}}}, wantArea: 2.5},
+		{name: "clip touch", mask: space.Polygon{{{2, 0}, {3, 0}, {3, 2}, {2, 2}, {2, 0}}}, wantNil: true},
+		{name: "clip in hole", mask: space.Polygon{{{-1, -1}, {3, -1}, {3, 3}, {-1, 3}, {-1, -1}}, {{-0.5, -0.5}, {2.5, -0.5}, {2.5, 2.5}, {-0.5, 2.5}, {-0.5, -0.5}}}, wantNil: true},
+		{name: "clip hole on cell", mask: space.Polygon{{{-1, -1}, {3, -1}, {3, 3}, {-1, 3}, {-1, -1}}, {{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}}, wantNil: true},
+		{name: "clip concave", mask: space.Polygon{{{-1, -1}, {3, -1}, {3, 3}, {1.5, 3}, {1.5, 0.5}, {0.5, 0.5}, {0.5, 3}, {-1, 3}, {-1, -1}}}, wantArea: 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clipToConvex(tt.mask, cell)
+			if (got == nil) != tt.wantNil {
+				t.Errorf("clipToConvex() = %v, wantNil %v", got, tt.wantNil)
+				return
+			}
+			if got == nil {
+				return
+			}
+			if area, _ := got.Area(); math.Abs(area-tt.wantArea) > 1e-9 {
+				t.Errorf("clipToConvex() area = %v, want %v", area, tt.wantArea)
+			}
+		})
+	}
+}
+
+func TestTriangleAndRotatedGridWithMask(t *testing.T) {
+	mask := space.Polygon{{{0, 0}, {10, 0}, {0, 10}, {0, 0}}}
+	for _, draw := range []func() ([]MaskGrid, error){
+		func() ([]MaskGrid, error) { return TriangleGridWithMask(mask, 1, Options{Planar: true, Clip: true}) },
+		func() ([]MaskGrid, error) {
+			return RotatedSquareGridWithMask(mask, 1, 20, Options{Planar: true, Clip: true})
+		},
+	} {
+		got, err := draw()
+		if err != nil {
+			t.Fatal(err)
+		}
+		area := 0.0
+		for _, cell := range got {
+			a, _ := cell.Geometry.Area()
+			area += a
+		}
+		if math.Abs(area-50) > 1e-9 {
+			t.Errorf("GridWithMask() area = %v, want %v", area, 50)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package grid
+
+import (
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/calc/angle"
+	"github.com/spatial-go/geoos/space"
+)
+
+// RotatedSquareGrid Draw a grid of squares with side length cellSize rotated by angleDegrees
+// counter-clockwise around the centre of the bound, including the given area.
+// Columns and rows follow the rotated axes.
+func RotatedSquareGrid(bound space.Bound, cellSize, angleDegrees float64) (gridGeoms [][]Grid) {
+	cellWidth, cellHeight := cellSizeInDegree(bound, cellSize)
+	return rotatedSquareGrid(bound, cellWidth, cellHeight, angleDegrees)
+}
+
+// rotatedSquareGrid Draw a rotated square grid of side cellWidth and cellHeight in the units of the bound.
+// The rotation is done in cell units, where the side of a cell is 1 along both axes,
+// so that the cells stay squares in meters when cellWidth and cellHeight are degrees.
+func rotatedSquareGrid(bound space.Bound, cellWidth, cellHeight, angleDegrees float64) (gridGeoms [][]Grid) {
+	centerX := (bound.Min[0] + bound.Max[0]) / 2
+	centerY := (bound.Min[1] + bound.Max[1]) / 2
+	sin, cos := math.Sincos(angle.ToRadians(angleDegrees))
+
+	// extent of the bound in the rotated frame.
+	minU, minV := math.MaxFloat64, math.MaxFloat64
+	maxU, maxV := -math.MaxFloat64, -math.MaxFloat64
+	for _, corner := range []space.Point{bound.Min, bound.Max, bound.LeftTop(), bound.RightBottom()} {
+		x := (corner[0] - centerX) / cellWidth
+		y := (corner[1] - centerY) / cellHeight
+		u, v := x*cos+y*sin, -x*sin+y*cos
+		minU, maxU = math.Min(minU, u), math.Max(maxU, u)
+		minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+	}
+	// Round up (including all points)
+	columns := math.Max(math.Ceil(maxU-minU), 1)
+	rows := math.Max(math.Ceil(maxV-minV), 1)
+	startU := (minU+maxU)/2 - columns/2
+	startV := (minV+maxV)/2 - rows/2
+
+	toPoint := func(u, v float64) space.Point {
+		x, y := u*cos-v*sin, u*sin+v*cos
+		return space.Point{centerX + x*cellWidth, centerY + y*cellHeight}
+	}
+	for column := int64(0); column < int64(columns); column++ {
+		u := startU + float64(column)
+		geomRows := []Grid{}
+		for row := int64(0); row < int64(rows); row++ {
+			v := startV + float64(row)
+			point0 := toPoint(u, v)
+			point1 := toPoint(u, v+1)
+			point2 := toPoint(u+1, v+1)
+			point3 := toPoint(u+1, v)
+			ring := space.Ring{point0, point1, point2, point3, point0}
+			geomRows = append(geomRows, Grid{Geometry: space.Polygon{ring}})
+		}
+		gridGeoms = append(gridGeoms, geomRows)
+	}
+	return gridGeoms
+}
@@ -0,0 +1,43 @@
+package grid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/calc/angle"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/space"
+)
+
+func TestRotatedSquareGrid(t *testing.T) {
+	bound := space.Bound{Min: space.Point{0, 0}, Max: space.Point{10, 6}}
+	boundArea, _ := bound.Area()
+	for _, angleDegrees := range []float64{0, 30, 45, -60, 90} {
+		gridGeoms := rotatedSquareGrid(bound, 2, 2, angleDegrees)
+		for _, rows := range gridGeoms {
+			for _, cell := range rows {
+				ring := cell.Geometry.(space.Polygon)[0]
+				if area, _ := cell.Geometry.Area(); math.Abs(area-4) > 1e-9 {
+					t.Errorf("rotatedSquareGrid(%v) cell area = %v, want %v", angleDegrees, area, 4)
+				}
+				// the second edge follows the rotated x axis.
+				got := angle.ToDegrees(angle.Angle(matrix.Matrix(ring[1]), matrix.Matrix(ring[2])))
+				if math.Abs(angle.Diff(angle.ToRadians(got), angle.ToRadians(angleDegrees))) > 1e-9 {
+					t.Errorf("rotatedSquareGrid(%v) angle = %v", angleDegrees, got)
+				}
+			}
+		}
+		if got := coveredArea(bound, gridGeoms); math.Abs(got-boundArea) > 1e-9 {
+			t.Errorf("rotatedSquareGrid(%v) covered area = %v, want %v", angleDegrees, got, boundArea)
+		}
+	}
+	if got := rotatedSquareGrid(bound, 2, 2, 0); len(got) != 5 || len(got[0]) != 3 {
+		t.Errorf("rotatedSquareGrid() size = %vx%v, want %vx%v", len(got), len(got[0]), 5, 3)
+	}
+
+	bound = space.Bound{Min: space.Point{1, 1}, Max: space.Point{1.5, 1.5}}
+	boundArea, _ = bound.Area()
+	if got := coveredArea(bound, RotatedSquareGrid(bound, 20000, 25)); math.Abs(got-boundArea) > 1e-9 {
+		t.Errorf("RotatedSquareGrid() covered area = %v, want %v", got, boundArea)
+	}
+}
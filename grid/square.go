@@ -1,5 +1,5 @@
 // Package grid is used to generate grid data.
-// include square and hexagon.
+// include square, hexagon, triangle and rotated square, and a global hierarchical hexagon cell index.
 package grid
 
 import (
@@ -17,6 +17,25 @@ type Grid struct {
 
 // SquareGrid ,Draw a grid according to the distance, including the given area.
 func SquareGrid(bound space.Bound, cellSize float64) (gridGeoms [][]Grid) {
+	cellWidth, cellHeight := cellSizeInDegree(bound, cellSize)
+	return squareGrid(bound, cellWidth, cellHeight)
+}
+
+// cellSizeInDegree Calculate the latitude and longitude corresponding to the length cellSize.
+func cellSizeInDegree(bound space.Bound, cellSize float64) (cellWidth, cellHeight float64) {
+	var (
+		west  = bound.Min[0]
+		south = bound.Min[1]
+		east  = bound.Max[0]
+		north = bound.Max[1]
+	)
+	cellWidth = cellSize * ((east - west) / measure.SpheroidDistance(matrix.Matrix{west, south}, matrix.Matrix{east, south}))
+	cellHeight = cellSize * ((north - south) / measure.SpheroidDistance(matrix.Matrix{west, north}, matrix.Matrix{west, south}))
+	return
+}
+
+// squareGrid Draw a grid of cellWidth and cellHeight in the units of the bound.
+func squareGrid(bound space.Bound, cellWidth, cellHeight float64) (gridGeoms [][]Grid) {
 	var (
 		minPoint = bound.Min
 		maxPoint = bound.Max
@@ -29,10 +48,6 @@ func SquareGrid(bound space.Bound, cellSize float64) (gridGeoms [][]Grid) {
 	boundWidth := east - west
 	boundHeight := north - south
 
-	// Calculate the latitude and longitude corresponding to the length cellSize
-	cellWidth := cellSize * (boundWidth / measure.SpheroidDistance(matrix.Matrix{west, south}, matrix.Matrix{east, south}))
-	cellHeight := cellSize * (boundHeight / measure.SpheroidDistance(matrix.Matrix{west, north}, matrix.Matrix{west, south}))
-
 	// Round up (including all points)
 	columns := math.Ceil(boundWidth / cellWidth)
 	rows := math.Ceil(boundHeight / cellHeight)
@@ -0,0 +1,60 @@
+package grid
+
+import (
+	"math"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+// TriangleGrid Draw a grid of equilateral triangles with side length cellSize, including the given area.
+// Triangles of a row alternate pointing up and down, neighbouring rows are flipped.
+func TriangleGrid(bound space.Bound, cellSize float64) (gridGeoms [][]Grid) {
+	cellWidth, cellHeight := cellSizeInDegree(bound, cellSize)
+	return triangleGrid(bound, cellWidth, cellHeight)
+}
+
+// triangleGrid Draw a triangle grid of side cellWidth and cellHeight in the units of the bound.
+func triangleGrid(bound space.Bound, cellWidth, cellHeight float64) (gridGeoms [][]Grid) {
+	var (
+		west  = bound.Min[0]
+		south = bound.Min[1]
+		east  = bound.Max[0]
+		north = bound.Max[1]
+	)
+	boundWidth := east - west
+	boundHeight := north - south
+	halfWidth := cellWidth / 2
+	triangleHeight := cellHeight * Sin60
+
+	// Round up (including all points), n triangles of a row span (n+1) half sides,
+	// one more triangle at each end covers the corners of the bound.
+	columns := math.Ceil(boundWidth/halfWidth) + 1
+	rows := math.Max(math.Ceil(boundHeight/triangleHeight), 1)
+	deltaX := ((columns+1)*halfWidth - boundWidth) / 2
+	deltaY := (rows*triangleHeight - boundHeight) / 2
+
+	currentX := west - deltaX
+	for column := int64(0); column < int64(columns); column++ {
+		currentY := south - deltaY
+		geomRows := []Grid{}
+		for row := int64(0); row < int64(rows); row++ {
+			var ring space.Ring
+			if (column+row)%2 == 0 {
+				point0 := space.Point{currentX, currentY}
+				point1 := space.Point{currentX + halfWidth, currentY + triangleHeight}
+				point2 := space.Point{currentX + cellWidth, currentY}
+				ring = space.Ring{point0, point1, point2, point0}
+			} else {
+				point0 := space.Point{currentX, currentY + triangleHeight}
+				point1 := space.Point{currentX + cellWidth, currentY + triangleHeight}
+				point2 := space.Point{currentX + halfWidth, currentY}
+				ring = space.Ring{point0, point1, point2, point0}
+			}
+			geomRows = append(geomRows, Grid{Geometry: space.Polygon{ring}})
+			currentY += triangleHeight
+		}
+		gridGeoms = append(gridGeoms, geomRows)
+		currentX += halfWidth
+	}
+	return gridGeoms
+}
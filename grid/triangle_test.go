@@ -0,0 +1,47 @@
+package grid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+// coveredArea returns the area of the bound covered by the cells.
+func coveredArea(bound space.Bound, gridGeoms [][]Grid) float64 {
+	area := 0.0
+	for _, rows := range gridGeoms {
+		for _, cell := range rows {
+			if clipped := clipToConvex(bound.ToPolygon(), cell.Geometry.(space.Polygon)[0]); clipped != nil {
+				a, _ := clipped.Area()
+				area += a
+			}
+		}
+	}
+	return area
+}
+
+func TestTriangleGrid(t *testing.T) {
+	bound := space.Bound{Min: space.Point{0, 0}, Max: space.Point{4, 2 * Sin60 * 2}}
+	gridGeoms := triangleGrid(bound, 2, 2)
+	if len(gridGeoms) != 5 || len(gridGeoms[0]) != 2 {
+		t.Errorf("triangleGrid() size = %vx%v, want %vx%v", len(gridGeoms), len(gridGeoms[0]), 5, 2)
+	}
+	for _, rows := range gridGeoms {
+		for _, cell := range rows {
+			if area, _ := cell.Geometry.Area(); math.Abs(area-Sin60*2) > 1e-9 {
+				t.Errorf("triangleGrid() cell area = %v, want %v", area, Sin60*2)
+			}
+		}
+	}
+	boundArea, _ := bound.Area()
+	if got := coveredArea(bound, gridGeoms); math.Abs(got-boundArea) > 1e-9 {
+		t.Errorf("triangleGrid() covered area = %v, want %v", got, boundArea)
+	}
+
+	bound = space.Bound{Min: space.Point{1, 1}, Max: space.Point{1.5, 1.5}}
+	boundArea, _ = bound.Area()
+	if got := coveredArea(bound, TriangleGrid(bound, 20000)); math.Abs(got-boundArea) > 1e-9 {
+		t.Errorf("TriangleGrid() covered area = %v, want %v", got, boundArea)
+	}
+}
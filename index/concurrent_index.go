@@ -0,0 +1,125 @@
+package index
+
+import (
+	"sync"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+)
+
+// Builder A spatial index which is built lazily, on its first query.
+type Builder interface {
+	// Build Builds the index, if not already built.
+	Build()
+}
+
+// ConcurrentIndex A spatial index safe for concurrent use by multiple goroutines.
+// It guards an index with a reader/writer lock:
+// queries and nearest neighbours searches run in parallel,
+// inserts and removes are exclusive and wait for the running queries.
+// An index which is built lazily on its first query, implementing Builder, is built under the write lock,
+// so queries never mutate the index concurrently.
+// Visitors are called with the read lock held, they must not modify the index.
+type ConcurrentIndex struct {
+	mu      sync.RWMutex
+	index   SpatialIndex
+	isBuilt bool
+}
+
+// NewConcurrentIndex Creates a ConcurrentIndex guarding the index,
+// which must not be used directly afterwards.
+func NewConcurrentIndex(index SpatialIndex) *ConcurrentIndex {
+	c := &ConcurrentIndex{index: index}
+	c.isBuilt = !c.isBuilder()
+	return c
+}
+
+// Insert Adds a spatial item with an extent specified by the given Envelope to the index.
+func (c *ConcurrentIndex) Insert(itemEnv *envelope.Envelope, item interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isBuilt = !c.isBuilder()
+	return c.index.Insert(itemEnv, item)
+}
+
+// Remove Removes a single item from the tree.
+func (c *ConcurrentIndex) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.index.Remove(itemEnv, item)
+}
+
+// Query Queries the index for all items whose extents intersect the given search  Envelope
+// Note that some kinds of indexes may also return objects which do not in fact
+// intersect the query envelope.
+func (c *ConcurrentIndex) Query(searchEnv *envelope.Envelope) interface{} {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.index.Query(searchEnv)
+}
+
+// QueryVisitor Queries the index for all items whose extents intersect the given search Envelope,
+// and applies an  ItemVisitor to them.
+func (c *ConcurrentIndex) QueryVisitor(searchEnv *envelope.Envelope, visitor ItemVisitor) error {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.index.QueryVisitor(searchEnv, visitor)
+}
+
+// NearestNeighbours Returns the k items nearest to the query envelope within maxDistance,
+// in increasing order of distance, no items if the index does not implement NearestIndex.
+func (c *ConcurrentIndex) NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64,
+	distance ItemDistance) []Neighbour {
+	nearestIndex, ok := c.index.(NearestIndex)
+	if !ok {
+		return []Neighbour{}
+	}
+	c.rlock()
+	defer c.mu.RUnlock()
+	return nearestIndex.NearestNeighbours(queryEnv, k, maxDistance, distance)
+}
+
+// Read Calls fn with the index under the read lock, fn must not modify the index.
+func (c *ConcurrentIndex) Read(fn func(index SpatialIndex)) {
+	c.rlock()
+	defer c.mu.RUnlock()
+	fn(c.index)
+}
+
+// Write Calls fn with the index under the write lock,
+// e.g. to use methods of the index which are not part of SpatialIndex.
+func (c *ConcurrentIndex) Write(fn func(index SpatialIndex)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isBuilt = !c.isBuilder()
+	fn(c.index)
+}
+
+// rlock acquires the read lock, after building an index implementing Builder
+// under the write lock, once after the last modification.
+func (c *ConcurrentIndex) rlock() {
+	for {
+		c.mu.RLock()
+		if c.isBuilt {
+			return
+		}
+		c.mu.RUnlock()
+		c.mu.Lock()
+		if !c.isBuilt {
+			c.index.(Builder).Build()
+			c.isBuilt = true
+		}
+		c.mu.Unlock()
+	}
+}
+
+// isBuilder returns whether the index is built lazily.
+func (c *ConcurrentIndex) isBuilder() bool {
+	_, ok := c.index.(Builder)
+	return ok
+}
+
+// compile time checks
+var (
+	_ SpatialIndex = &ConcurrentIndex{}
+	_ NearestIndex = &ConcurrentIndex{}
+)
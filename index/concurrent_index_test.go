@@ -0,0 +1,105 @@
+package index_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+	"github.com/spatial-go/geoos/index/hprtree"
+	"github.com/spatial-go/geoos/index/quadtree"
+	"github.com/spatial-go/geoos/index/rstartree"
+	"github.com/spatial-go/geoos/index/strtree"
+)
+
+// cellEnv returns the envelope of the i-th unit cell of a 50 columns grid.
+func cellEnv(i int) *envelope.Envelope {
+	x, y := float64(i%50), float64(i/50)
+	return envelope.FourFloat(x, x+1, y, y+1)
+}
+
+func TestConcurrentIndex_Dynamic(t *testing.T) {
+	for name, spatialIndex := range map[string]index.SpatialIndex{
+		"quadtree":  quadtree.NewQuadtree(),
+		"rstartree": rstartree.NewRStarTree(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := index.NewConcurrentIndex(spatialIndex)
+			var wg sync.WaitGroup
+			for w := 0; w < 4; w++ {
+				wg.Add(2)
+				go func(w int) {
+					defer wg.Done()
+					for i := w; i < 1000; i += 4 {
+						if err := c.Insert(cellEnv(i), i); err != nil {
+							t.Error(err)
+						}
+						if i%10 == 0 {
+							c.Remove(cellEnv(i), i)
+						}
+					}
+				}(w)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 200; i++ {
+						_ = c.Query(envelope.FourFloat(10, 20, 5, 10))
+						_ = c.NearestNeighbours(envelope.FourFloat(25, 25, 10, 10), 3, math.Inf(1),
+							func(item interface{}) float64 {
+								return cellEnv(item.(int)).Distance(envelope.FourFloat(25, 25, 10, 10))
+							})
+					}
+				}()
+			}
+			wg.Wait()
+			size := 0
+			c.Read(func(spatialIndex index.SpatialIndex) {
+				switch tree := spatialIndex.(type) {
+				case *quadtree.Quadtree:
+					size = tree.Size()
+				case *rstartree.RStarTree:
+					size = tree.Size()
+				}
+			})
+			if size != 900 {
+				t.Errorf("ConcurrentIndex size = %v, want %v", size, 900)
+			}
+			for _, item := range c.Query(envelope.FourFloat(0.5, 0.5, 0.5, 0.5)).([]interface{}) {
+				if item == 0 {
+					t.Errorf("ConcurrentIndex.Query() returns removed item %v", item)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrentIndex_Static(t *testing.T) {
+	for name, spatialIndex := range map[string]index.SpatialIndex{
+		"strtree": strtree.NewSTRtree(),
+		"hprtree": hprtree.NewHPRTree(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := index.NewConcurrentIndex(spatialIndex)
+			for i := 0; i < 1000; i++ {
+				_ = c.Insert(cellEnv(i), i)
+			}
+			// the first queries build the tree concurrently.
+			var wg sync.WaitGroup
+			for w := 0; w < 8; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 50; i++ {
+						visitor := &index.ArrayVisitor{}
+						_ = c.QueryVisitor(envelope.FourFloat(10.5, 10.5, 3.5, 3.5), visitor)
+						if len(visitor.ItemsArray) != 1 || visitor.ItemsArray[0] != 160 {
+							t.Errorf("ConcurrentIndex.QueryVisitor() = %v, want %v", visitor.ItemsArray, []int{160})
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Package geoindex a type-safe spatial index of values keyed by the bounds of geometries,
+// over the spatial indexes of package index.
+package geoindex
+
+import (
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+	"github.com/spatial-go/geoos/index/hprtree"
+	"github.com/spatial-go/geoos/index/quadtree"
+	"github.com/spatial-go/geoos/index/rstartree"
+	"github.com/spatial-go/geoos/index/strtree"
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+// Index A spatial index of values of type T keyed by the bounds of geometries.
+// Queries return []T instead of interface{}, and only the values whose bounds do intersect the query,
+// even if the underlying index returns more candidates.
+type Index[T any] struct {
+	index index.SpatialIndex
+}
+
+// item a value with its envelope, the item stored in the underlying index.
+type item[T any] struct {
+	env   *envelope.Envelope
+	value T
+}
+
+// Neighbour A value found by a nearest neighbours search and its distance to the query.
+type Neighbour[T any] struct {
+	Value    T
+	Distance float64
+}
+
+// New Creates an Index over an empty spatial index, which must return the inserted items,
+// i.e. any index of package index except kdtree.KdTree which returns its nodes.
+func New[T any](spatialIndex index.SpatialIndex) *Index[T] {
+	return &Index[T]{index: spatialIndex}
+}
+
+// NewSTRtree Creates an Index over a static STRtree, no values can be inserted after the first query.
+func NewSTRtree[T any]() *Index[T] {
+	return New[T](strtree.NewSTRtree())
+}
+
+// NewRStarTree Creates an Index over a dynamic RStarTree.
+func NewRStarTree[T any]() *Index[T] {
+	return New[T](rstartree.NewRStarTree())
+}
+
+// NewQuadtree Creates an Index over a Quadtree.
+func NewQuadtree[T any]() *Index[T] {
+	return New[T](quadtree.NewQuadtree())
+}
+
+// NewHPRTree Creates an Index over a static HPRTree, no values can be inserted after the first query.
+func NewHPRTree[T any]() *Index[T] {
+	return New[T](hprtree.NewHPRTree())
+}
+
+// SpatialIndex Returns the underlying spatial index.
+func (x *Index[T]) SpatialIndex() index.SpatialIndex {
+	return x.index
+}
+
+// Insert Adds the value keyed by the bound of the geometry, which may be a space.Bound.
+func (x *Index[T]) Insert(geom space.Geometry, value T) error {
+	env, err := Envelope(geom)
+	if err != nil {
+		return err
+	}
+	return x.index.Insert(env, item[T]{env: env, value: value})
+}
+
+// Remove Removes the value keyed by the bound of the geometry.
+func (x *Index[T]) Remove(geom space.Geometry, value T) bool {
+	env, err := Envelope(geom)
+	if err != nil {
+		return false
+	}
+	return x.index.Remove(env, item[T]{env: env, value: value})
+}
+
+// Query Returns the values whose bounds intersect the bound of the geometry.
+func (x *Index[T]) Query(geom space.Geometry) []T {
+	values := []T{}
+	x.Each(geom, func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Each Calls fn for the values whose bounds intersect the bound of the geometry,
+// until fn returns false.
+func (x *Index[T]) Each(geom space.Geometry, fn func(value T) bool) {
+	env, err := Envelope(geom)
+	if err != nil {
+		return
+	}
+	_ = x.index.QueryVisitor(env, &visitor[T]{env: env, fn: fn})
+}
+
+// Nearest Returns the k values nearest to the geometry within maxDistance, in increasing order of distance.
+// distance computes the exact distance of a value, nil for the distance between the bounds.
+// The underlying index must implement index.NearestIndex.
+func (x *Index[T]) Nearest(geom space.Geometry, k int, maxDistance float64,
+	distance func(value T) float64) ([]Neighbour[T], error) {
+	nearestIndex, ok := x.index.(index.NearestIndex)
+	if !ok {
+		return nil, index.ErrNotNearestIndex
+	}
+	env, err := Envelope(geom)
+	if err != nil {
+		return nil, err
+	}
+	itemDistance := func(it interface{}) float64 {
+		if distance != nil {
+			return distance(it.(item[T]).value)
+		}
+		return env.Distance(it.(item[T]).env)
+	}
+	neighbours := []Neighbour[T]{}
+	for _, n := range nearestIndex.NearestNeighbours(env, k, maxDistance, itemDistance) {
+		neighbours = append(neighbours, Neighbour[T]{Value: n.Item.(item[T]).value, Distance: n.Distance})
+	}
+	return neighbours, nil
+}
+
+// Envelope Returns the envelope of the bound of the geometry.
+func Envelope(geom space.Geometry) (*envelope.Envelope, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	bound := geom.Bound()
+	return envelope.TwoMatrix(matrix.Matrix(bound.Min), matrix.Matrix(bound.Max)), nil
+}
+
+// visitor calls fn for the values whose envelopes intersect env until it returns false,
+// then it is done and the underlying index ends the query.
+type visitor[T any] struct {
+	env     *envelope.Envelope
+	fn      func(value T) bool
+	stopped bool
+}
+
+// VisitItem Visits an item in the index.
+func (v *visitor[T]) VisitItem(it interface{}) {
+	if v.stopped {
+		return
+	}
+	if it, ok := it.(item[T]); ok && it.env.IsIntersects(v.env) {
+		v.stopped = !v.fn(it.value)
+	}
+}
+
+// Items returns nothing, values are passed to fn.
+func (v *visitor[T]) Items() interface{} {
+	return nil
+}
+
+// IsDone returns true once fn has returned false.
+func (v *visitor[T]) IsDone() bool {
+	return v.stopped
+}
@@ -0,0 +1,145 @@
+package geoindex
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/index"
+	"github.com/spatial-go/geoos/index/hprtree"
+	"github.com/spatial-go/geoos/index/quadtree"
+	"github.com/spatial-go/geoos/index/rstartree"
+	"github.com/spatial-go/geoos/index/strtree"
+	"github.com/spatial-go/geoos/space"
+)
+
+type city struct {
+	Name string
+	space.Point
+}
+
+var cities = []city{
+	{"beijing", space.Point{116.4, 39.9}},
+	{"shanghai", space.Point{121.5, 31.2}},
+	{"guangzhou", space.Point{113.3, 23.1}},
+	{"tianjin", space.Point{117.2, 39.1}},
+}
+
+func names(values []city) []string {
+	result := []string{}
+	for _, v := range values {
+		result = append(result, v.Name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestIndex_Query(t *testing.T) {
+	north := space.Polygon{{{110, 35}, {125, 35}, {125, 45}, {110, 45}, {110, 35}}}
+	for name, x := range map[string]*Index[city]{
+		"strtree": NewSTRtree[city](), "rstartree": NewRStarTree[city](),
+		"quadtree": NewQuadtree[city](), "hprtree": NewHPRTree[city](),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, c := range cities {
+				if err := x.Insert(c.Point, c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := x.Insert(space.Polygon{}, city{}); err == nil {
+				t.Errorf("Index.Insert() empty geometry error = nil")
+			}
+			if got := names(x.Query(north)); !reflect.DeepEqual(got, []string{"beijing", "tianjin"}) {
+				t.Errorf("Index.Query() = %v", got)
+			}
+			bound := space.Bound{Min: space.Point{100, 20}, Max: space.Point{130, 50}}
+			if got := names(x.Query(bound)); len(got) != 4 {
+				t.Errorf("Index.Query() bound = %v", got)
+			}
+			count := 0
+			x.Each(bound, func(value city) bool {
+				count++
+				return count < 2
+			})
+			if count != 2 {
+				t.Errorf("Index.Each() stopped after %v values, want %v", count, 2)
+			}
+		})
+	}
+}
+
+// countingIndex counts the items visited by the underlying index.
+type countingIndex struct {
+	index.SpatialIndex
+	visits int
+}
+
+func (c *countingIndex) QueryVisitor(searchEnv *envelope.Envelope, visitor index.ItemVisitor) error {
+	return c.SpatialIndex.QueryVisitor(searchEnv, &countingVisitor{visitor.(index.StopVisitor), c})
+}
+
+type countingVisitor struct {
+	index.StopVisitor
+	counter *countingIndex
+}
+
+func (c *countingVisitor) VisitItem(item interface{}) {
+	c.counter.visits++
+	c.StopVisitor.VisitItem(item)
+}
+
+func TestIndex_EachStops(t *testing.T) {
+	for name, spatialIndex := range map[string]index.SpatialIndex{
+		"strtree": strtree.NewSTRtree(), "rstartree": rstartree.NewRStarTree(),
+		"quadtree": quadtree.NewQuadtree(), "hprtree": hprtree.NewHPRTree(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			counter := &countingIndex{SpatialIndex: spatialIndex}
+			x := New[int](counter)
+			for i := 0; i < 100; i++ {
+				_ = x.Insert(space.Point{float64(i % 10), float64(i / 10)}, i)
+			}
+			count := 0
+			x.Each(space.Bound{Min: space.Point{0, 0}, Max: space.Point{9, 9}}, func(value int) bool {
+				count++
+				return count < 3
+			})
+			if count != 3 || counter.visits != 3 {
+				t.Errorf("Index.Each() called fn %v times over %v visited items, want %v", count, counter.visits, 3)
+			}
+		})
+	}
+}
+
+func TestIndex_Remove(t *testing.T) {
+	x := NewRStarTree[city]()
+	for _, c := range cities {
+		_ = x.Insert(c.Point, c)
+	}
+	if !x.Remove(cities[0].Point, cities[0]) {
+		t.Errorf("Index.Remove() = false, want true")
+	}
+	if got := names(x.Query(space.Bound{Min: space.Point{100, 20}, Max: space.Point{130, 50}})); !reflect.DeepEqual(got, []string{"guangzhou", "shanghai", "tianjin"}) {
+		t.Errorf("Index.Query() after remove = %v", got)
+	}
+}
+
+func TestIndex_Nearest(t *testing.T) {
+	x := NewSTRtree[city]()
+	for _, c := range cities {
+		_ = x.Insert(c.Point, c)
+	}
+	query := space.Point{116, 39}
+	got, err := x.Nearest(query, 2, math.Inf(1), func(value city) float64 {
+		return measure.PlanarDistance(query.ToMatrix(), value.ToMatrix())
+	})
+	if err != nil || len(got) != 2 || got[0].Value.Name != "beijing" || got[1].Value.Name != "tianjin" {
+		t.Errorf("Index.Nearest() = %v, %v", got, err)
+	}
+	if _, err := New[city](nil).Nearest(query, 2, math.Inf(1), nil); err != index.ErrNotNearestIndex {
+		t.Errorf("Index.Nearest() error = %v, want %v", err, index.ErrNotNearestIndex)
+	}
+}
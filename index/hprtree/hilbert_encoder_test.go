@@ -18,6 +18,7 @@ func TestNewHilbertEncoder(t *testing.T) {
 		want *HilbertEncoder
 	}{
 		{"case1", args{1, &envelope.Envelope{MaxX: 4, MinX: 1, MaxY: 5, MinY: 1}}, &HilbertEncoder{1, 1, 1, 3, 4}},
+		{"case2", args{1, &envelope.Envelope{MaxX: 4, MinX: 1, MaxY: 6, MinY: -2}}, &HilbertEncoder{1, 1, -2, 3, 8}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -35,7 +35,7 @@ type HPRTree struct {
 func NewHPRTree() *HPRTree {
 	h := &HPRTree{}
 	h.nodeCapacity = DefaultNodeCapacity
-	h.totalExtent = &envelope.Envelope{}
+	h.totalExtent = envelope.Empty()
 	return h
 }
 
@@ -58,17 +58,16 @@ func (h *HPRTree) Insert(itemEnv *envelope.Envelope, item interface{}) error {
 // Note that some kinds of indexes may also return objects which do not in fact
 //  intersect the query envelope.
 func (h *HPRTree) Query(searchEnv *envelope.Envelope) interface{} {
-	h.build()
+	h.Build()
 
+	visitor := &index.ArrayVisitor{}
 	if !h.totalExtent.IsIntersects(searchEnv) {
-		return h.Items
+		return visitor.Items()
 	}
-
-	visitor := &index.ArrayVisitor{}
 	if err := h.QueryVisitor(searchEnv, visitor); err != nil {
 		log.Println(err)
 	}
-	return visitor.Items
+	return visitor.Items()
 }
 
 // QueryVisitor Queries the index for all items whose extents intersect the given search Envelope,
@@ -76,7 +75,7 @@ func (h *HPRTree) Query(searchEnv *envelope.Envelope) interface{} {
 // Note that some kinds of indexes may also return objects which do not in fact
 // intersect the query envelope.
 func (h *HPRTree) QueryVisitor(searchEnv *envelope.Envelope, visitor index.ItemVisitor) error {
-	h.build()
+	h.Build()
 	if !h.totalExtent.IsIntersects(searchEnv) {
 		return index.ErrHPRNotIsIntersects
 	}
@@ -91,7 +90,7 @@ func (h *HPRTree) queryTopLayer(searchEnv *envelope.Envelope, visitor index.Item
 	layerIndex := len(h.layerStartIndex) - 2
 	layerSize := h.layerSize(layerIndex)
 	// query each node in layer
-	for i := 0; i < layerSize; i += EnvSize {
+	for i := 0; i < layerSize && !index.IsDone(visitor); i += EnvSize {
 		h.queryNode(layerIndex, i, searchEnv, visitor)
 	}
 }
@@ -121,7 +120,7 @@ func (h *HPRTree) isIntersects(nodeIndex int, env *envelope.Envelope) bool {
 func (h *HPRTree) queryNodeChildren(layerIndex, blockOffset int, searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
 	layerStart := h.layerStartIndex[layerIndex]
 	layerEnd := h.layerStartIndex[layerIndex+1]
-	for i := 0; i < h.nodeCapacity; i++ {
+	for i := 0; i < h.nodeCapacity && !index.IsDone(visitor); i++ {
 		nodeOffset := blockOffset + EnvSize*i
 		// don't query past layer end
 		if layerStart+nodeOffset >= layerEnd {
@@ -132,7 +131,7 @@ func (h *HPRTree) queryNodeChildren(layerIndex, blockOffset int, searchEnv *enve
 }
 
 func (h *HPRTree) queryItems(blockStart int, searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
-	for i := 0; i < h.nodeCapacity; i++ {
+	for i := 0; i < h.nodeCapacity && !index.IsDone(visitor); i++ {
 		itemIndex := blockStart + i
 		// don't query past end of items
 		if itemIndex >= h.Size() {
@@ -163,14 +162,64 @@ func (h *HPRTree) layerSize(layerIndex int) int {
 	return layerEnd - layerStart
 }
 
+// NearestNeighbours Returns the k items nearest to the query envelope within maxDistance,
+// in increasing order of distance. distance computes the exact distance of an item,
+// nil for the distance between the envelopes.
+func (h *HPRTree) NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64,
+	distance index.ItemDistance) []index.Neighbour {
+	h.Build()
+	search := index.NewNearestSearch(queryEnv, k, maxDistance, distance)
+	addItems := func(blockStart int) {
+		for i := blockStart; i < blockStart+h.nodeCapacity && i < h.Size(); i++ {
+			item := h.Items[i].(*Item)
+			search.AddItem(item.Env, item.Item)
+		}
+	}
+	if h.layerStartIndex == nil {
+		addItems(0)
+	} else {
+		layerIndex := len(h.layerStartIndex) - 2
+		for i := 0; i < h.layerSize(layerIndex); i += EnvSize {
+			search.AddNode(h.nodeEnvelope(layerIndex, i), hprNode{layerIndex, i})
+		}
+	}
+	return search.Search(func(node interface{}) {
+		n := node.(hprNode)
+		if n.layerIndex == 0 {
+			addItems(n.nodeOffset / EnvSize * h.nodeCapacity)
+			return
+		}
+		layerSize := h.layerSize(n.layerIndex - 1)
+		for i := 0; i < h.nodeCapacity; i++ {
+			nodeOffset := n.nodeOffset*h.nodeCapacity + EnvSize*i
+			if nodeOffset >= layerSize {
+				break
+			}
+			search.AddNode(h.nodeEnvelope(n.layerIndex-1, nodeOffset), hprNode{n.layerIndex - 1, nodeOffset})
+		}
+	})
+}
+
+// hprNode identifies a node of the tree by its layer and offset in the layer.
+type hprNode struct {
+	layerIndex, nodeOffset int
+}
+
+// nodeEnvelope returns the envelope of the node at the offset in the layer.
+func (h *HPRTree) nodeEnvelope(layerIndex, nodeOffset int) *envelope.Envelope {
+	i := h.layerStartIndex[layerIndex] + nodeOffset
+	return envelope.FourFloat(h.nodeBounds[i], h.nodeBounds[i+2], h.nodeBounds[i+1], h.nodeBounds[i+3])
+}
+
 // Remove Removes a single item from the tree.
 func (h *HPRTree) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
 	// TODO Auto-generated method stub
 	return false
 }
 
-// build Builds the index, if not already built.
-func (h *HPRTree) build() {
+// Build Builds the index, if not already built.
+// Build is called by the first query, no more items can be inserted after.
+func (h *HPRTree) Build() {
 	// skip if already built
 	if h.isBuilt {
 		return
@@ -220,7 +269,7 @@ func (h *HPRTree) computeLayerNodes(layerIndex int) {
 }
 
 func (h *HPRTree) computeNodeBounds(nodeIndex, blockStart, nodeMaxIndex int) {
-	for i := 0; i <= h.nodeCapacity; i++ {
+	for i := 0; i < h.nodeCapacity; i++ {
 		index := blockStart + 4*i
 		if index >= nodeMaxIndex {
 			break
@@ -236,12 +285,12 @@ func (h *HPRTree) computeLeafNodes(layerSize int) {
 }
 
 func (h *HPRTree) computeLeafNodeBounds(nodeIndex, blockStart int) {
-	for i := 0; i <= h.nodeCapacity; i++ {
+	for i := 0; i < h.nodeCapacity; i++ {
 		itemIndex := blockStart + i
 		if itemIndex >= h.Size() {
 			break
 		}
-		env := h.Items[itemIndex].(Item).Env
+		env := h.Items[itemIndex].(*Item).Env
 		h.updateNodeBounds(nodeIndex, env.MinX, env.MinY, env.MaxX, env.MaxY)
 	}
 }
@@ -272,7 +321,7 @@ func (h *HPRTree) computeLayerIndices(itemSize, nodeCapacity int) []int {
 	index := 0
 	for layerSize > 1 {
 		layerIndexList = append(layerIndexList, index)
-		layerSize := h.numNodesToCover(layerSize, nodeCapacity)
+		layerSize = h.numNodesToCover(layerSize, nodeCapacity)
 		index += EnvSize * layerSize
 	}
 	return layerIndexList
@@ -324,8 +373,8 @@ func (it *ItemComparator) Len() int {
 // Less ...
 func (it *ItemComparator) Less(i, j int) bool {
 
-	hCode1 := it.encoder.encode(it.items[i].(Item).Env)
-	hCode2 := it.encoder.encode(it.items[j].(Item).Env)
+	hCode1 := it.encoder.encode(it.items[i].(*Item).Env)
+	hCode2 := it.encoder.encode(it.items[j].(*Item).Env)
 	return hCode1 < hCode2
 }
 
@@ -336,4 +385,6 @@ func (it ItemComparator) Swap(i, j int) {
 
 var (
 	_ index.SpatialIndex = &HPRTree{}
+	_ index.NearestIndex = &HPRTree{}
+	_ index.Builder      = &HPRTree{}
 )
@@ -3,11 +3,16 @@ package hprtree
 import (
 	"fmt"
 	"log"
+	"math"
+	"math/rand"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
 	"github.com/spatial-go/geoos/index"
 )
 
@@ -100,6 +105,45 @@ func TestHPRTree_Query(t *testing.T) {
 	}
 }
 
+func TestHPRTree_QueryLayers(t *testing.T) {
+	tree := NewHPRTree()
+	points := []matrix.Matrix{}
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 20; y++ {
+			p := matrix.Matrix{float64(x), float64(y) - 10}
+			points = append(points, p)
+			_ = tree.Insert(envelope.Matrix(p), p)
+		}
+	}
+	tests := []struct {
+		name      string
+		searchEnv *envelope.Envelope
+	}{
+		{"window", envelope.FourFloat(2.5, 7.5, -4.5, 3.5)},
+		{"corner", envelope.FourFloat(28, 40, 8, 20)},
+		{"miss", envelope.FourFloat(100, 110, 100, 110)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := 0
+			for _, p := range points {
+				if tt.searchEnv.Contains(envelope.Matrix(p)) {
+					want++
+				}
+			}
+			got := tree.Query(tt.searchEnv).([]interface{})
+			if len(got) != want {
+				t.Errorf("HPRTree.Query() = %v items, want %v", len(got), want)
+			}
+			for _, v := range got {
+				if !tt.searchEnv.Contains(envelope.Matrix(v.(matrix.Matrix))) {
+					t.Errorf("HPRTree.Query() item %v is outside %v", v, tt.searchEnv)
+				}
+			}
+		})
+	}
+}
+
 func TestHPRTree_QueryVisitor(t *testing.T) {
 	type args struct {
 		searchEnv *envelope.Envelope
@@ -122,3 +166,33 @@ func TestHPRTree_QueryVisitor(t *testing.T) {
 		})
 	}
 }
+
+func TestHPRTree_NearestNeighbours(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	points := make([]matrix.Matrix, 300)
+	tree := NewHPRTree()
+	for i := range points {
+		points[i] = matrix.Matrix{random.Float64() * 100, random.Float64() * 100}
+		_ = tree.Insert(envelope.Matrix(points[i]), points[i])
+	}
+	query := matrix.Matrix{50, 50}
+	for _, maxDistance := range []float64{math.Inf(1), 3} {
+		want := []float64{}
+		for _, p := range points {
+			if d := measure.PlanarDistance(query, p); d <= maxDistance {
+				want = append(want, d)
+			}
+		}
+		sort.Float64s(want)
+		if len(want) > 5 {
+			want = want[:5]
+		}
+		got := []float64{}
+		for _, n := range tree.NearestNeighbours(envelope.Matrix(query), 5, maxDistance, nil) {
+			got = append(got, n.Distance)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("HPRTree.NearestNeighbours() = %v, want %v", got, want)
+		}
+	}
+}
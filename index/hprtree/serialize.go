@@ -0,0 +1,76 @@
+package hprtree
+
+import (
+	"io"
+
+	"github.com/spatial-go/geoos/index/packed"
+)
+
+// WritePacked Writes the tree in the packed binary format, building it if necessary,
+// itemID returns the id stored for each item, nil for items which are integers.
+// Read the tree back with packed.Open or packed.OpenFile.
+func (h *HPRTree) WritePacked(w io.Writer, itemID packed.ItemID) error {
+	h.Build()
+	if itemID == nil {
+		itemID = packed.DefaultItemID
+	}
+	if h.Size() == 0 {
+		return packed.Write(w, packed.KindHPRTree, nil)
+	}
+	itemsNode := func(blockStart int) (*packed.Node, error) {
+		node := &packed.Node{}
+		for i := blockStart; i < blockStart+h.nodeCapacity && i < h.Size(); i++ {
+			item := h.Items[i].(*Item)
+			id, err := itemID(item.Item)
+			if err != nil {
+				return nil, err
+			}
+			node.Items = append(node.Items, packed.Item{Env: item.Env, ID: id})
+		}
+		return node, nil
+	}
+	if h.layerStartIndex == nil {
+		root, err := itemsNode(0)
+		if err != nil {
+			return err
+		}
+		root.Env = h.totalExtent
+		return packed.Write(w, packed.KindHPRTree, root)
+	}
+
+	var layerNode func(layerIndex, nodeOffset int) (*packed.Node, error)
+	layerNode = func(layerIndex, nodeOffset int) (*packed.Node, error) {
+		if layerIndex == 0 {
+			node, err := itemsNode(nodeOffset / EnvSize * h.nodeCapacity)
+			if err != nil {
+				return nil, err
+			}
+			node.Env = h.nodeEnvelope(layerIndex, nodeOffset)
+			return node, nil
+		}
+		node := &packed.Node{Env: h.nodeEnvelope(layerIndex, nodeOffset)}
+		layerSize := h.layerSize(layerIndex - 1)
+		for i := 0; i < h.nodeCapacity; i++ {
+			childOffset := nodeOffset*h.nodeCapacity + EnvSize*i
+			if childOffset >= layerSize {
+				break
+			}
+			child, err := layerNode(layerIndex-1, childOffset)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+		}
+		return node, nil
+	}
+	root := &packed.Node{Env: h.totalExtent}
+	layerIndex := len(h.layerStartIndex) - 2
+	for i := 0; i < h.layerSize(layerIndex); i += EnvSize {
+		child, err := layerNode(layerIndex, i)
+		if err != nil {
+			return err
+		}
+		root.Children = append(root.Children, child)
+	}
+	return packed.Write(w, packed.KindHPRTree, root)
+}
@@ -18,3 +18,9 @@ var ErrTreeIsNil = fmt.Errorf("Index is nil")
 
 // ErrNotMatchType ...
 var ErrNotMatchType = fmt.Errorf("Operation does not support not match type arguments")
+
+// ErrBulkLoadLength ...
+var ErrBulkLoadLength = fmt.Errorf("envelopes and items must have the same length")
+
+// ErrNotNearestIndex ...
+var ErrNotNearestIndex = fmt.Errorf("index does not support nearest neighbours search")
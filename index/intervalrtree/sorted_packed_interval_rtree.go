@@ -58,34 +58,24 @@ func (s *SortedPackedIntervalRTree) buildTree() Node {
 	// sort the leaf nodes
 	sort.Sort(s.leaves)
 
-	// now group nodes into blocks of two and build tree up recursively
+	// now group nodes into blocks of two and build tree up level by level
 	src := s.leaves
-	var dest LeafNodes = make(LeafNodes, 1)
-	for {
-		s.buildLevel(src, dest)
-		if len(dest) == 1 {
-			return dest[0]
-		}
-		temp := src
-		src = dest
-		dest = temp
+	for len(src) > 1 {
+		src = s.buildLevel(src)
 	}
+	return src[0]
 }
 
-func (s *SortedPackedIntervalRTree) buildLevel(src, dest LeafNodes) {
-	//level++;
+func (s *SortedPackedIntervalRTree) buildLevel(src LeafNodes) LeafNodes {
+	dest := make(LeafNodes, 0, (len(src)+1)/2)
 	for i := 0; i < len(src); i += 2 {
-		if i == 0 {
-			dest[i] = src[i]
+		if i+1 < len(src) {
+			dest = append(dest, NewBranchNode(src[i], src[i+1]))
 		} else {
 			dest = append(dest, src[i])
 		}
-		if i+1 < len(src) {
-			node := NewBranchNode(src[i], src[i+1])
-			dest = append(dest, node)
-
-		}
 	}
+	return dest
 }
 
 // Query Search for intervals in the index which intersect the given closed interval and apply the visitor to them.
@@ -84,3 +84,28 @@ func TestSortedPackedIntervalRTree_QueryVisitor(t *testing.T) {
 		})
 	}
 }
+
+func TestSortedPackedIntervalRTree_Query(t *testing.T) {
+	tree := &SortedPackedIntervalRTree{}
+	for i := 0; i < 100; i++ {
+		_ = tree.Insert(envelope.FourFloat(float64(i), float64(i)+1.5, 0, 0), i)
+	}
+	tests := []struct {
+		name     string
+		queryEnv *envelope.Envelope
+		want     int
+	}{
+		{"point", envelope.FourFloat(10.2, 10.2, 0, 0), 2},
+		{"interval", envelope.FourFloat(20.5, 30.5, 0, 0), 12},
+		{"first", envelope.FourFloat(-1, 0, 0, 0), 1},
+		{"last", envelope.FourFloat(100.5, 200, 0, 0), 1},
+		{"outside", envelope.FourFloat(101, 200, 0, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tree.Query(tt.queryEnv).([]interface{}); len(got) != tt.want {
+				t.Errorf("SortedPackedIntervalRTree.Query() = %v, want %v items", got, tt.want)
+			}
+		})
+	}
+}
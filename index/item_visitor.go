@@ -8,6 +8,21 @@ type ItemVisitor interface {
 	Items() interface{}
 }
 
+// StopVisitor An ItemVisitor which can end a query early,
+// the indexes stop their traversal once IsDone returns true.
+type StopVisitor interface {
+	ItemVisitor
+
+	// IsDone returns true if no more items are to be visited.
+	IsDone() bool
+}
+
+// IsDone returns true if the visitor is a StopVisitor which is done.
+func IsDone(visitor ItemVisitor) bool {
+	stopVisitor, ok := visitor.(StopVisitor)
+	return ok && stopVisitor.IsDone()
+}
+
 // compile time checks
 var (
 	_ ItemVisitor = &ArrayVisitor{}
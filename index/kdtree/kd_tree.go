@@ -3,6 +3,7 @@ package kdtree
 
 import (
 	"log"
+	"math"
 
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
@@ -237,6 +238,44 @@ func (k *KdTree) QueryMatrix(queryPt matrix.Matrix) *KdNode {
 	return k.QueryNodePoint(k.root, queryPt, true)
 }
 
+// NearestNeighbours Returns the k nodes nearest to the query envelope within maxDistance,
+// in increasing order of distance. distance computes the exact distance of a *KdNode,
+// nil for the distance to its point.
+func (k *KdTree) NearestNeighbours(queryEnv *envelope.Envelope, kNearest int, maxDistance float64,
+	distance index.ItemDistance) []index.Neighbour {
+	if k.root == nil {
+		return []index.Neighbour{}
+	}
+	search := index.NewNearestSearch(queryEnv, kNearest, maxDistance, distance)
+	inf := math.Inf(1)
+	search.AddNode(nil, &kdRegion{node: k.root, env: envelope.FourFloat(-inf, inf, -inf, inf), odd: true})
+	return search.Search(func(node interface{}) {
+		region := node.(*kdRegion)
+		n := region.node
+		search.AddItem(envelope.Matrix(n.Matrix), n)
+		// the left subtree lies below the discriminant, the right one above or on it.
+		left, right := envelope.Env(region.env), envelope.Env(region.env)
+		if region.odd {
+			left.MaxX, right.MinX = n.X(), n.X()
+		} else {
+			left.MaxY, right.MinY = n.Y(), n.Y()
+		}
+		if n.Left != nil {
+			search.AddNode(left, &kdRegion{node: n.Left, env: left, odd: !region.odd})
+		}
+		if n.Right != nil {
+			search.AddNode(right, &kdRegion{node: n.Right, env: right, odd: !region.odd})
+		}
+	})
+}
+
+// kdRegion a node of the tree with the region of its subtree.
+type kdRegion struct {
+	node *KdNode
+	env  *envelope.Envelope
+	odd  bool
+}
+
 // Depth Computes the Depth of the tree.
 func (k *KdTree) Depth() int {
 	return k.DepthNode(k.root)
@@ -326,4 +365,5 @@ var (
 	_ index.ItemVisitor  = &BestMatchVisitor{}
 	_ index.SpatialIndex = &quadtree.Quadtree{}
 	_ index.SpatialIndex = &KdTree{}
+	_ index.NearestIndex = &KdTree{}
 )
@@ -3,11 +3,16 @@ package kdtree
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
 )
 
 func TestKdTree_IsEmpty(t *testing.T) {
@@ -170,3 +175,33 @@ func buildTree() *KdTree {
 	}
 	return indexTree
 }
+
+func TestKdTree_NearestNeighbours(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	points := make([]matrix.Matrix, 300)
+	tree := &KdTree{}
+	for i := range points {
+		points[i] = matrix.Matrix{random.Float64() * 100, random.Float64() * 100}
+		_ = tree.Insert(envelope.Matrix(points[i]), points[i])
+	}
+	query := matrix.Matrix{50, 50}
+	for _, maxDistance := range []float64{math.Inf(1), 3} {
+		want := []float64{}
+		for _, p := range points {
+			if d := measure.PlanarDistance(query, p); d <= maxDistance {
+				want = append(want, d)
+			}
+		}
+		sort.Float64s(want)
+		if len(want) > 5 {
+			want = want[:5]
+		}
+		got := []float64{}
+		for _, n := range tree.NearestNeighbours(envelope.Matrix(query), 5, maxDistance, nil) {
+			got = append(got, n.Distance)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("KdTree.NearestNeighbours() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package index
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+)
+
+// ItemDistance Computes the distance between an item of an index and the query of a nearest neighbours search.
+// The distance must not be less than the distance between the envelope of the item and the query envelope,
+// which holds for the planar distance between geometries, e.g. measure.PlanarDistance.
+type ItemDistance func(item interface{}) float64
+
+// NearestIndex A spatial index supporting the search of the k nearest items.
+type NearestIndex interface {
+	// NearestNeighbours Returns the k items nearest to the query envelope within maxDistance,
+	// in increasing order of distance.
+	NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64, distance ItemDistance) []Neighbour
+}
+
+// Neighbour An item found by a nearest neighbours search and its distance to the query.
+type Neighbour struct {
+	Item     interface{}
+	Distance float64
+}
+
+// NearestSearch A best-first search of the k nearest items of a tree index.
+// Nodes and items are kept in a priority queue by the distance between their envelope and the query envelope,
+// the exact distance of an item is computed only when it is the nearest one of the queue,
+// so the items are found in increasing order of distance.
+type NearestSearch struct {
+	QueryEnv    *envelope.Envelope
+	K           int
+	MaxDistance float64
+	Distance    ItemDistance
+
+	queue nearestQueue
+}
+
+// NewNearestSearch Creates a search of the k nearest items to the query envelope within maxDistance,
+// use math.Inf(1) for no cutoff. If distance is nil the distance between envelopes is used.
+func NewNearestSearch(queryEnv *envelope.Envelope, k int, maxDistance float64, distance ItemDistance) *NearestSearch {
+	return &NearestSearch{QueryEnv: queryEnv, K: k, MaxDistance: maxDistance, Distance: distance}
+}
+
+// AddNode Adds a node to the queue, a nil envelope means the extent of the node is unknown.
+func (s *NearestSearch) AddNode(env *envelope.Envelope, node interface{}) {
+	s.push(&nearestEntry{value: node, distance: s.envDistance(env)})
+}
+
+// AddItem Adds an item to the queue, a nil envelope means the extent of the item is unknown.
+func (s *NearestSearch) AddItem(env *envelope.Envelope, item interface{}) {
+	entry := &nearestEntry{value: item, isItem: true, distance: s.envDistance(env)}
+	if s.Distance == nil {
+		entry.isExact = true
+	}
+	s.push(entry)
+}
+
+// Search Returns the nearest items in increasing order of distance,
+// expand is called for each node taken from the queue and adds its children by AddNode and AddItem.
+func (s *NearestSearch) Search(expand func(node interface{})) []Neighbour {
+	result := []Neighbour{}
+	for s.K > 0 && len(result) < s.K && s.queue.Len() > 0 {
+		entry := heap.Pop(&s.queue).(*nearestEntry)
+		switch {
+		case !entry.isItem:
+			expand(entry.value)
+		case entry.isExact:
+			result = append(result, Neighbour{Item: entry.value, Distance: entry.distance})
+		default:
+			entry.distance = math.Max(entry.distance, s.Distance(entry.value))
+			entry.isExact = true
+			s.push(entry)
+		}
+	}
+	s.queue = nil
+	return result
+}
+
+// envDistance returns the distance between the envelope and the query envelope.
+func (s *NearestSearch) envDistance(env *envelope.Envelope) float64 {
+	if env == nil || env.IsNil() || s.QueryEnv == nil {
+		return 0
+	}
+	return s.QueryEnv.Distance(env)
+}
+
+// push adds the entry to the queue unless it is beyond the maximum distance.
+func (s *NearestSearch) push(entry *nearestEntry) {
+	if entry.distance > s.MaxDistance {
+		return
+	}
+	heap.Push(&s.queue, entry)
+}
+
+// nearestEntry a node or an item in the queue of a nearest neighbours search.
+type nearestEntry struct {
+	value    interface{}
+	distance float64
+	isItem   bool
+	isExact  bool
+}
+
+// nearestQueue a priority queue of entries by distance, implements heap.Interface.
+type nearestQueue []*nearestEntry
+
+// Len ...
+func (q nearestQueue) Len() int {
+	return len(q)
+}
+
+// Less ...
+func (q nearestQueue) Less(i, j int) bool {
+	if q[i].distance != q[j].distance {
+		return q[i].distance < q[j].distance
+	}
+	// at the same distance items come first, so they are returned before further nodes are expanded.
+	return q[i].isItem && !q[j].isItem
+}
+
+// Swap ...
+func (q nearestQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Push ...
+func (q *nearestQueue) Push(x interface{}) {
+	*q = append(*q, x.(*nearestEntry))
+}
+
+// Pop ...
+func (q *nearestQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	*q = old[:n-1]
+	return entry
+}
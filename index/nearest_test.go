@@ -0,0 +1,63 @@
+package index
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+)
+
+func TestNearestSearch_Search(t *testing.T) {
+	lines := []matrix.LineMatrix{
+		{{0, 0}, {10, 0}},
+		{{0, 3}, {10, 3}},
+		{{0, 5}, {10, 5}},
+		{{20, 20}, {30, 30}},
+	}
+	// the envelope of the diagonal line is nearer than the third line, but the line itself is farther.
+	lines = append(lines, matrix.LineMatrix{{0, 2}, {4, 6}})
+	query := matrix.Matrix{5, 1}
+	distance := func(item interface{}) float64 {
+		line := lines[item.(int)]
+		dx, dy := line[1][0]-line[0][0], line[1][1]-line[0][1]
+		t := ((query[0]-line[0][0])*dx + (query[1]-line[0][1])*dy) / (dx*dx + dy*dy)
+		t = math.Max(0, math.Min(1, t))
+		return math.Hypot(line[0][0]+t*dx-query[0], line[0][1]+t*dy-query[1])
+	}
+	tests := []struct {
+		name        string
+		k           int
+		maxDistance float64
+		distance    ItemDistance
+		want        []int
+	}{
+		{name: "nearest 3", k: 3, maxDistance: math.Inf(1), distance: distance, want: []int{0, 1, 2}},
+		{name: "nearest all", k: 10, maxDistance: math.Inf(1), distance: distance, want: []int{0, 1, 2, 4, 3}},
+		{name: "nearest cutoff", k: 10, maxDistance: 4, distance: distance, want: []int{0, 1, 2}},
+		{name: "nearest envelope", k: 2, maxDistance: math.Inf(1), want: []int{0, 4}},
+		{name: "nearest none", k: 0, maxDistance: math.Inf(1), distance: distance, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := NewNearestSearch(envelope.Matrix(query), tt.k, tt.maxDistance, tt.distance)
+			search.AddNode(nil, lines)
+			neighbours := search.Search(func(node interface{}) {
+				for i, line := range node.([]matrix.LineMatrix) {
+					search.AddItem(envelope.TwoMatrix(line[0], line[1]), i)
+				}
+			})
+			got := []int{}
+			for i, n := range neighbours {
+				got = append(got, n.Item.(int))
+				if i > 0 && n.Distance < neighbours[i-1].Distance {
+					t.Errorf("NearestSearch.Search() distances not increasing %v", neighbours)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NearestSearch.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
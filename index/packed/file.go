@@ -0,0 +1,32 @@
+package packed
+
+// File A Tree over the bytes of a file, memory-mapped where the platform supports it,
+// so only the pages of the visited nodes and items are read.
+type File struct {
+	*Tree
+	data []byte
+}
+
+// OpenFile Opens the packed index file, Close must be called when the tree is no longer used.
+func OpenFile(path string) (*File, error) {
+	data, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := Open(data)
+	if err != nil {
+		_ = unmapFile(data)
+		return nil, err
+	}
+	return &File{Tree: tree, data: data}, nil
+}
+
+// Close Releases the bytes of the file, the tree must not be used afterwards.
+func (f *File) Close() error {
+	if f.data == nil {
+		return nil
+	}
+	data := f.data
+	f.data, f.Tree = nil, nil
+	return unmapFile(data)
+}
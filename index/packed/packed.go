@@ -0,0 +1,156 @@
+// Package packed a binary format of packed spatial index trees,
+// and a read-only index operating directly over the encoded bytes, e.g. a memory-mapped file.
+//
+// The format is little endian, a header is followed by the node records and then the item records.
+// Nodes are laid out breadth first from the root, so the child nodes and the items of a node are contiguous:
+// header: magic "GIDX", version uint32, kind uint32, reserved uint32, node count uint64, item count uint64
+// node:   minX, minY, maxX, maxY float64, first child uint64, child count uint64, first item uint64, item count uint64
+// item:   minX, minY, maxX, maxY float64, id uint64
+package packed
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+)
+
+// Kind the kind of tree which was written.
+type Kind uint32
+
+// Kinds of trees.
+const (
+	KindHPRTree Kind = iota + 1
+	KindSTRtree
+	KindQuadtree
+)
+
+// Format parameter.
+const (
+	Version    = 1
+	HeaderSize = 32
+	NodeSize   = 64
+	ItemSize   = 40
+)
+
+var magic = [4]byte{'G', 'I', 'D', 'X'}
+
+// ErrInvalidFormat ...
+var ErrInvalidFormat = fmt.Errorf("invalid packed index format")
+
+// ErrItemID ...
+var ErrItemID = fmt.Errorf("item is not an integer id")
+
+// ErrNegativeItemID ...
+var ErrNegativeItemID = fmt.Errorf("item id is negative")
+
+// ErrReadOnly ...
+var ErrReadOnly = fmt.Errorf("packed index is read only")
+
+// ItemID Returns the id stored for an item of the index.
+type ItemID func(item interface{}) (uint64, error)
+
+// DefaultItemID Returns the id of an item which is an integer, negative integers are not ids.
+func DefaultItemID(item interface{}) (uint64, error) {
+	switch v := item.(type) {
+	case int:
+		return signedItemID(int64(v))
+	case int32:
+		return signedItemID(int64(v))
+	case int64:
+		return signedItemID(v)
+	case uint:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	}
+	return 0, ErrItemID
+}
+
+func signedItemID(v int64) (uint64, error) {
+	if v < 0 {
+		return 0, ErrNegativeItemID
+	}
+	return uint64(v), nil
+}
+
+// Node A node of a tree to write, nil envelopes are unbounded.
+type Node struct {
+	Env      *envelope.Envelope
+	Children []*Node
+	Items    []Item
+}
+
+// Item An item of a tree to write, a nil envelope is unbounded.
+type Item struct {
+	Env *envelope.Envelope
+	ID  uint64
+}
+
+// Write Writes the tree rooted at root, a nil root writes an empty tree.
+func Write(w io.Writer, kind Kind, root *Node) error {
+	nodes := []*Node{}
+	if root != nil {
+		nodes = append(nodes, root)
+	}
+	// breadth first, so the children of each node are contiguous.
+	for i := 0; i < len(nodes); i++ {
+		nodes = append(nodes, nodes[i].Children...)
+	}
+	itemCount := 0
+	for _, n := range nodes {
+		itemCount += len(n.Items)
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, NodeSize)
+	copy(buf, magic[:])
+	binary.LittleEndian.PutUint32(buf[4:], Version)
+	binary.LittleEndian.PutUint32(buf[8:], uint32(kind))
+	binary.LittleEndian.PutUint32(buf[12:], 0)
+	binary.LittleEndian.PutUint64(buf[16:], uint64(len(nodes)))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(itemCount))
+	if _, err := bw.Write(buf[:HeaderSize]); err != nil {
+		return err
+	}
+
+	firstChild, firstItem := uint64(1), uint64(0)
+	for _, n := range nodes {
+		putEnvelope(buf, n.Env)
+		binary.LittleEndian.PutUint64(buf[32:], firstChild)
+		binary.LittleEndian.PutUint64(buf[40:], uint64(len(n.Children)))
+		binary.LittleEndian.PutUint64(buf[48:], firstItem)
+		binary.LittleEndian.PutUint64(buf[56:], uint64(len(n.Items)))
+		if _, err := bw.Write(buf[:NodeSize]); err != nil {
+			return err
+		}
+		firstChild += uint64(len(n.Children))
+		firstItem += uint64(len(n.Items))
+	}
+	for _, n := range nodes {
+		for _, item := range n.Items {
+			putEnvelope(buf, item.Env)
+			binary.LittleEndian.PutUint64(buf[32:], item.ID)
+			if _, err := bw.Write(buf[:ItemSize]); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func putEnvelope(buf []byte, env *envelope.Envelope) {
+	minX, minY, maxX, maxY := math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1)
+	if env != nil && !env.IsNil() {
+		minX, minY, maxX, maxY = env.MinX, env.MinY, env.MaxX, env.MaxY
+	}
+	binary.LittleEndian.PutUint64(buf[0:], math.Float64bits(minX))
+	binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(minY))
+	binary.LittleEndian.PutUint64(buf[16:], math.Float64bits(maxX))
+	binary.LittleEndian.PutUint64(buf[24:], math.Float64bits(maxY))
+}
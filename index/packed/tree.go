@@ -0,0 +1,214 @@
+package packed
+
+import (
+	"encoding/binary"
+	"log"
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+)
+
+// Tree A read-only spatial index over packed bytes, nodes and items are decoded only when visited.
+// The items of the index are the uint64 ids written.
+// A Tree is safe for concurrent use as it never modifies the bytes.
+type Tree struct {
+	data      []byte
+	kind      Kind
+	nodeCount uint64
+	itemCount uint64
+}
+
+// Open Returns a Tree over the packed bytes, which must not be modified while the tree is used.
+// The node records are validated, the children and the items of the nodes must be laid out breadth first
+// as Write does, so the traversals of the tree visit each node once.
+func Open(data []byte) (*Tree, error) {
+	if len(data) < HeaderSize || string(data[:4]) != string(magic[:]) ||
+		binary.LittleEndian.Uint32(data[4:]) != Version {
+		return nil, ErrInvalidFormat
+	}
+	t := &Tree{
+		data:      data,
+		kind:      Kind(binary.LittleEndian.Uint32(data[8:])),
+		nodeCount: binary.LittleEndian.Uint64(data[16:]),
+		itemCount: binary.LittleEndian.Uint64(data[24:]),
+	}
+	size := uint64(HeaderSize) + t.nodeCount*NodeSize + t.itemCount*ItemSize
+	if t.nodeCount > uint64(len(data))/NodeSize || t.itemCount > uint64(len(data))/ItemSize ||
+		size != uint64(len(data)) {
+		return nil, ErrInvalidFormat
+	}
+	switch t.kind {
+	case KindHPRTree, KindSTRtree, KindQuadtree:
+	default:
+		return nil, ErrInvalidFormat
+	}
+	if err := t.validateNodes(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// validateNodes checks that the children of each node follow it and the ones of the nodes before it,
+// and that the items of each node follow the ones of the nodes before it.
+func (t *Tree) validateNodes() error {
+	nextChild, nextItem := uint64(1), uint64(0)
+	for i := uint64(0); i < t.nodeCount; i++ {
+		node, _ := t.node(i)
+		firstChild, childCount := binary.LittleEndian.Uint64(node[32:]), binary.LittleEndian.Uint64(node[40:])
+		firstItem, itemCount := binary.LittleEndian.Uint64(node[48:]), binary.LittleEndian.Uint64(node[56:])
+		if firstChild != nextChild || firstChild <= i || childCount > t.nodeCount-firstChild ||
+			firstItem != nextItem || itemCount > t.itemCount-firstItem {
+			return ErrInvalidFormat
+		}
+		nextChild += childCount
+		nextItem += itemCount
+	}
+	if t.nodeCount > 0 && (nextChild != t.nodeCount || nextItem != t.itemCount) {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+// Kind Returns the kind of tree which was written.
+func (t *Tree) Kind() Kind {
+	return t.kind
+}
+
+// Size Returns the number of items in the tree.
+func (t *Tree) Size() int {
+	return int(t.itemCount)
+}
+
+// IsEmpty Tests whether the index contains any items.
+func (t *Tree) IsEmpty() bool {
+	return t.itemCount == 0
+}
+
+// Insert returns ErrReadOnly.
+func (t *Tree) Insert(itemEnv *envelope.Envelope, item interface{}) error {
+	return ErrReadOnly
+}
+
+// Remove returns false, the tree is read only.
+func (t *Tree) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
+	return false
+}
+
+// Query Queries the index for the ids of all items whose extents intersect the given search Envelope.
+func (t *Tree) Query(searchEnv *envelope.Envelope) interface{} {
+	visitor := &index.ArrayVisitor{}
+	if err := t.QueryVisitor(searchEnv, visitor); err != nil {
+		log.Println(err)
+	}
+	return visitor.Items()
+}
+
+// QueryVisitor Queries the index for all items whose extents intersect the given search Envelope,
+// and applies an  ItemVisitor to their ids.
+func (t *Tree) QueryVisitor(searchEnv *envelope.Envelope, visitor index.ItemVisitor) error {
+	if t.nodeCount == 0 || searchEnv == nil || searchEnv.IsNil() {
+		return nil
+	}
+	stack := []uint64{0}
+	for len(stack) > 0 && !index.IsDone(visitor) {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node, err := t.node(i)
+		if err != nil {
+			return err
+		}
+		if !intersects(node, searchEnv) {
+			continue
+		}
+		firstItem, itemCount := binary.LittleEndian.Uint64(node[48:]), binary.LittleEndian.Uint64(node[56:])
+		for j := firstItem; j < firstItem+itemCount && !index.IsDone(visitor); j++ {
+			item, err := t.item(j)
+			if err != nil {
+				return err
+			}
+			if intersects(item, searchEnv) {
+				visitor.VisitItem(binary.LittleEndian.Uint64(item[32:]))
+			}
+		}
+		firstChild, childCount := binary.LittleEndian.Uint64(node[32:]), binary.LittleEndian.Uint64(node[40:])
+		for j := firstChild + childCount; j > firstChild; j-- {
+			stack = append(stack, j-1)
+		}
+	}
+	return nil
+}
+
+// NearestNeighbours Returns the ids of the k items nearest to the query envelope within maxDistance,
+// in increasing order of distance. distance computes the exact distance of an item from its id,
+// nil for the distance between the envelopes.
+func (t *Tree) NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64,
+	distance index.ItemDistance) []index.Neighbour {
+	search := index.NewNearestSearch(queryEnv, k, maxDistance, distance)
+	if t.nodeCount == 0 {
+		return []index.Neighbour{}
+	}
+	search.AddNode(nil, uint64(0))
+	return search.Search(func(n interface{}) {
+		node, err := t.node(n.(uint64))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		firstItem, itemCount := binary.LittleEndian.Uint64(node[48:]), binary.LittleEndian.Uint64(node[56:])
+		for j := firstItem; j < firstItem+itemCount; j++ {
+			if item, err := t.item(j); err == nil {
+				search.AddItem(decodeEnvelope(item), binary.LittleEndian.Uint64(item[32:]))
+			}
+		}
+		firstChild, childCount := binary.LittleEndian.Uint64(node[32:]), binary.LittleEndian.Uint64(node[40:])
+		for j := firstChild; j < firstChild+childCount; j++ {
+			if child, err := t.node(j); err == nil {
+				search.AddNode(decodeEnvelope(child), j)
+			}
+		}
+	})
+}
+
+// node returns the record of the i-th node.
+func (t *Tree) node(i uint64) ([]byte, error) {
+	if i >= t.nodeCount {
+		return nil, ErrInvalidFormat
+	}
+	start := HeaderSize + i*NodeSize
+	return t.data[start : start+NodeSize], nil
+}
+
+// item returns the record of the i-th item.
+func (t *Tree) item(i uint64) ([]byte, error) {
+	if i >= t.itemCount {
+		return nil, ErrInvalidFormat
+	}
+	start := HeaderSize + t.nodeCount*NodeSize + i*ItemSize
+	return t.data[start : start+ItemSize], nil
+}
+
+// intersects tests whether the envelope at the start of the record intersects env.
+func intersects(record []byte, env *envelope.Envelope) bool {
+	return !(env.MaxX < float(record, 0) || env.MaxY < float(record, 8) ||
+		env.MinX > float(record, 16) || env.MinY > float(record, 24))
+}
+
+// decodeEnvelope returns the envelope at the start of the record, nil if unbounded.
+func decodeEnvelope(record []byte) *envelope.Envelope {
+	minX, minY, maxX, maxY := float(record, 0), float(record, 8), float(record, 16), float(record, 24)
+	if math.IsInf(minX, -1) && math.IsInf(maxX, 1) {
+		return nil
+	}
+	return envelope.FourFloat(minX, maxX, minY, maxY)
+}
+
+func float(record []byte, offset int) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(record[offset:]))
+}
+
+// compile time checks
+var (
+	_ index.SpatialIndex = &Tree{}
+	_ index.NearestIndex = &Tree{}
+)
@@ -0,0 +1,217 @@
+package packed_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+	"github.com/spatial-go/geoos/index/hprtree"
+	"github.com/spatial-go/geoos/index/packed"
+	"github.com/spatial-go/geoos/index/quadtree"
+	"github.com/spatial-go/geoos/index/strtree"
+)
+
+type packedWriter interface {
+	index.SpatialIndex
+	WritePacked(w io.Writer, itemID packed.ItemID) error
+}
+
+// cellEnv returns the envelope of the i-th cell of a 40 columns grid of cells of size 0.5.
+func cellEnv(i int) *envelope.Envelope {
+	x, y := float64(i%40), float64(i/40)
+	return envelope.FourFloat(x, x+0.5, y, y+0.5)
+}
+
+// ids returns the items as sorted uint64.
+func ids(items interface{}) []uint64 {
+	result := []uint64{}
+	for _, item := range items.([]interface{}) {
+		switch v := item.(type) {
+		case int:
+			result = append(result, uint64(v))
+		case uint64:
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+var searchEnvs = []*envelope.Envelope{
+	envelope.FourFloat(3.2, 7.7, 10.2, 12.7),
+	envelope.FourFloat(-100, 100, -100, 100),
+	envelope.FourFloat(20.7, 20.8, 20.7, 20.8),
+	envelope.FourFloat(100, 200, 100, 200),
+}
+
+func TestTree_Query(t *testing.T) {
+	for name, tree := range map[string]packedWriter{
+		"hprtree":  hprtree.NewHPRTree(),
+		"strtree":  strtree.NewSTRtree(),
+		"quadtree": quadtree.NewQuadtree(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 2000; i++ {
+				_ = tree.Insert(cellEnv(i), i)
+			}
+			buf := &bytes.Buffer{}
+			if err := tree.WritePacked(buf, nil); err != nil {
+				t.Fatal(err)
+			}
+			p, err := packed.Open(buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Size() != 2000 {
+				t.Errorf("Tree.Size() = %v, want %v", p.Size(), 2000)
+			}
+			for _, searchEnv := range searchEnvs {
+				if got, want := ids(p.Query(searchEnv)), ids(tree.Query(searchEnv)); !reflect.DeepEqual(got, want) {
+					t.Errorf("Tree.Query(%v) = %v, want %v", searchEnv.ToString(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTree_NearestNeighbours(t *testing.T) {
+	tree := strtree.NewSTRtree()
+	for i := 0; i < 2000; i++ {
+		_ = tree.Insert(cellEnv(i), i)
+	}
+	buf := &bytes.Buffer{}
+	_ = tree.WritePacked(buf, nil)
+	p, _ := packed.Open(buf.Bytes())
+	queryEnv := envelope.FourFloat(10.7, 10.7, 10.7, 10.7)
+	want := tree.NearestNeighbours(queryEnv, 5, math.Inf(1), nil)
+	got := p.NearestNeighbours(queryEnv, 5, math.Inf(1), nil)
+	if len(got) != len(want) {
+		t.Fatalf("Tree.NearestNeighbours() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].Distance != want[i].Distance {
+			t.Errorf("Tree.NearestNeighbours() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	tree := hprtree.NewHPRTree()
+	for i := 0; i < 500; i++ {
+		_ = tree.Insert(cellEnv(i), uint64(i))
+	}
+	path := filepath.Join(t.TempDir(), "cells.gidx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.WritePacked(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	file, err := packed.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Kind() != packed.KindHPRTree || file.Size() != 500 {
+		t.Errorf("File kind = %v size = %v", file.Kind(), file.Size())
+	}
+	if got := ids(file.Query(envelope.FourFloat(1.2, 1.3, 2.2, 2.3))); !reflect.DeepEqual(got, []uint64{81}) {
+		t.Errorf("File.Query() = %v, want %v", got, []uint64{81})
+	}
+	if err := file.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tree := strtree.NewSTRtree()
+	_ = tree.Insert(cellEnv(0), "not an id")
+	if err := tree.WritePacked(buf, nil); err != packed.ErrItemID {
+		t.Errorf("WritePacked() error = %v, want %v", err, packed.ErrItemID)
+	}
+	buf.Reset()
+	_ = packed.Write(buf, packed.KindSTRtree, &packed.Node{Items: []packed.Item{{ID: 1}}})
+	data := buf.Bytes()
+	for name, invalid := range map[string][]byte{
+		"empty":     {},
+		"truncated": data[:len(data)-1],
+		"magic":     append([]byte("XXXX"), data[4:]...),
+	} {
+		if _, err := packed.Open(invalid); err != packed.ErrInvalidFormat {
+			t.Errorf("Open(%v) error = %v, want %v", name, err, packed.ErrInvalidFormat)
+		}
+	}
+	p, err := packed.Open(data)
+	if err != nil || !reflect.DeepEqual(ids(p.Query(envelope.FourFloat(0, 1, 0, 1))), []uint64{1}) {
+		t.Errorf("Open() unbounded item = %v, %v", p, err)
+	}
+	if p.Insert(envelope.FourFloat(0, 1, 0, 1), 2) != packed.ErrReadOnly {
+		t.Errorf("Tree.Insert() error = nil, want %v", packed.ErrReadOnly)
+	}
+}
+
+func TestOpen_Malformed(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := &packed.Node{Children: []*packed.Node{
+		{Items: []packed.Item{{Env: cellEnv(0), ID: 1}}},
+		{Items: []packed.Item{{Env: cellEnv(1), ID: 2}}, Children: []*packed.Node{{Items: []packed.Item{{Env: cellEnv(2), ID: 3}}}}},
+	}}
+	_ = packed.Write(buf, packed.KindSTRtree, root)
+	if _, err := packed.Open(buf.Bytes()); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	// patch returns a copy of the packed tree with the field at offset of the i-th node set to v.
+	patch := func(i, offset int, v uint64) []byte {
+		data := append([]byte{}, buf.Bytes()...)
+		binary.LittleEndian.PutUint64(data[packed.HeaderSize+i*packed.NodeSize+offset:], v)
+		return data
+	}
+	kind := append([]byte{}, buf.Bytes()...)
+	binary.LittleEndian.PutUint32(kind[8:], 99)
+	for name, invalid := range map[string][]byte{
+		"kind":              kind,
+		"child is itself":   patch(2, 32, 2),
+		"child is ancestor": patch(2, 32, 0),
+		"child is root":     patch(0, 32, 0),
+		"too many children": patch(2, 40, 2),
+		"children overflow": patch(2, 40, math.MaxUint64),
+		"overlapping items": patch(2, 48, 0),
+		"items overflow":    patch(3, 56, math.MaxUint64),
+	} {
+		if _, err := packed.Open(invalid); err != packed.ErrInvalidFormat {
+			t.Errorf("Open(%v) error = %v, want %v", name, err, packed.ErrInvalidFormat)
+		}
+	}
+}
+
+func TestDefaultItemID(t *testing.T) {
+	tests := []struct {
+		item    interface{}
+		want    uint64
+		wantErr error
+	}{
+		{item: 7, want: 7},
+		{item: int64(8), want: 8},
+		{item: uint64(math.MaxUint64), want: math.MaxUint64},
+		{item: -1, wantErr: packed.ErrNegativeItemID},
+		{item: int32(-5), wantErr: packed.ErrNegativeItemID},
+		{item: "1", wantErr: packed.ErrItemID},
+	}
+	for _, tt := range tests {
+		got, err := packed.DefaultItemID(tt.item)
+		if got != tt.want || err != tt.wantErr {
+			t.Errorf("DefaultItemID(%v) = %v, %v, want %v, %v", tt.item, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
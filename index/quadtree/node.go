@@ -95,12 +95,12 @@ func (n *Node) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
 	}
 	found := false
 	for i := 0; i < 4; i++ {
-		if n.Subnode[i] != nil && n.Subnode[i].HasItems() {
+		if !n.Subnode[i].IsEmpty() {
 			found = n.Subnode[i].Remove(itemEnv, item)
 			if found {
 				// trim subtree if empty
 				if n.Subnode[i].IsPrunable() {
-					n.Subnode[i] = &Node{}
+					n.Subnode[i] = nil
 				}
 				break
 			}
@@ -135,7 +135,7 @@ func (n *Node) IsPrunable() bool {
 // HasChildren ...
 func (n *Node) HasChildren() bool {
 	for i := 0; i < 4; i++ {
-		if !n.Subnode[i].HasItems() {
+		if n.Subnode[i] != nil {
 			return true
 		}
 	}
@@ -152,6 +152,9 @@ func (n *Node) Visit(searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
 	n.VisitItems(searchEnv, n.Env, visitor)
 
 	for i := 0; i < 4; i++ {
+		if index.IsDone(visitor) {
+			return
+		}
 		if !n.Subnode[i].IsEmpty() {
 			n.Subnode[i].Visit(searchEnv, visitor)
 		}
@@ -162,6 +165,9 @@ func (n *Node) Visit(searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
 func (n *Node) VisitItems(searchEnv, nodeEnv *envelope.Envelope, visitor index.ItemVisitor) {
 	// would be nice to filter items based on search envelope, but can't until they contain an envelope
 	for _, v := range n.Items {
+		if index.IsDone(visitor) {
+			return
+		}
 		if searchEnv.IsIntersects(nodeEnv) {
 			visitor.VisitItem(v)
 		}
@@ -214,14 +220,9 @@ func (n *Node) IsEmpty() bool {
 		isEmpty = false
 	} else {
 		for i := 0; i < 4; i++ {
-			if n.Subnode[i] == nil {
-				continue
-			}
-			if !n.Subnode[i].HasItems() {
-				if !n.Subnode[i].IsEmpty() {
-					isEmpty = false
-					break
-				}
+			if !n.Subnode[i].IsEmpty() {
+				isEmpty = false
+				break
 			}
 		}
 	}
@@ -107,6 +107,30 @@ func (q *Quadtree) QueryVisitor(searchEnv *envelope.Envelope, visitor index.Item
 	return nil
 }
 
+// NearestNeighbours Returns the k items nearest to the query envelope within maxDistance,
+// in increasing order of distance. distance computes the exact distance of an item,
+// it is required as the quadtree does not keep the envelopes of the items, nil returns no items.
+func (q *Quadtree) NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64,
+	distance index.ItemDistance) []index.Neighbour {
+	if distance == nil {
+		return []index.Neighbour{}
+	}
+	search := index.NewNearestSearch(queryEnv, k, maxDistance, distance)
+	search.AddNode(nil, q.Root.Node)
+	return search.Search(func(node interface{}) {
+		n := node.(*Node)
+		// items lie within the envelope of their node, the root has no envelope.
+		for _, item := range n.Items {
+			search.AddItem(n.Env, item)
+		}
+		for _, subnode := range n.Subnode {
+			if !subnode.IsEmpty() {
+				search.AddNode(subnode.Env, subnode)
+			}
+		}
+	})
+}
+
 // CollectStats ...
 func (q *Quadtree) CollectStats(itemEnv *envelope.Envelope) {
 	delX := itemEnv.Width()
@@ -121,4 +145,5 @@ func (q *Quadtree) CollectStats(itemEnv *envelope.Envelope) {
 
 var (
 	_ index.SpatialIndex = &Quadtree{}
+	_ index.NearestIndex = &Quadtree{}
 )
@@ -2,12 +2,16 @@ package quadtree
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
 )
 
 var indexTree *Quadtree
@@ -75,6 +79,58 @@ func TestQuadtree_Remove(t *testing.T) {
 	}
 }
 
+func TestQuadtree_RemovePrunes(t *testing.T) {
+	q := NewQuadtree()
+	env := envelope.FourFloat(1, 1.5, 1, 1.5)
+	_ = q.Insert(env, 0)
+	_ = q.Insert(envelope.FourFloat(-3, -2, -3, -2), 1)
+	if !q.Remove(env, 0) {
+		t.Fatalf("Quadtree.Remove() = false, want true")
+	}
+	if q.Size() != 1 || q.IsEmpty() {
+		t.Errorf("Quadtree.Size() = %v, IsEmpty() = %v, want 1 and false", q.Size(), q.IsEmpty())
+	}
+	if q.Remove(env, 0) {
+		t.Errorf("Quadtree.Remove() of a removed item = true, want false")
+	}
+	if !q.Remove(envelope.FourFloat(-3, -2, -3, -2), 1) || !q.IsEmpty() {
+		t.Errorf("Quadtree.Remove() of the last item, IsEmpty() = %v, want true", q.IsEmpty())
+	}
+	for i, sub := range q.Root.Subnode {
+		if sub != nil {
+			t.Errorf("Quadtree.Remove() subnode %v = %v, want pruned", i, sub)
+		}
+	}
+}
+
+func TestNode_IsEmpty(t *testing.T) {
+	leaf := &Node{Items: []interface{}{0}}
+	tests := []struct {
+		name        string
+		node        *Node
+		isEmpty     bool
+		hasChildren bool
+	}{
+		{name: "nil node", node: nil, isEmpty: true},
+		{name: "no subnodes", node: &Node{}, isEmpty: true},
+		{name: "empty subnode", node: &Node{Subnode: [4]*Node{nil, {}}}, isEmpty: true, hasChildren: true},
+		{name: "subnode with items", node: &Node{Subnode: [4]*Node{nil, nil, leaf}}, hasChildren: true},
+		{name: "items deeper", node: &Node{Subnode: [4]*Node{{Subnode: [4]*Node{leaf}}}}, hasChildren: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.IsEmpty(); got != tt.isEmpty {
+				t.Errorf("Node.IsEmpty() = %v, want %v", got, tt.isEmpty)
+			}
+			if tt.node != nil {
+				if got := tt.node.HasChildren(); got != tt.hasChildren {
+					t.Errorf("Node.HasChildren() = %v, want %v", got, tt.hasChildren)
+				}
+			}
+		})
+	}
+}
+
 func TestQuadtree_Query(t *testing.T) {
 	seg := lineMatrix.ToLineArray()[1]
 	type args struct {
@@ -104,3 +160,59 @@ func TestQuadtree_Query(t *testing.T) {
 		})
 	}
 }
+
+func TestQuadtree_SizeQuery(t *testing.T) {
+	q := NewQuadtree()
+	_ = q.Insert(envelope.FourFloat(0, 2, 0, 2), 0)
+	_ = q.Insert(envelope.FourFloat(2, 4, 0, 2), 1)
+	_ = q.Insert(envelope.FourFloat(-1, 1, -1, 1), 2)
+	if q.Size() != 3 {
+		t.Errorf("Quadtree.Size() = %v, want %v", q.Size(), 3)
+	}
+	got := q.Query(envelope.Matrix(matrix.Matrix{3, 1}))
+	has := false
+	for _, v := range got.([]interface{}) {
+		if v == 1 {
+			has = true
+		}
+	}
+	if !has {
+		t.Errorf("Quadtree.Query() = %v, want contains %v", got, 1)
+	}
+	if !q.Remove(envelope.FourFloat(2, 4, 0, 2), 1) || q.Size() != 2 {
+		t.Errorf("Quadtree.Remove() size = %v, want %v", q.Size(), 2)
+	}
+}
+
+func TestQuadtree_NearestNeighbours(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	points := make([]matrix.Matrix, 300)
+	tree := NewQuadtree()
+	for i := range points {
+		points[i] = matrix.Matrix{random.Float64() * 100, random.Float64() * 100}
+		_ = tree.Insert(envelope.Matrix(points[i]), points[i])
+	}
+	query := matrix.Matrix{50, 50}
+	distance := func(item interface{}) float64 {
+		return measure.PlanarDistance(query, item.(matrix.Matrix))
+	}
+	for _, maxDistance := range []float64{math.Inf(1), 3} {
+		want := []float64{}
+		for _, p := range points {
+			if d := measure.PlanarDistance(query, p); d <= maxDistance {
+				want = append(want, d)
+			}
+		}
+		sort.Float64s(want)
+		if len(want) > 5 {
+			want = want[:5]
+		}
+		got := []float64{}
+		for _, n := range tree.NearestNeighbours(envelope.Matrix(query), 5, maxDistance, distance) {
+			got = append(got, n.Distance)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Quadtree.NearestNeighbours() = %v, want %v", got, want)
+		}
+	}
+}
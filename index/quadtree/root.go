@@ -60,12 +60,12 @@ func (r *Root) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
 	}
 	found := false
 	for i := 0; i < 4; i++ {
-		if r.Subnode[i] != nil && r.Subnode[i].HasItems() {
+		if !r.Subnode[i].IsEmpty() {
 			found = r.Subnode[i].Remove(itemEnv, item)
 			if found {
 				// trim subtree if empty
 				if r.Subnode[i].IsPrunable() {
-					r.Subnode[i] = &Node{}
+					r.Subnode[i] = nil
 				}
 				break
 			}
@@ -85,11 +85,18 @@ func (r *Root) Visit(searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
 	if !r.IsSearchMatch(searchEnv) {
 		return
 	}
-	// this node may have items as well as subnodes (since items may not
-	// be wholely contained in any single subnode
-	r.VisitItems(searchEnv, r.Env, visitor)
+	// the root has no extent, its items cross the axes and are always visited.
+	for _, v := range r.Items {
+		if index.IsDone(visitor) {
+			return
+		}
+		visitor.VisitItem(v)
+	}
 
 	for i := 0; i < 4; i++ {
+		if index.IsDone(visitor) {
+			return
+		}
 		if !r.Subnode[i].IsEmpty() {
 			r.Subnode[i].Visit(searchEnv, visitor)
 		}
@@ -0,0 +1,46 @@
+package quadtree
+
+import (
+	"io"
+
+	"github.com/spatial-go/geoos/index/packed"
+)
+
+// WritePacked Writes the tree in the packed binary format,
+// itemID returns the id stored for each item, nil for items which are integers.
+// The quadtree does not keep the envelopes of the items, they are written with the envelope of their node,
+// so as the quadtree the packed tree returns candidates which may not intersect the query.
+// Read the tree back with packed.Open or packed.OpenFile.
+func (q *Quadtree) WritePacked(w io.Writer, itemID packed.ItemID) error {
+	if itemID == nil {
+		itemID = packed.DefaultItemID
+	}
+	var toPacked func(n *Node) (*packed.Node, error)
+	toPacked = func(n *Node) (*packed.Node, error) {
+		// the root has no envelope, it is written unbounded.
+		node := &packed.Node{Env: n.Env}
+		for _, item := range n.Items {
+			id, err := itemID(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Items = append(node.Items, packed.Item{Env: n.Env, ID: id})
+		}
+		for _, subnode := range n.Subnode {
+			if subnode.IsEmpty() {
+				continue
+			}
+			child, err := toPacked(subnode)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+		}
+		return node, nil
+	}
+	root, err := toPacked(q.Root.Node)
+	if err != nil {
+		return err
+	}
+	return packed.Write(w, packed.KindQuadtree, root)
+}
@@ -0,0 +1,93 @@
+package rstartree
+
+import (
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+)
+
+// Entry An entry of a node, it bounds either a child node or an item in a leaf.
+type Entry struct {
+	Env   *envelope.Envelope
+	Child *Node
+	Item  interface{}
+}
+
+// Node A node of a RStarTree, nodes at level 0 are leaves holding items.
+type Node struct {
+	Level   int
+	Entries []*Entry
+}
+
+// IsLeaf Returns whether the entries of the node are items.
+func (n *Node) IsLeaf() bool {
+	return n.Level == 0
+}
+
+// Envelope Returns the envelope of all entries of the node.
+func (n *Node) Envelope() *envelope.Envelope {
+	return envelopeOf(n.Entries)
+}
+
+// entryOf returns the index of the entry of the child node, -1 if not found.
+func (n *Node) entryOf(child *Node) int {
+	for i, e := range n.Entries {
+		if e.Child == child {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeEntry removes the entry at index i.
+func (n *Node) removeEntry(i int) {
+	n.Entries = append(n.Entries[:i], n.Entries[i+1:]...)
+}
+
+// size returns the number of items in the subtree.
+func (n *Node) size() int {
+	if n.IsLeaf() {
+		return len(n.Entries)
+	}
+	size := 0
+	for _, e := range n.Entries {
+		size += e.Child.size()
+	}
+	return size
+}
+
+// union returns the envelope including both envelopes.
+func union(env1, env2 *envelope.Envelope) *envelope.Envelope {
+	env := envelope.Env(env1)
+	env.ExpandToIncludeEnv(env2)
+	return env
+}
+
+// area returns the area of the envelope.
+func area(env *envelope.Envelope) float64 {
+	return (env.MaxX - env.MinX) * (env.MaxY - env.MinY)
+}
+
+// margin returns the half perimeter of the envelope.
+func margin(env *envelope.Envelope) float64 {
+	return (env.MaxX - env.MinX) + (env.MaxY - env.MinY)
+}
+
+// overlap returns the area of the intersection of two envelopes.
+func overlap(env1, env2 *envelope.Envelope) float64 {
+	dx := math.Min(env1.MaxX, env2.MaxX) - math.Max(env1.MinX, env2.MinX)
+	dy := math.Min(env1.MaxY, env2.MaxY) - math.Max(env1.MinY, env2.MinY)
+	if dx <= 0 || dy <= 0 {
+		return 0
+	}
+	return dx * dy
+}
+
+// envelopeOf returns the envelope of the entries.
+func envelopeOf(entries []*Entry) *envelope.Envelope {
+	env := envelope.Empty()
+	for _, e := range entries {
+		env.ExpandToIncludeEnv(e.Env)
+	}
+	return env
+}
@@ -0,0 +1,331 @@
+// Package rstartree a RStarTree is a dynamic spatial index structure .
+// Items can be inserted, removed and moved at any time.
+package rstartree
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+)
+
+// RStarTree const parameter.
+const (
+	DefaultMaxEntries = 16
+	MinEntriesRatio   = 0.4
+	ReinsertRatio     = 0.3
+)
+
+// RStarTree A dynamic R*-tree.
+// The R*-tree improves the R-tree by choosing the subtree with the least overlap enlargement,
+// by reinserting part of the entries of an overflowing node once per level and insertion,
+// and by splitting nodes along the axis of least margin with the distribution of least overlap.
+// Removed items shrink the tree, the entries of underfull nodes are reinserted.
+type RStarTree struct {
+	root       *Node
+	maxEntries int
+	minEntries int
+	reinsert   int
+	size       int
+
+	// reinserted records the levels already reinserted during the current insertion.
+	reinserted map[int]bool
+}
+
+// NewRStarTree Constructs a RStarTree with the default maximum entries of a node.
+func NewRStarTree() *RStarTree {
+	return NewRStarTreeCapacity(DefaultMaxEntries)
+}
+
+// NewRStarTreeCapacity Constructs a RStarTree with the given maximum entries of a node,
+// the capacity must be at least 4, otherwise the default is used.
+func NewRStarTreeCapacity(maxEntries int) *RStarTree {
+	if maxEntries < 4 {
+		maxEntries = DefaultMaxEntries
+	}
+	r := &RStarTree{maxEntries: maxEntries, root: &Node{}}
+	r.minEntries = int(float64(maxEntries) * MinEntriesRatio)
+	if r.minEntries < 2 {
+		r.minEntries = 2
+	}
+	r.reinsert = int(float64(maxEntries) * ReinsertRatio)
+	if r.reinsert < 1 {
+		r.reinsert = 1
+	}
+	return r
+}
+
+// Size Returns the number of items in the tree.
+func (r *RStarTree) Size() int {
+	return r.size
+}
+
+// IsEmpty Tests whether the index contains any items.
+func (r *RStarTree) IsEmpty() bool {
+	return r.size == 0
+}
+
+// Depth Returns the number of levels in the tree.
+func (r *RStarTree) Depth() int {
+	if r.size == 0 {
+		return 0
+	}
+	return r.root.Level + 1
+}
+
+// Root Returns the root node of the tree.
+func (r *RStarTree) Root() *Node {
+	return r.root
+}
+
+// Insert Adds a spatial item with an extent specified by the given Envelope to the index.
+// Items with a nil envelope are ignored.
+func (r *RStarTree) Insert(itemEnv *envelope.Envelope, item interface{}) error {
+	if itemEnv == nil || itemEnv.IsNil() {
+		return nil
+	}
+	r.reinserted = map[int]bool{}
+	r.insert(&Entry{Env: envelope.Env(itemEnv), Item: item}, 0)
+	r.size++
+	return nil
+}
+
+// BulkLoad Adds the items with the envelopes to the index.
+// An empty tree is packed with the Sort-Tile-Recursive algorithm, which is much faster
+// and gives a better tree than inserting the items one by one.
+func (r *RStarTree) BulkLoad(itemEnvs []*envelope.Envelope, items []interface{}) error {
+	if len(itemEnvs) != len(items) {
+		return index.ErrBulkLoadLength
+	}
+	if r.size > 0 {
+		for i, item := range items {
+			if err := r.Insert(itemEnvs[i], item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	entries := make([]*Entry, 0, len(items))
+	for i, item := range items {
+		if itemEnvs[i] == nil || itemEnvs[i].IsNil() {
+			continue
+		}
+		entries = append(entries, &Entry{Env: envelope.Env(itemEnvs[i]), Item: item})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	r.root = pack(entries, 0, r.maxEntries)
+	r.size = len(entries)
+	return nil
+}
+
+// Query Queries the index for all items whose extents intersect the given search  Envelope
+// Note that some kinds of indexes may also return objects which do not in fact
+// intersect the query envelope.
+func (r *RStarTree) Query(searchEnv *envelope.Envelope) interface{} {
+	visitor := &index.ArrayVisitor{}
+	if err := r.QueryVisitor(searchEnv, visitor); err != nil {
+		log.Println(err)
+	}
+	return visitor.Items()
+}
+
+// QueryVisitor Queries the index for all items whose extents intersect the given search Envelope,
+// and applies an  ItemVisitor to them.
+func (r *RStarTree) QueryVisitor(searchEnv *envelope.Envelope, visitor index.ItemVisitor) error {
+	if searchEnv == nil {
+		return nil
+	}
+	query(r.root, searchEnv, visitor)
+	return nil
+}
+
+func query(n *Node, searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
+	for _, e := range n.Entries {
+		if index.IsDone(visitor) {
+			return
+		}
+		if !e.Env.IsIntersects(searchEnv) {
+			continue
+		}
+		if n.IsLeaf() {
+			visitor.VisitItem(e.Item)
+		} else {
+			query(e.Child, searchEnv, visitor)
+		}
+	}
+}
+
+// NearestNeighbours Returns the k items nearest to the query envelope within maxDistance,
+// in increasing order of distance. distance computes the exact distance of an item,
+// nil for the distance between the envelopes.
+func (r *RStarTree) NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64,
+	distance index.ItemDistance) []index.Neighbour {
+	search := index.NewNearestSearch(queryEnv, k, maxDistance, distance)
+	search.AddNode(nil, r.root)
+	return search.Search(func(node interface{}) {
+		n := node.(*Node)
+		for _, e := range n.Entries {
+			if n.IsLeaf() {
+				search.AddItem(e.Env, e.Item)
+			} else {
+				search.AddNode(e.Env, e.Child)
+			}
+		}
+	})
+}
+
+// Remove Removes a single item from the tree.
+func (r *RStarTree) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
+	if itemEnv == nil {
+		return false
+	}
+	path, i := r.findLeaf(r.root, itemEnv, item, nil)
+	if path == nil {
+		return false
+	}
+	path[len(path)-1].removeEntry(i)
+	r.size--
+	r.condense(path)
+	return true
+}
+
+// Update Moves an item from oldEnv to newEnv, returns false if the item is not found.
+// If the new envelope still lies within the envelope of the leaf of the item,
+// only the envelopes on its path are updated, otherwise the item is removed and inserted again.
+func (r *RStarTree) Update(oldEnv, newEnv *envelope.Envelope, item interface{}) bool {
+	if oldEnv == nil || newEnv == nil || newEnv.IsNil() {
+		return false
+	}
+	path, i := r.findLeaf(r.root, oldEnv, item, nil)
+	if path == nil {
+		return false
+	}
+	leaf := path[len(path)-1]
+	if len(path) == 1 || path[len(path)-2].Entries[path[len(path)-2].entryOf(leaf)].Env.Covers(newEnv) {
+		leaf.Entries[i].Env = envelope.Env(newEnv)
+		adjustPath(path)
+		return true
+	}
+	leaf.removeEntry(i)
+	r.condense(path)
+	r.reinserted = map[int]bool{}
+	r.insert(&Entry{Env: envelope.Env(newEnv), Item: item}, 0)
+	return true
+}
+
+// findLeaf returns the path from the root to the leaf holding the item, and the index of the item in the leaf.
+func (r *RStarTree) findLeaf(n *Node, itemEnv *envelope.Envelope, item interface{}, path []*Node) ([]*Node, int) {
+	path = append(path, n)
+	for i, e := range n.Entries {
+		if !e.Env.IsIntersects(itemEnv) {
+			continue
+		}
+		if n.IsLeaf() {
+			if reflect.DeepEqual(e.Item, item) {
+				return path, i
+			}
+			continue
+		}
+		if found, index := r.findLeaf(e.Child, itemEnv, item, path); found != nil {
+			return found, index
+		}
+	}
+	return nil, -1
+}
+
+// insert inserts the entry into a node at the level, handling overflows by reinsertion or split.
+func (r *RStarTree) insert(e *Entry, level int) {
+	path := []*Node{r.root}
+	for n := r.root; n.Level > level; {
+		n = chooseSubtree(n, e.Env).Child
+		path = append(path, n)
+	}
+	path[len(path)-1].Entries = append(path[len(path)-1].Entries, e)
+
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		if len(n.Entries) <= r.maxEntries {
+			continue
+		}
+		if i > 0 && !r.reinserted[n.Level] {
+			r.reinserted[n.Level] = true
+			removed := pickReinsert(n, r.reinsert)
+			adjustPath(path[:i+1])
+			for _, re := range removed {
+				r.insert(re, n.Level)
+			}
+			adjustPath(path[:i])
+			return
+		}
+		sibling := split(n, r.minEntries)
+		if i == 0 {
+			r.root = &Node{Level: n.Level + 1, Entries: []*Entry{
+				{Env: n.Envelope(), Child: n},
+				{Env: sibling.Envelope(), Child: sibling},
+			}}
+			return
+		}
+		parent := path[i-1]
+		parent.Entries = append(parent.Entries, &Entry{Env: sibling.Envelope(), Child: sibling})
+		parent.Entries[parent.entryOf(n)].Env = n.Envelope()
+	}
+	adjustPath(path)
+}
+
+// adjustPath updates the envelopes of the entries of the nodes on the path.
+func adjustPath(path []*Node) {
+	for i := len(path) - 1; i > 0; i-- {
+		parent := path[i-1]
+		if j := parent.entryOf(path[i]); j >= 0 {
+			parent.Entries[j].Env = path[i].Envelope()
+		}
+	}
+}
+
+// condense removes the underfull nodes on the path after a removal, reinserts their entries,
+// and shortens the tree while the root has a single child.
+func (r *RStarTree) condense(path []*Node) {
+	orphans := []*Node{}
+	for i := len(path) - 1; i > 0; i-- {
+		n, parent := path[i], path[i-1]
+		j := parent.entryOf(n)
+		if len(n.Entries) < r.minEntries {
+			parent.removeEntry(j)
+			orphans = append(orphans, n)
+		} else {
+			parent.Entries[j].Env = n.Envelope()
+		}
+	}
+	if len(r.root.Entries) == 0 {
+		r.root = &Node{}
+	}
+	for _, orphan := range orphans {
+		for _, e := range orphan.Entries {
+			r.reinsertEntry(e, orphan.Level)
+		}
+	}
+	for !r.root.IsLeaf() && len(r.root.Entries) == 1 {
+		r.root = r.root.Entries[0].Child
+	}
+}
+
+// reinsertEntry inserts an entry of a node at the level,
+// or its items when the tree is no longer high enough.
+func (r *RStarTree) reinsertEntry(e *Entry, level int) {
+	r.reinserted = map[int]bool{}
+	if level <= r.root.Level {
+		r.insert(e, level)
+		return
+	}
+	for _, child := range e.Child.Entries {
+		r.reinsertEntry(child, level-1)
+	}
+}
+
+var (
+	_ index.SpatialIndex = &RStarTree{}
+	_ index.NearestIndex = &RStarTree{}
+)
@@ -0,0 +1,224 @@
+package rstartree
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/index"
+)
+
+// randomEnvs returns n small envelopes in the square 0..100.
+func randomEnvs(n int, seed int64) []*envelope.Envelope {
+	random := rand.New(rand.NewSource(seed))
+	envs := make([]*envelope.Envelope, n)
+	for i := range envs {
+		x, y := random.Float64()*100, random.Float64()*100
+		envs[i] = envelope.FourFloat(x, x+random.Float64()*2, y, y+random.Float64()*2)
+	}
+	return envs
+}
+
+// bruteQuery returns the indexes of the envelopes intersecting the search envelope.
+func bruteQuery(envs []*envelope.Envelope, searchEnv *envelope.Envelope) []int {
+	result := []int{}
+	for i, env := range envs {
+		if env != nil && env.IsIntersects(searchEnv) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func sortedInts(items interface{}) []int {
+	ints := []int{}
+	for _, v := range items.([]interface{}) {
+		ints = append(ints, v.(int))
+	}
+	sort.Ints(ints)
+	return ints
+}
+
+// checkTree checks that leaves are at level 0, entries are bounded by their parents
+// and nodes other than the root are neither under nor overfull.
+func checkTree(t *testing.T, r *RStarTree) {
+	var check func(n *Node, level int, isRoot bool) int
+	check = func(n *Node, level int, isRoot bool) int {
+		if n.Level != level {
+			t.Fatalf("node level = %v, want %v", n.Level, level)
+		}
+		if len(n.Entries) > r.maxEntries || (!isRoot && len(n.Entries) < r.minEntries) {
+			t.Fatalf("node entries = %v, want %v..%v", len(n.Entries), r.minEntries, r.maxEntries)
+		}
+		if n.IsLeaf() {
+			return len(n.Entries)
+		}
+		size := 0
+		for _, e := range n.Entries {
+			if !e.Env.Equals(e.Child.Envelope()) {
+				t.Fatalf("entry envelope = %v, want %v", e.Env.ToString(), e.Child.Envelope().ToString())
+			}
+			size += check(e.Child, level-1, false)
+		}
+		return size
+	}
+	if size := check(r.root, r.root.Level, true); size != r.Size() {
+		t.Fatalf("tree size = %v, want %v", size, r.Size())
+	}
+}
+
+func checkQueries(t *testing.T, r *RStarTree, envs []*envelope.Envelope) {
+	for _, searchEnv := range []*envelope.Envelope{
+		envelope.FourFloat(10, 30, 10, 30),
+		envelope.FourFloat(50, 51, 0, 100),
+		envelope.FourFloat(-10, 110, -10, 110),
+		envelope.FourFloat(200, 300, 200, 300),
+	} {
+		if got, want := sortedInts(r.Query(searchEnv)), bruteQuery(envs, searchEnv); !reflect.DeepEqual(got, want) {
+			t.Errorf("RStarTree.Query(%v) = %v, want %v", searchEnv.ToString(), got, want)
+		}
+	}
+}
+
+func TestRStarTree_Insert(t *testing.T) {
+	envs := randomEnvs(1000, 1)
+	r := NewRStarTreeCapacity(8)
+	for i, env := range envs {
+		if err := r.Insert(env, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.Size() != 1000 || r.Depth() < 3 {
+		t.Errorf("RStarTree size = %v depth = %v", r.Size(), r.Depth())
+	}
+	checkTree(t, r)
+	checkQueries(t, r, envs)
+
+	visitor := &index.ArrayVisitor{}
+	if err := r.QueryVisitor(envelope.FourFloat(0, 100, 0, 100), visitor); err != nil || len(visitor.ItemsArray) != 1000 {
+		t.Errorf("RStarTree.QueryVisitor() = %v items, error %v", len(visitor.ItemsArray), err)
+	}
+}
+
+func TestRStarTree_Remove(t *testing.T) {
+	envs := randomEnvs(500, 2)
+	r := NewRStarTreeCapacity(6)
+	for i, env := range envs {
+		_ = r.Insert(env, i)
+	}
+	for i := 0; i < len(envs); i += 2 {
+		if !r.Remove(envs[i], i) {
+			t.Fatalf("RStarTree.Remove(%v) = false, want true", i)
+		}
+		envs[i] = nil
+	}
+	if r.Remove(envelope.FourFloat(0, 100, 0, 100), 0) {
+		t.Errorf("RStarTree.Remove() removed item = true, want false")
+	}
+	if r.Size() != 250 {
+		t.Errorf("RStarTree.Size() = %v, want %v", r.Size(), 250)
+	}
+	checkTree(t, r)
+	checkQueries(t, r, envs)
+
+	for i := 1; i < len(envs); i += 2 {
+		r.Remove(envs[i], i)
+	}
+	if !r.IsEmpty() || r.Depth() != 0 {
+		t.Errorf("RStarTree size = %v depth = %v, want empty", r.Size(), r.Depth())
+	}
+}
+
+func TestRStarTree_Update(t *testing.T) {
+	envs := randomEnvs(300, 3)
+	r := NewRStarTreeCapacity(8)
+	for i, env := range envs {
+		_ = r.Insert(env, i)
+	}
+	random := rand.New(rand.NewSource(4))
+	for step := 0; step < 3; step++ {
+		for i, env := range envs {
+			dx, dy := random.Float64()*2-1, random.Float64()*2-1
+			if step == 2 {
+				dx, dy = random.Float64()*60-30, random.Float64()*60-30
+			}
+			moved := envelope.FourFloat(env.MinX+dx, env.MaxX+dx, env.MinY+dy, env.MaxY+dy)
+			if !r.Update(env, moved, i) {
+				t.Fatalf("RStarTree.Update(%v) = false, want true", i)
+			}
+			envs[i] = moved
+		}
+	}
+	if r.Update(envelope.FourFloat(0, 1, 0, 1), envelope.FourFloat(0, 1, 0, 1), 1000) {
+		t.Errorf("RStarTree.Update() missing item = true, want false")
+	}
+	if r.Size() != 300 {
+		t.Errorf("RStarTree.Size() = %v, want %v", r.Size(), 300)
+	}
+	checkTree(t, r)
+	checkQueries(t, r, envs)
+}
+
+func TestRStarTree_BulkLoad(t *testing.T) {
+	envs := randomEnvs(1000, 5)
+	items := make([]interface{}, len(envs))
+	for i := range items {
+		items[i] = i
+	}
+	r := NewRStarTreeCapacity(10)
+	if err := r.BulkLoad(envs[:2], items); err != index.ErrBulkLoadLength {
+		t.Errorf("RStarTree.BulkLoad() error = %v, want %v", err, index.ErrBulkLoadLength)
+	}
+	if err := r.BulkLoad(envs[:900], items[:900]); err != nil {
+		t.Fatal(err)
+	}
+	if r.Size() != 900 || r.Depth() != 3 {
+		t.Errorf("RStarTree size = %v depth = %v, want %v %v", r.Size(), r.Depth(), 900, 3)
+	}
+	if err := r.BulkLoad(envs[900:], items[900:]); err != nil {
+		t.Fatal(err)
+	}
+	checkQueries(t, r, envs)
+	for i := 0; i < 900; i += 3 {
+		if !r.Remove(envs[i], i) {
+			t.Fatalf("RStarTree.Remove(%v) = false, want true", i)
+		}
+		envs[i] = nil
+	}
+	checkQueries(t, r, envs)
+}
+
+func TestRStarTree_NearestNeighbours(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	points := make([]matrix.Matrix, 300)
+	tree := NewRStarTree()
+	for i := range points {
+		points[i] = matrix.Matrix{random.Float64() * 100, random.Float64() * 100}
+		_ = tree.Insert(envelope.Matrix(points[i]), points[i])
+	}
+	query := matrix.Matrix{50, 50}
+	for _, maxDistance := range []float64{math.Inf(1), 3} {
+		want := []float64{}
+		for _, p := range points {
+			if d := measure.PlanarDistance(query, p); d <= maxDistance {
+				want = append(want, d)
+			}
+		}
+		sort.Float64s(want)
+		if len(want) > 5 {
+			want = want[:5]
+		}
+		got := []float64{}
+		for _, n := range tree.NearestNeighbours(envelope.Matrix(query), 5, maxDistance, nil) {
+			got = append(got, n.Distance)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("RStarTree.NearestNeighbours() = %v, want %v", got, want)
+		}
+	}
+}
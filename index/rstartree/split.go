@@ -0,0 +1,143 @@
+package rstartree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+)
+
+// chooseSubtree returns the entry of the node which needs the least enlargement to include env.
+// When the children are leaves the least overlap enlargement is chosen, otherwise the least area enlargement,
+// ties are resolved by area enlargement and then by area.
+func chooseSubtree(n *Node, env *envelope.Envelope) *Entry {
+	var best *Entry
+	bestOverlap, bestEnlargement, bestArea := math.Inf(1), math.Inf(1), math.Inf(1)
+	for _, e := range n.Entries {
+		enlarged := union(e.Env, env)
+		enlargement := area(enlarged) - area(e.Env)
+		overlapEnlargement := 0.0
+		if n.Level == 1 {
+			for _, other := range n.Entries {
+				if other != e {
+					overlapEnlargement += overlap(enlarged, other.Env) - overlap(e.Env, other.Env)
+				}
+			}
+		}
+		a := area(e.Env)
+		if overlapEnlargement < bestOverlap ||
+			(overlapEnlargement == bestOverlap && enlargement < bestEnlargement) ||
+			(overlapEnlargement == bestOverlap && enlargement == bestEnlargement && a < bestArea) {
+			best, bestOverlap, bestEnlargement, bestArea = e, overlapEnlargement, enlargement, a
+		}
+	}
+	return best
+}
+
+// pickReinsert removes from the node the count entries whose centres are farthest from the centre of the node,
+// and returns them ordered from the nearest to the farthest.
+func pickReinsert(n *Node, count int) []*Entry {
+	centre := n.Envelope().Centre()
+	distance := func(e *Entry) float64 {
+		c := e.Env.Centre()
+		return math.Hypot(c[0]-centre[0], c[1]-centre[1])
+	}
+	sort.SliceStable(n.Entries, func(i, j int) bool {
+		return distance(n.Entries[i]) < distance(n.Entries[j])
+	})
+	keep := len(n.Entries) - count
+	removed := make([]*Entry, count)
+	copy(removed, n.Entries[keep:])
+	n.Entries = n.Entries[:keep]
+	return removed
+}
+
+// split splits the entries of an overflowing node between the node and a new sibling, which is returned.
+// The split axis minimises the sum of margins of all distributions,
+// the distribution along it minimises the overlap and then the area of the two groups.
+func split(n *Node, minEntries int) *Node {
+	var bestSorts [][]*Entry
+	bestMargin := math.Inf(1)
+	for axis := 0; axis < 2; axis++ {
+		sorts := sortedByAxis(n.Entries, axis)
+		marginSum := 0.0
+		for _, entries := range sorts {
+			for k := minEntries; k <= len(entries)-minEntries; k++ {
+				marginSum += margin(envelopeOf(entries[:k])) + margin(envelopeOf(entries[k:]))
+			}
+		}
+		if marginSum < bestMargin {
+			bestMargin, bestSorts = marginSum, sorts
+		}
+	}
+
+	var best []*Entry
+	bestK := 0
+	bestOverlap, bestArea := math.Inf(1), math.Inf(1)
+	for _, entries := range bestSorts {
+		for k := minEntries; k <= len(entries)-minEntries; k++ {
+			env1, env2 := envelopeOf(entries[:k]), envelopeOf(entries[k:])
+			o, a := overlap(env1, env2), area(env1)+area(env2)
+			if o < bestOverlap || (o == bestOverlap && a < bestArea) {
+				best, bestK, bestOverlap, bestArea = entries, k, o, a
+			}
+		}
+	}
+	sibling := &Node{Level: n.Level, Entries: append([]*Entry{}, best[bestK:]...)}
+	n.Entries = append([]*Entry{}, best[:bestK]...)
+	return sibling
+}
+
+// sortedByAxis returns the entries sorted by their lower and by their upper value on the axis.
+func sortedByAxis(entries []*Entry, axis int) [][]*Entry {
+	lower := append([]*Entry{}, entries...)
+	upper := append([]*Entry{}, entries...)
+	if axis == 0 {
+		sort.SliceStable(lower, func(i, j int) bool {
+			return lower[i].Env.MinX < lower[j].Env.MinX ||
+				(lower[i].Env.MinX == lower[j].Env.MinX && lower[i].Env.MaxX < lower[j].Env.MaxX)
+		})
+		sort.SliceStable(upper, func(i, j int) bool {
+			return upper[i].Env.MaxX < upper[j].Env.MaxX ||
+				(upper[i].Env.MaxX == upper[j].Env.MaxX && upper[i].Env.MinX < upper[j].Env.MinX)
+		})
+	} else {
+		sort.SliceStable(lower, func(i, j int) bool {
+			return lower[i].Env.MinY < lower[j].Env.MinY ||
+				(lower[i].Env.MinY == lower[j].Env.MinY && lower[i].Env.MaxY < lower[j].Env.MaxY)
+		})
+		sort.SliceStable(upper, func(i, j int) bool {
+			return upper[i].Env.MaxY < upper[j].Env.MaxY ||
+				(upper[i].Env.MaxY == upper[j].Env.MaxY && upper[i].Env.MinY < upper[j].Env.MinY)
+		})
+	}
+	return [][]*Entry{lower, upper}
+}
+
+// pack builds the levels of a tree from the entries by Sort-Tile-Recursive packing, and returns the root.
+func pack(entries []*Entry, level, nodeCapacity int) *Node {
+	minNodeCount := int(math.Ceil(float64(len(entries)) / float64(nodeCapacity)))
+	sliceCount := int(math.Ceil(math.Sqrt(float64(minNodeCount))))
+	sliceCapacity := int(math.Ceil(float64(len(entries))/float64(sliceCount*nodeCapacity))) * nodeCapacity
+
+	sorted := append([]*Entry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Env.MinX+sorted[i].Env.MaxX < sorted[j].Env.MinX+sorted[j].Env.MaxX
+	})
+	parents := []*Entry{}
+	for start := 0; start < len(sorted); start += sliceCapacity {
+		slice := sorted[start:int(math.Min(float64(start+sliceCapacity), float64(len(sorted))))]
+		sort.SliceStable(slice, func(i, j int) bool {
+			return slice[i].Env.MinY+slice[i].Env.MaxY < slice[j].Env.MinY+slice[j].Env.MaxY
+		})
+		for i := 0; i < len(slice); i += nodeCapacity {
+			j := int(math.Min(float64(i+nodeCapacity), float64(len(slice))))
+			node := &Node{Level: level, Entries: append([]*Entry{}, slice[i:j]...)}
+			parents = append(parents, &Entry{Env: node.Envelope(), Child: node})
+		}
+	}
+	if len(parents) == 1 {
+		return parents[0].Child
+	}
+	return pack(parents, level+1, nodeCapacity)
+}
@@ -0,0 +1,123 @@
+package strtree
+
+import (
+	"reflect"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+)
+
+// Boundable A spatial object in a STRtree, either an Item or a Node.
+type Boundable interface {
+	// Envelope Returns the bounds of this object.
+	Envelope() *envelope.Envelope
+}
+
+// Item Boundable wrapper for a non-Boundable spatial object.
+type Item struct {
+	Env  *envelope.Envelope
+	Item interface{}
+}
+
+// Envelope Returns the bounds of the item.
+func (it *Item) Envelope() *envelope.Envelope {
+	return it.Env
+}
+
+// Node A node of a STRtree, the children of a node at level 0 are items,
+// the children of higher nodes are nodes.
+type Node struct {
+	Env      *envelope.Envelope
+	Level    int
+	Children []Boundable
+}
+
+// newNode creates a node with the children, its envelope includes all children.
+func newNode(level int, children []Boundable) *Node {
+	n := &Node{Level: level, Children: make([]Boundable, len(children))}
+	copy(n.Children, children)
+	n.computeEnvelope()
+	return n
+}
+
+// Envelope Returns the bounds of the node.
+func (n *Node) Envelope() *envelope.Envelope {
+	return n.Env
+}
+
+// IsLeaf Returns whether the children of the node are items.
+func (n *Node) IsLeaf() bool {
+	return n.Level == 0
+}
+
+func (n *Node) computeEnvelope() {
+	n.Env = envelope.Empty()
+	for _, child := range n.Children {
+		n.Env.ExpandToIncludeEnv(child.Envelope())
+	}
+}
+
+func (n *Node) query(searchEnv *envelope.Envelope, visitor index.ItemVisitor) {
+	for _, child := range n.Children {
+		if index.IsDone(visitor) {
+			return
+		}
+		if !child.Envelope().IsIntersects(searchEnv) {
+			continue
+		}
+		switch c := child.(type) {
+		case *Item:
+			visitor.VisitItem(c.Item)
+		case *Node:
+			c.query(searchEnv, visitor)
+		}
+	}
+}
+
+// remove removes the item from the subtree, empty nodes are removed and envelopes shrink.
+func (n *Node) remove(itemEnv *envelope.Envelope, item interface{}) bool {
+	for i, child := range n.Children {
+		if !child.Envelope().IsIntersects(itemEnv) {
+			continue
+		}
+		found := false
+		switch c := child.(type) {
+		case *Item:
+			found = reflect.DeepEqual(c.Item, item)
+		case *Node:
+			if found = c.remove(itemEnv, item); found && len(c.Children) > 0 {
+				n.computeEnvelope()
+				return true
+			}
+		}
+		if found {
+			n.Children = append(n.Children[:i], n.Children[i+1:]...)
+			n.computeEnvelope()
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Node) size() int {
+	if n.IsLeaf() {
+		return len(n.Children)
+	}
+	size := 0
+	for _, child := range n.Children {
+		size += child.(*Node).size()
+	}
+	return size
+}
+
+func (n *Node) depth() int {
+	maxChildDepth := 0
+	for _, child := range n.Children {
+		if c, ok := child.(*Node); ok {
+			if d := c.depth(); d > maxChildDepth {
+				maxChildDepth = d
+			}
+		}
+	}
+	return maxChildDepth + 1
+}
@@ -0,0 +1,46 @@
+package strtree
+
+import (
+	"io"
+
+	"github.com/spatial-go/geoos/index/packed"
+)
+
+// WritePacked Writes the tree in the packed binary format, building it if necessary,
+// itemID returns the id stored for each item, nil for items which are integers.
+// Read the tree back with packed.Open or packed.OpenFile.
+func (s *STRtree) WritePacked(w io.Writer, itemID packed.ItemID) error {
+	s.Build()
+	if itemID == nil {
+		itemID = packed.DefaultItemID
+	}
+	if s.root == nil {
+		return packed.Write(w, packed.KindSTRtree, nil)
+	}
+	var toPacked func(n *Node) (*packed.Node, error)
+	toPacked = func(n *Node) (*packed.Node, error) {
+		node := &packed.Node{Env: n.Env}
+		for _, child := range n.Children {
+			switch c := child.(type) {
+			case *Item:
+				id, err := itemID(c.Item)
+				if err != nil {
+					return nil, err
+				}
+				node.Items = append(node.Items, packed.Item{Env: c.Env, ID: id})
+			case *Node:
+				packedChild, err := toPacked(c)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, packedChild)
+			}
+		}
+		return node, nil
+	}
+	root, err := toPacked(s.root)
+	if err != nil {
+		return err
+	}
+	return packed.Write(w, packed.KindSTRtree, root)
+}
@@ -0,0 +1,225 @@
+// Package strtree a STRtree is a spatial index structure .
+// This is a static R-tree which is packed by using the Sort-Tile-Recursive algorithm.
+package strtree
+
+import (
+	"log"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/index"
+)
+
+// DefaultNodeCapacity the default maximum number of children of a node.
+const DefaultNodeCapacity = 10
+
+// STRtree A query-only R-tree created using the Sort-Tile-Recursive (STR) algorithm.
+// The STR packed R-tree is simple to implement and maximizes space utilization;
+// that is, as many leaves as possible are filled to capacity.
+// Overlap between nodes is far less than in a basic R-tree.
+// The tree is built lazily on the first query, items cannot be inserted once it has been built,
+// but they can still be removed.
+type STRtree struct {
+	nodeCapacity int
+	root         *Node
+	items        []Boundable
+	isBuilt      bool
+}
+
+// NewSTRtree Constructs an STRtree with the default node capacity.
+func NewSTRtree() *STRtree {
+	return NewSTRtreeCapacity(DefaultNodeCapacity)
+}
+
+// NewSTRtreeCapacity Constructs an STRtree with the given maximum number of children of a node,
+// the capacity must be at least 2, otherwise the default is used.
+func NewSTRtreeCapacity(nodeCapacity int) *STRtree {
+	if nodeCapacity < 2 {
+		nodeCapacity = DefaultNodeCapacity
+	}
+	return &STRtree{nodeCapacity: nodeCapacity}
+}
+
+// NodeCapacity Returns the maximum number of children of a node.
+func (s *STRtree) NodeCapacity() int {
+	return s.nodeCapacity
+}
+
+// Insert Adds a spatial item with an extent specified by the given Envelope to the index.
+// Items with a nil envelope are ignored.
+func (s *STRtree) Insert(itemEnv *envelope.Envelope, item interface{}) error {
+	if s.isBuilt {
+		return index.ErrRTreeQueried
+	}
+	if itemEnv == nil || itemEnv.IsNil() {
+		return nil
+	}
+	s.items = append(s.items, &Item{Env: itemEnv, Item: item})
+	return nil
+}
+
+// Query Queries the index for all items whose extents intersect the given search  Envelope
+// Note that some kinds of indexes may also return objects which do not in fact
+// intersect the query envelope.
+func (s *STRtree) Query(searchEnv *envelope.Envelope) interface{} {
+	visitor := &index.ArrayVisitor{}
+	if err := s.QueryVisitor(searchEnv, visitor); err != nil {
+		log.Println(err)
+	}
+	return visitor.Items()
+}
+
+// QueryVisitor Queries the index for all items whose extents intersect the given search Envelope,
+// and applies an  ItemVisitor to them.
+func (s *STRtree) QueryVisitor(searchEnv *envelope.Envelope, visitor index.ItemVisitor) error {
+	s.Build()
+	if s.root == nil || searchEnv == nil || !s.root.Env.IsIntersects(searchEnv) {
+		return nil
+	}
+	s.root.query(searchEnv, visitor)
+	return nil
+}
+
+// Remove Removes a single item from the tree.
+func (s *STRtree) Remove(itemEnv *envelope.Envelope, item interface{}) bool {
+	if !s.isBuilt {
+		for i, v := range s.items {
+			if reflect.DeepEqual(v.(*Item).Item, item) {
+				s.items = append(s.items[:i], s.items[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+	if s.root == nil || itemEnv == nil {
+		return false
+	}
+	return s.root.remove(itemEnv, item)
+}
+
+// NearestNeighbours Returns the k items nearest to the query envelope within maxDistance,
+// in increasing order of distance. distance computes the exact distance of an item,
+// nil for the distance between the envelopes.
+func (s *STRtree) NearestNeighbours(queryEnv *envelope.Envelope, k int, maxDistance float64,
+	distance index.ItemDistance) []index.Neighbour {
+	s.Build()
+	if s.root == nil {
+		return []index.Neighbour{}
+	}
+	search := index.NewNearestSearch(queryEnv, k, maxDistance, distance)
+	search.AddNode(s.root.Env, s.root)
+	return search.Search(func(node interface{}) {
+		for _, child := range node.(*Node).Children {
+			switch c := child.(type) {
+			case *Item:
+				search.AddItem(c.Env, c.Item)
+			case *Node:
+				search.AddNode(c.Env, c)
+			}
+		}
+	})
+}
+
+// Size Returns the number of items in the tree.
+func (s *STRtree) Size() int {
+	if !s.isBuilt {
+		return len(s.items)
+	}
+	if s.root == nil {
+		return 0
+	}
+	return s.root.size()
+}
+
+// Depth Returns the number of levels in the tree.
+func (s *STRtree) Depth() int {
+	s.Build()
+	if s.root == nil {
+		return 0
+	}
+	return s.root.depth()
+}
+
+// IsEmpty Tests whether the index contains any items.
+func (s *STRtree) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Root Returns the root node of the tree, building it if necessary, nil if the tree is empty.
+func (s *STRtree) Root() *Node {
+	s.Build()
+	return s.root
+}
+
+// Build Creates the tree from the inserted items, if not already built.
+// Build is called by the first query, no more items can be inserted after.
+func (s *STRtree) Build() {
+	if s.isBuilt {
+		return
+	}
+	s.isBuilt = true
+	if len(s.items) == 0 {
+		return
+	}
+	s.root = s.createHigherLevels(s.items, 0)
+	s.items = nil
+}
+
+// createHigherLevels creates the levels above the given children until a single root remains.
+func (s *STRtree) createHigherLevels(children []Boundable, level int) *Node {
+	parents := s.createParentNodes(children, level)
+	if len(parents) == 1 {
+		return parents[0].(*Node)
+	}
+	return s.createHigherLevels(parents, level+1)
+}
+
+// createParentNodes sorts the children into vertical slices by the x of their centres,
+// then packs each slice sorted by y into nodes of full capacity.
+func (s *STRtree) createParentNodes(children []Boundable, level int) []Boundable {
+	minLeafCount := int(math.Ceil(float64(len(children)) / float64(s.nodeCapacity)))
+	sliceCount := int(math.Ceil(math.Sqrt(float64(minLeafCount))))
+	sliceCapacity := int(math.Ceil(float64(len(children)) / float64(sliceCount)))
+
+	sorted := make([]Boundable, len(children))
+	copy(sorted, children)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return centreX(sorted[i].Envelope()) < centreX(sorted[j].Envelope())
+	})
+
+	parents := []Boundable{}
+	for start := 0; start < len(sorted); start += sliceCapacity {
+		end := start + sliceCapacity
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		slice := sorted[start:end]
+		sort.SliceStable(slice, func(i, j int) bool {
+			return centreY(slice[i].Envelope()) < centreY(slice[j].Envelope())
+		})
+		for i := 0; i < len(slice); i += s.nodeCapacity {
+			j := i + s.nodeCapacity
+			if j > len(slice) {
+				j = len(slice)
+			}
+			parents = append(parents, newNode(level, slice[i:j]))
+		}
+	}
+	return parents
+}
+
+func centreX(env *envelope.Envelope) float64 {
+	return (env.MinX + env.MaxX) / 2
+}
+
+func centreY(env *envelope.Envelope) float64 {
+	return (env.MinY + env.MaxY) / 2
+}
+
+var (
+	_ index.SpatialIndex = &STRtree{}
+	_ index.NearestIndex = &STRtree{}
+	_ index.Builder      = &STRtree{}
+)
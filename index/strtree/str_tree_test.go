@@ -0,0 +1,147 @@
+package strtree
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/measure"
+	"github.com/spatial-go/geoos/index"
+)
+
+// gridTree builds a tree of n*n unit cells, the item of a cell is its index.
+func gridTree(n, nodeCapacity int) *STRtree {
+	tree := NewSTRtreeCapacity(nodeCapacity)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			_ = tree.Insert(envelope.FourFloat(float64(i), float64(i+1), float64(j), float64(j+1)), i*n+j)
+		}
+	}
+	return tree
+}
+
+func sortedInts(items interface{}) []int {
+	ints := []int{}
+	for _, v := range items.([]interface{}) {
+		ints = append(ints, v.(int))
+	}
+	sort.Ints(ints)
+	return ints
+}
+
+func TestSTRtree_Query(t *testing.T) {
+	tree := gridTree(10, 4)
+	tests := []struct {
+		name      string
+		searchEnv *envelope.Envelope
+		want      []int
+	}{
+		{name: "query inner", searchEnv: envelope.FourFloat(2.5, 2.6, 3.5, 3.6), want: []int{23}},
+		{name: "query corner", searchEnv: envelope.FourFloat(1, 1, 1, 1), want: []int{0, 1, 10, 11}},
+		{name: "query range", searchEnv: envelope.FourFloat(8.5, 20, 8.5, 20), want: []int{88, 89, 98, 99}},
+		{name: "query outside", searchEnv: envelope.FourFloat(20, 30, 20, 30), want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortedInts(tree.Query(tt.searchEnv)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("STRtree.Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	visitor := &index.ArrayVisitor{}
+	if err := tree.QueryVisitor(envelope.FourFloat(0, 10, 0, 10), visitor); err != nil {
+		t.Errorf("STRtree.QueryVisitor() error = %v", err)
+	}
+	if len(visitor.ItemsArray) != 100 {
+		t.Errorf("STRtree.QueryVisitor() = %v items, want %v", len(visitor.ItemsArray), 100)
+	}
+}
+
+func TestSTRtree_SizeDepth(t *testing.T) {
+	tests := []struct {
+		name         string
+		n            int
+		nodeCapacity int
+		size, depth  int
+	}{
+		{name: "empty", n: 0, nodeCapacity: 10, size: 0, depth: 0},
+		{name: "one leaf", n: 3, nodeCapacity: 10, size: 9, depth: 1},
+		{name: "two levels", n: 5, nodeCapacity: 10, size: 25, depth: 2},
+		{name: "three levels", n: 10, nodeCapacity: 4, size: 100, depth: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := gridTree(tt.n, tt.nodeCapacity)
+			if got := tree.Size(); got != tt.size {
+				t.Errorf("STRtree.Size() = %v, want %v", got, tt.size)
+			}
+			if got := tree.Depth(); got != tt.depth {
+				t.Errorf("STRtree.Depth() = %v, want %v", got, tt.depth)
+			}
+			if got := tree.IsEmpty(); got != (tt.size == 0) {
+				t.Errorf("STRtree.IsEmpty() = %v, want %v", got, tt.size == 0)
+			}
+		})
+	}
+}
+
+func TestSTRtree_InsertRemove(t *testing.T) {
+	tree := gridTree(5, 4)
+	if !tree.Remove(envelope.FourFloat(0, 1, 0, 1), 0) {
+		t.Errorf("STRtree.Remove() before build = false, want true")
+	}
+	if tree.Size() != 24 {
+		t.Errorf("STRtree.Size() = %v, want %v", tree.Size(), 24)
+	}
+	_ = tree.Query(envelope.FourFloat(0, 1, 0, 1))
+	if err := tree.Insert(envelope.FourFloat(0, 1, 0, 1), 0); err != index.ErrRTreeQueried {
+		t.Errorf("STRtree.Insert() after build error = %v, want %v", err, index.ErrRTreeQueried)
+	}
+	if !tree.Remove(envelope.FourFloat(4, 5, 4, 5), 24) {
+		t.Errorf("STRtree.Remove() = false, want true")
+	}
+	if tree.Remove(envelope.FourFloat(4, 5, 4, 5), 24) {
+		t.Errorf("STRtree.Remove() twice = true, want false")
+	}
+	if got := sortedInts(tree.Query(envelope.FourFloat(3.5, 5, 3.5, 5))); !reflect.DeepEqual(got, []int{18, 19, 23}) {
+		t.Errorf("STRtree.Query() after remove = %v, want %v", got, []int{18, 19, 23})
+	}
+	if tree.Size() != 23 {
+		t.Errorf("STRtree.Size() = %v, want %v", tree.Size(), 23)
+	}
+}
+
+func TestSTRtree_NearestNeighbours(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	points := make([]matrix.Matrix, 300)
+	tree := NewSTRtree()
+	for i := range points {
+		points[i] = matrix.Matrix{random.Float64() * 100, random.Float64() * 100}
+		_ = tree.Insert(envelope.Matrix(points[i]), points[i])
+	}
+	query := matrix.Matrix{50, 50}
+	for _, maxDistance := range []float64{math.Inf(1), 3} {
+		want := []float64{}
+		for _, p := range points {
+			if d := measure.PlanarDistance(query, p); d <= maxDistance {
+				want = append(want, d)
+			}
+		}
+		sort.Float64s(want)
+		if len(want) > 5 {
+			want = want[:5]
+		}
+		got := []float64{}
+		for _, n := range tree.NearestNeighbours(envelope.Matrix(query), 5, maxDistance, nil) {
+			got = append(got, n.Distance)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("STRtree.NearestNeighbours() = %v, want %v", got, want)
+		}
+	}
+}
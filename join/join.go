@@ -0,0 +1,323 @@
+// Package join spatial joins of GeoJSON feature collections.
+//
+// The right collection is indexed, each left feature queries the index for candidates
+// which are refined with the exact predicates of planar.Algorithm.
+package join
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/geoencoding/geojson"
+	"github.com/spatial-go/geoos/index"
+	"github.com/spatial-go/geoos/index/hprtree"
+	"github.com/spatial-go/geoos/index/strtree"
+	"github.com/spatial-go/geoos/planar"
+	"github.com/spatial-go/geoos/space"
+)
+
+// Predicate the spatial relationship of the left and right features of a pair.
+type Predicate int
+
+// Predicates.
+const (
+	// Intersects the features share any portion of space.
+	Intersects Predicate = iota
+	// Contains the left feature contains the right feature.
+	Contains
+	// Within the left feature is within the right feature.
+	Within
+	// DWithin the features are within Options.Distance of each other.
+	DWithin
+	// Nearest the right features are the Options.K nearest to the left feature,
+	// within Options.Distance if positive.
+	Nearest
+)
+
+// Type the semantics of the join.
+type Type int
+
+// Types of join.
+const (
+	// Inner keeps the matched pairs only.
+	Inner Type = iota
+	// Left keeps also the unmatched left features, paired with no right feature.
+	Left
+)
+
+// IndexType the index built over the right features.
+type IndexType int
+
+// Types of index.
+const (
+	IndexSTRtree IndexType = iota
+	IndexHPRTree
+)
+
+// Default prefixes of the properties of merged features.
+const (
+	DefaultLeftPrefix  = "left_"
+	DefaultRightPrefix = "right_"
+)
+
+// PropertyDistance the property of merged features holding the distance of a DWithin or Nearest pair.
+const PropertyDistance = "distance"
+
+// ErrNilFeatureCollection ...
+var ErrNilFeatureCollection = fmt.Errorf("feature collection is nil")
+
+// ErrInvalidPredicate ...
+var ErrInvalidPredicate = fmt.Errorf("invalid join predicate")
+
+// ErrInvalidDistance ...
+var ErrInvalidDistance = fmt.Errorf("join distance must be non-negative")
+
+// Options the parameters of a join, the zero value is an inner intersects join over an STRtree.
+type Options struct {
+	Predicate Predicate
+	// Distance the distance of DWithin, the maximum distance of Nearest if positive.
+	Distance float64
+	// K the number of nearest right features of Nearest, 1 if not positive.
+	K     int
+	Type  Type
+	Index IndexType
+	// Workers the number of goroutines refining the left features, runtime.GOMAXPROCS if not positive.
+	Workers int
+	// LeftPrefix and RightPrefix prefix the properties of merged features,
+	// DefaultLeftPrefix and DefaultRightPrefix if empty.
+	LeftPrefix, RightPrefix string
+}
+
+// Pair A matched pair of features, with their indexes in the collections.
+// Right is nil and RightIndex is -1 for an unmatched left feature of a Left join.
+type Pair struct {
+	Left       *geojson.Feature
+	Right      *geojson.Feature
+	LeftIndex  int
+	RightIndex int
+	// Distance the distance of the features for DWithin and Nearest, 0 otherwise.
+	Distance float64
+}
+
+// Join Returns the pairs of left and right features matching the predicate,
+// ordered by left feature, then by right feature or by distance for Nearest.
+func Join(left, right *geojson.FeatureCollection, opts Options) ([]Pair, error) {
+	if left == nil || right == nil {
+		return nil, ErrNilFeatureCollection
+	}
+	if opts.Predicate < Intersects || opts.Predicate > Nearest {
+		return nil, ErrInvalidPredicate
+	}
+	if opts.Distance < 0 {
+		return nil, ErrInvalidDistance
+	}
+	j := &joiner{
+		opts:     opts,
+		right:    right.Features,
+		geoms:    make([]space.Geometry, len(right.Features)),
+		strategy: planar.NormalStrategy(),
+	}
+	if err := j.buildIndex(); err != nil {
+		return nil, err
+	}
+	return j.join(left.Features)
+}
+
+// JoinFeatures Returns the features merged from the matched pairs of Join,
+// with the geometry and id of the left feature, and the properties of both features prefixed.
+func JoinFeatures(left, right *geojson.FeatureCollection, opts Options) (*geojson.FeatureCollection, error) {
+	pairs, err := Join(left, right, opts)
+	if err != nil {
+		return nil, err
+	}
+	leftPrefix, rightPrefix := opts.LeftPrefix, opts.RightPrefix
+	if leftPrefix == "" {
+		leftPrefix = DefaultLeftPrefix
+	}
+	if rightPrefix == "" {
+		rightPrefix = DefaultRightPrefix
+	}
+	fc := geojson.NewFeatureCollection()
+	for _, pair := range pairs {
+		feature := geojson.NewFeature(pair.Left.Geometry)
+		feature.ID = pair.Left.ID
+		for k, v := range pair.Left.Properties {
+			feature.Properties[leftPrefix+k] = v
+		}
+		if pair.Right != nil {
+			for k, v := range pair.Right.Properties {
+				feature.Properties[rightPrefix+k] = v
+			}
+			if opts.Predicate == DWithin || opts.Predicate == Nearest {
+				feature.Properties[PropertyDistance] = pair.Distance
+			}
+		}
+		fc.Append(feature)
+	}
+	return fc, nil
+}
+
+// joiner joins left features against the indexed right features.
+type joiner struct {
+	opts     Options
+	right    []*geojson.Feature
+	geoms    []space.Geometry
+	index    index.SpatialIndex
+	strategy planar.Algorithm
+}
+
+// buildIndex indexes the right features by the envelopes of their geometries, empty geometries are skipped.
+func (j *joiner) buildIndex() error {
+	switch j.opts.Index {
+	case IndexHPRTree:
+		j.index = hprtree.NewHPRTree()
+	default:
+		j.index = strtree.NewSTRtree()
+	}
+	for i, feature := range j.right {
+		geom := feature.Geometry.Geometry()
+		if geom == nil || geom.IsEmpty() {
+			continue
+		}
+		j.geoms[i] = geom
+		if err := j.index.Insert(geometryEnvelope(geom), i); err != nil {
+			return err
+		}
+	}
+	// built now, so the concurrent queries never modify the index.
+	if builder, ok := j.index.(index.Builder); ok {
+		builder.Build()
+	}
+	return nil
+}
+
+// join refines the left features in parallel, the pairs of each left feature are kept together in order.
+func (j *joiner) join(left []*geojson.Feature) ([]Pair, error) {
+	workers := j.opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(left) {
+		workers = len(left)
+	}
+	results := make([][]Pair, len(left))
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(left); i += workers {
+				pairs, err := j.match(i, left[i])
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				results[i] = pairs
+			}
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	pairs := []Pair{}
+	for i, matched := range results {
+		if len(matched) == 0 && j.opts.Type == Left {
+			matched = []Pair{{Left: left[i], LeftIndex: i, RightIndex: -1}}
+		}
+		pairs = append(pairs, matched...)
+	}
+	return pairs, nil
+}
+
+// match returns the pairs of a left feature.
+func (j *joiner) match(i int, feature *geojson.Feature) ([]Pair, error) {
+	geom := feature.Geometry.Geometry()
+	if geom == nil || geom.IsEmpty() {
+		return nil, nil
+	}
+	env := geometryEnvelope(geom)
+	if j.opts.Predicate == Nearest {
+		return j.nearest(i, feature, geom, env)
+	}
+	if j.opts.Predicate == DWithin {
+		env.ExpandBy(j.opts.Distance)
+	}
+	candidates := []int{}
+	for _, item := range j.index.Query(env).([]interface{}) {
+		candidates = append(candidates, item.(int))
+	}
+	sort.Ints(candidates)
+	pairs := []Pair{}
+	for _, r := range candidates {
+		ok, distance, err := j.refine(geom, j.geoms[r])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			pairs = append(pairs, Pair{Left: feature, Right: j.right[r], LeftIndex: i, RightIndex: r, Distance: distance})
+		}
+	}
+	return pairs, nil
+}
+
+// refine evaluates the exact predicate of a candidate pair.
+func (j *joiner) refine(geom, other space.Geometry) (bool, float64, error) {
+	switch j.opts.Predicate {
+	case Contains:
+		ok, err := j.strategy.Contains(geom, other)
+		return ok, 0, err
+	case Within:
+		ok, err := j.strategy.Within(geom, other)
+		return ok, 0, err
+	case DWithin:
+		distance, err := j.strategy.Distance(geom, other)
+		return err == nil && distance <= j.opts.Distance, distance, err
+	default:
+		ok, err := j.strategy.Intersects(geom, other)
+		return ok, 0, err
+	}
+}
+
+// nearest returns the pairs of a left feature with its nearest right features.
+func (j *joiner) nearest(i int, feature *geojson.Feature, geom space.Geometry, env *envelope.Envelope) ([]Pair, error) {
+	k := j.opts.K
+	if k <= 0 {
+		k = 1
+	}
+	maxDistance := math.Inf(1)
+	if j.opts.Distance > 0 {
+		maxDistance = j.opts.Distance
+	}
+	var err error
+	neighbours := j.index.(index.NearestIndex).NearestNeighbours(env, k, maxDistance, func(item interface{}) float64 {
+		distance, e := j.strategy.Distance(geom, j.geoms[item.(int)])
+		if e != nil {
+			err = e
+			return math.Inf(1)
+		}
+		return distance
+	})
+	if err != nil {
+		return nil, err
+	}
+	pairs := []Pair{}
+	for _, neighbour := range neighbours {
+		r := neighbour.Item.(int)
+		pairs = append(pairs, Pair{Left: feature, Right: j.right[r], LeftIndex: i, RightIndex: r, Distance: neighbour.Distance})
+	}
+	return pairs, nil
+}
+
+func geometryEnvelope(geom space.Geometry) *envelope.Envelope {
+	bound := geom.Bound()
+	return envelope.TwoMatrix(matrix.Matrix(bound.Min), matrix.Matrix(bound.Max))
+}
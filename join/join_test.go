@@ -0,0 +1,142 @@
+package join
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spatial-go/geoos/geoencoding/geojson"
+	"github.com/spatial-go/geoos/space"
+)
+
+func collection(geoms ...space.Geometry) *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for i, geom := range geoms {
+		feature := geojson.NewFeature(*geojson.NewGeometry(geom))
+		feature.ID = i
+		feature.Properties["name"] = i
+		fc.Append(feature)
+	}
+	return fc
+}
+
+// districts two unit squares side by side and a square apart.
+var districts = collection(
+	space.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	space.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}},
+	space.Polygon{{{5, 5}, {6, 5}, {6, 6}, {5, 6}, {5, 5}}},
+)
+
+var positions = collection(
+	space.Point{0.5, 0.5},
+	space.Point{1, 0.5},
+	space.Point{3, 0.5},
+	space.Point{5.5, 5.5},
+)
+
+// interior the positions not on the boundary of a district.
+var interior = collection(
+	space.Point{0.5, 0.5},
+	space.Point{3, 0.5},
+	space.Point{5.5, 5.5},
+)
+
+type matched [][2]int
+
+func pairIndexes(pairs []Pair) matched {
+	result := matched{}
+	for _, pair := range pairs {
+		result = append(result, [2]int{pair.LeftIndex, pair.RightIndex})
+	}
+	return result
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right *geojson.FeatureCollection
+		opts        Options
+		want        matched
+	}{
+		{"intersects", positions, districts, Options{}, matched{{0, 0}, {1, 0}, {1, 1}, {3, 2}}},
+		{"intersects hprtree", positions, districts, Options{Index: IndexHPRTree, Workers: 3},
+			matched{{0, 0}, {1, 0}, {1, 1}, {3, 2}}},
+		{"left join", positions, districts, Options{Type: Left}, matched{{0, 0}, {1, 0}, {1, 1}, {2, -1}, {3, 2}}},
+		{"within", interior, districts, Options{Predicate: Within}, matched{{0, 0}, {2, 2}}},
+		{"contains", districts, interior, Options{Predicate: Contains}, matched{{0, 0}, {2, 2}}},
+		{"dwithin", positions, districts, Options{Predicate: DWithin, Distance: 1},
+			matched{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 1}, {3, 2}}},
+		{"nearest", positions, districts, Options{Predicate: Nearest}, matched{{0, 0}, {1, 0}, {2, 1}, {3, 2}}},
+		{"nearest max distance", positions, districts, Options{Predicate: Nearest, Distance: 0.5, Type: Left},
+			matched{{0, 0}, {1, 0}, {2, -1}, {3, 2}}},
+		{"nearest k", collection(space.Point{0.5, 0.5}), districts, Options{Predicate: Nearest, K: 2}, matched{{0, 0}, {0, 1}}},
+		{"empty", geojson.NewFeatureCollection(), districts, Options{}, matched{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := Join(tt.left, tt.right, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pairIndexes(pairs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Join() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoin_Parallel(t *testing.T) {
+	geoms := []space.Geometry{}
+	for i := 0; i < 500; i++ {
+		geoms = append(geoms, space.Point{float64(i%50) + 0.5, float64(i/50) + 0.5})
+	}
+	points := collection(geoms...)
+	for _, opts := range []Options{{Workers: 1}, {Workers: 8}, {Workers: 8, Index: IndexHPRTree}} {
+		pairs, err := Join(points, districts, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := pairIndexes(pairs), (matched{{0, 0}, {1, 1}, {255, 2}}); !reflect.DeepEqual(got, want) {
+			t.Errorf("Join(%+v) = %v, want %v", opts, got, want)
+		}
+	}
+}
+
+func TestJoinFeatures(t *testing.T) {
+	fc, err := JoinFeatures(positions, districts, Options{Predicate: DWithin, Distance: 0.1, Type: Left, RightPrefix: "district_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 5 {
+		t.Fatalf("JoinFeatures() = %v features, want %v", len(fc.Features), 5)
+	}
+	want := geojson.Properties{"left_name": 1, "district_name": 1, PropertyDistance: 0.0}
+	if got := fc.Features[2].Properties; !reflect.DeepEqual(got, want) {
+		t.Errorf("JoinFeatures() properties = %v, want %v", got, want)
+	}
+	if got := fc.Features[3].Properties; !reflect.DeepEqual(got, geojson.Properties{"left_name": 2}) {
+		t.Errorf("JoinFeatures() unmatched properties = %v", got)
+	}
+	if fc.Features[2].ID != 1 {
+		t.Errorf("JoinFeatures() id = %v, want %v", fc.Features[2].ID, 1)
+	}
+}
+
+func TestJoin_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		left    *geojson.FeatureCollection
+		opts    Options
+		wantErr error
+	}{
+		{"nil", nil, Options{}, ErrNilFeatureCollection},
+		{"predicate", positions, Options{Predicate: Nearest + 1}, ErrInvalidPredicate},
+		{"distance", positions, Options{Predicate: DWithin, Distance: -1}, ErrInvalidDistance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Join(tt.left, districts, tt.opts); err != tt.wantErr {
+				t.Errorf("Join() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@ package planar
 import (
 	"errors"
 
+	"github.com/spatial-go/geoos/algorithm/buffer"
+	"github.com/spatial-go/geoos/algorithm/operation"
+	"github.com/spatial-go/geoos/algorithm/subdivision"
 	"github.com/spatial-go/geoos/space"
 )
 
@@ -19,10 +22,16 @@ type Algorithm interface {
 
 	Buffer(geom space.Geometry, width float64, quadsegs int) space.Geometry
 
+	BufferWithParams(geom space.Geometry, width float64, params *buffer.CurveParameters) space.Geometry
+
 	BufferInMeter(geom space.Geometry, width float64, quadsegs int) space.Geometry
 
 	Centroid(geom space.Geometry) (space.Geometry, error)
 
+	ConcaveHull(geom space.Geometry, opts subdivision.ConcaveHullOptions) (space.Geometry, error)
+
+	ConcaveHullOfPolygons(geom space.Geometry, opts subdivision.ConcaveHullOptions) (space.Geometry, error)
+
 	Contains(geom1, geom2 space.Geometry) (bool, error)
 
 	ConvexHull(geom space.Geometry) (space.Geometry, error)
@@ -63,16 +72,34 @@ type Algorithm interface {
 
 	IsSimple(geom space.Geometry) (bool, error)
 
+	LargestEmptyCircle(obstacles, boundary space.Geometry, tolerance float64) (*space.Circle, error)
+
 	Length(geom space.Geometry) (float64, error)
 
 	LineMerge(geom space.Geometry) (space.Geometry, error)
 
+	MakeValid(geom space.Geometry, method int) (space.Geometry, error)
+
+	MaximumInscribedCircle(geom space.Geometry, tolerance float64) (*space.Circle, error)
+
+	MinimumAreaRectangle(geom space.Geometry) (space.Geometry, error)
+
+	MinimumBoundingCircle(geom space.Geometry) (*space.Circle, error)
+
+	MinimumDiameter(geom space.Geometry) (space.Geometry, error)
+
+	MinimumWidthRectangle(geom space.Geometry) (space.Geometry, error)
+
 	NGeometry(geom space.Geometry) (int, error)
 
+	OffsetCurve(geom space.Geometry, distance float64, params *buffer.CurveParameters) (space.Geometry, error)
+
 	Overlaps(geom1, geom2 space.Geometry) (bool, error)
 
 	PointOnSurface(geom space.Geometry) (space.Geometry, error)
 
+	Polygonize(geom space.Geometry) (space.Collection, error)
+
 	Relate(s, d space.Geometry) (string, error)
 
 	SharedPaths(geom1, geom2 space.Geometry) (string, error)
@@ -83,6 +110,8 @@ type Algorithm interface {
 
 	Snap(input, reference space.Geometry, tolerance float64) (space.Geometry, error)
 
+	Split(input, blade space.Geometry) (space.Collection, error)
+
 	SymDifference(geom1, geom2 space.Geometry) (space.Geometry, error)
 
 	Touches(geom1, geom2 space.Geometry) (bool, error)
@@ -93,6 +122,8 @@ type Algorithm interface {
 
 	UniquePoints(geom space.Geometry) (space.Geometry, error)
 
+	Validate(geom space.Geometry) ([]operation.ValidationResult, error)
+
 	Within(geom1, geom2 space.Geometry) (bool, error)
 }
 
@@ -1,7 +1,9 @@
 package planar
 
 import (
+	"github.com/spatial-go/geoos/algorithm/operation"
 	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
 	"github.com/spatial-go/geoos/space/topograph"
 )
 
@@ -47,3 +49,24 @@ func (g *megrezAlgorithm) IsRing(geom space.Geometry) (bool, error) {
 func (g *megrezAlgorithm) IsSimple(geom space.Geometry) (bool, error) {
 	return geom.IsSimple(), nil
 }
+
+// Validate returns the invalidities of the geometry, their reasons and locations, none if it is valid.
+// The polygons of a MultiPolygon may touch at points only, the geometries of a Collection are validated separately.
+func (g *megrezAlgorithm) Validate(geom space.Geometry) ([]operation.ValidationResult, error) {
+	if geom == nil {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	if c, ok := geom.(space.Collection); ok {
+		var results []operation.ValidationResult
+		for _, v := range c {
+			r, err := g.Validate(v)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, r...)
+		}
+		return results, nil
+	}
+	vop := &operation.ValidOP{Steric: geom.ToMatrix()}
+	return vop.ValidateAll(), nil
+}
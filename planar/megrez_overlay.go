@@ -71,6 +71,24 @@ func (g *megrezAlgorithm) SharedPaths(geom1, geom2 space.Geometry) (string, erro
 	return wkt.MarshalString(coll), nil
 }
 
+// Split returns a collection of the parts of the input geometry split by the blade geometry.
+// A line is split by points, lines or the boundaries of polygons, a polygon is split by lines or the boundaries of polygons.
+// The collection contains the input geometry itself if the blade does not split it.
+func (g *megrezAlgorithm) Split(input, blade space.Geometry) (space.Collection, error) {
+	if input == nil || blade == nil || input.IsEmpty() || blade.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	result, err := clipping.Split(input.ToMatrix(), blade.ToMatrix())
+	if err != nil {
+		return nil, err
+	}
+	parts := make(space.Collection, 0, len(result))
+	for _, v := range result {
+		parts = append(parts, space.TransGeometry(v))
+	}
+	return parts, nil
+}
+
 // SymDifference returns a geometry that represents the portions of A and B that do not intersect.
 // It is called a symmetric difference because SymDifference(A,B) = SymDifference(B,A).
 // One can think of this as Union(geomA,geomB) - Intersection(A,B).
@@ -123,6 +123,46 @@ func TestAlgorithm_LineMerge(t *testing.T) {
 	}
 }
 
+func TestAlgorithm_Split(t *testing.T) {
+	line, _ := wkt.UnmarshalString(`LINESTRING(0 0, 10 0, 10 10)`)
+	blade, _ := wkt.UnmarshalString(`LINESTRING(5 -5, 5 5, 15 5)`)
+	square, _ := wkt.UnmarshalString(`POLYGON((0 0, 10 0, 10 10, 0 10, 0 0))`)
+	lineParts, _ := wkt.UnmarshalString(`GEOMETRYCOLLECTION(LINESTRING(0 0, 5 0),LINESTRING(5 0, 10 0, 10 5),LINESTRING(10 5, 10 10))`)
+	polyParts, _ := wkt.UnmarshalString(`GEOMETRYCOLLECTION(POLYGON((0 0, 5 0, 5 5, 10 5, 10 10, 0 10, 0 0)),POLYGON((5 0, 10 0, 10 5, 5 5, 5 0)))`)
+
+	type args struct {
+		input space.Geometry
+		blade space.Geometry
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    space.Geometry
+		wantErr bool
+	}{
+		{name: "line by line", args: args{input: line, blade: blade}, want: lineParts},
+		{name: "polygon by line", args: args{input: square, blade: blade}, want: polyParts},
+		{name: "point", args: args{input: space.Point{1, 1}, blade: blade}, wantErr: true},
+		{name: "nil blade", args: args{input: square}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G := NormalStrategy()
+			got, err := G.Split(tt.args.input, tt.args.blade)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Split() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !got.Equals(tt.want) {
+				t.Errorf("Split() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAlgorithm_SymDifference(t *testing.T) {
 	line01, _ := wkt.UnmarshalString(`LINESTRING(50 100, 50 200)`)
 	line02, _ := wkt.UnmarshalString(`LINESTRING(50 50, 50 150)`)
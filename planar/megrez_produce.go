@@ -3,11 +3,15 @@ package planar
 import (
 	"github.com/spatial-go/geoos/algorithm/buffer"
 	"github.com/spatial-go/geoos/algorithm/buffer/simplify"
+	"github.com/spatial-go/geoos/algorithm/calc"
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/algorithm/measure"
 	"github.com/spatial-go/geoos/algorithm/overlay/snap"
+	"github.com/spatial-go/geoos/algorithm/polygonize"
+	"github.com/spatial-go/geoos/algorithm/subdivision"
 	"github.com/spatial-go/geoos/coordtransform"
 	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
 )
 
 // Boundary returns the closure of the combinatorial boundary of this space.Geometry.
@@ -17,15 +21,21 @@ func (g *megrezAlgorithm) Boundary(geom space.Geometry) (space.Geometry, error)
 
 // Buffer Returns a geometry that represents all points whose distance
 // from this space.Geometry is less than or equal to distance.
-func (g *megrezAlgorithm) Buffer(geom space.Geometry, width float64, quadsegs int) (geometry space.Geometry) {
-	buff := buffer.Buffer(geom.ToMatrix(), width, quadsegs)
-	switch b := buff.(type) {
-	case matrix.LineMatrix:
-		return space.LineString(b)
-	case matrix.PolygonMatrix:
-		return space.Polygon(b)
+func (g *megrezAlgorithm) Buffer(geom space.Geometry, width float64, quadsegs int) space.Geometry {
+	if geom == nil {
+		return nil
+	}
+	return space.TransBuffer(buffer.Buffer(geom.ToMatrix(), width, quadsegs))
+}
+
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance,
+// built with the end cap style, join style, mitre limit and single-sided parameters.
+func (g *megrezAlgorithm) BufferWithParams(geom space.Geometry, width float64, params *buffer.CurveParameters) space.Geometry {
+	if geom == nil {
+		return nil
 	}
-	return nil
+	return space.TransBuffer(buffer.BufferWithParams(geom.ToMatrix(), width, params))
 }
 
 // BufferInMeter Returns a geometry that represents all points whose distance
@@ -63,6 +73,44 @@ func (g *megrezAlgorithm) Centroid(geom space.Geometry) (space.Geometry, error)
 	return space.Centroid(geom), nil
 }
 
+// ConcaveHull computes the concave hull of the vertices of a geometry, a polygon which contains them
+// and follows them more tightly than the convex hull, built on their Delaunay triangulation.
+// In the general case the concave hull is a Polygon, a MultiPolygon if it is not required to be a single polygon.
+func (g *megrezAlgorithm) ConcaveHull(geom space.Geometry, opts subdivision.ConcaveHullOptions) (space.Geometry, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	result, err := subdivision.ConcaveHull(matrix.TransMatrixes(geom.ToMatrix()), opts)
+	if err != nil {
+		return nil, err
+	}
+	return space.TransGeometry(result), nil
+}
+
+// ConcaveHullOfPolygons computes the concave hull of a Polygon or a MultiPolygon,
+// which contains the polygons and fills the space between them.
+func (g *megrezAlgorithm) ConcaveHullOfPolygons(geom space.Geometry, opts subdivision.ConcaveHullOptions) (space.Geometry, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	var polygons []matrix.PolygonMatrix
+	switch p := geom.(type) {
+	case space.Polygon:
+		polygons = []matrix.PolygonMatrix{matrix.PolygonMatrix(p)}
+	case space.MultiPolygon:
+		for _, v := range p {
+			polygons = append(polygons, matrix.PolygonMatrix(v))
+		}
+	default:
+		return nil, spaceerr.ErrNotPolygon
+	}
+	result, err := subdivision.ConcaveHullOfPolygons(polygons, opts)
+	if err != nil {
+		return nil, err
+	}
+	return space.TransGeometry(result), nil
+}
+
 // ConvexHull computes the convex hull of a geometry. The convex hull is the smallest convex geometry
 // that encloses all geometries in the input.
 // In the general case the convex hull is a Polygon.
@@ -85,12 +133,169 @@ func (g *megrezAlgorithm) Envelope(geom space.Geometry) (space.Geometry, error)
 	}
 }
 
+// LargestEmptyCircle returns the largest circle which does not overlap the obstacles, points, lines or polygons,
+// with its centre in the boundary, a Polygon or a MultiPolygon, or in the convex hull of the obstacles if boundary is nil.
+// The centre is computed within the tolerance.
+func (g *megrezAlgorithm) LargestEmptyCircle(obstacles, boundary space.Geometry, tolerance float64) (*space.Circle, error) {
+	if obstacles == nil || obstacles.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	var boundaryMatrix matrix.Steric
+	if boundary != nil {
+		if boundary.GeoJSONType() != space.TypePolygon && boundary.GeoJSONType() != space.TypeMultiPolygon {
+			return nil, spaceerr.ErrNotPolygon
+		}
+		boundaryMatrix = boundary.ToMatrix()
+	}
+	centre, radius, err := buffer.LargestEmptyCircle(obstacles.ToMatrix(), boundaryMatrix, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	return newCircle(centre, radius)
+}
+
+// MakeValid repairs an invalid Polygon or MultiPolygon, its self-intersections, bow-ties, ring orientations,
+// duplicate or degenerate rings and overlapping polygons, by the method calc.MakeValidLinework or calc.MakeValidStructure.
+// Returns a Polygon or a MultiPolygon, the linework keeps the collapsed parts as lines or points,
+// so it may return a LineString, a MultiLineString, a Point or a Collection of polygons and lines.
+func (g *megrezAlgorithm) MakeValid(geom space.Geometry, method int) (space.Geometry, error) {
+	if geom == nil {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	if geom.GeoJSONType() != space.TypePolygon && geom.GeoJSONType() != space.TypeMultiPolygon {
+		return nil, spaceerr.ErrNotPolygon
+	}
+	result, err := buffer.MakeValid(geom.ToMatrix(), method)
+	if err != nil {
+		return nil, err
+	}
+	return space.TransGeometry(result), nil
+}
+
+// MaximumInscribedCircle returns the largest circle contained in a Polygon or a MultiPolygon,
+// its centre is the pole of inaccessibility, the interior point farthest from the boundary,
+// which is a better label point than PointOnSurface. The centre is computed within the tolerance.
+func (g *megrezAlgorithm) MaximumInscribedCircle(geom space.Geometry, tolerance float64) (*space.Circle, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	if geom.GeoJSONType() != space.TypePolygon && geom.GeoJSONType() != space.TypeMultiPolygon {
+		return nil, spaceerr.ErrNotPolygon
+	}
+	centre, radius, err := buffer.MaximumInscribedCircle(geom.ToMatrix(), tolerance)
+	if err != nil {
+		return nil, err
+	}
+	return newCircle(centre, radius)
+}
+
+// MinimumAreaRectangle returns the rectangle of minimum area which encloses a geometry, it may be rotated
+// with respect to the coordinate axes and has a side collinear with an edge of the convex hull.
+// The rectangle of collinear geometries is a LineString, the one of identical points is a Point.
+func (g *megrezAlgorithm) MinimumAreaRectangle(geom space.Geometry) (space.Geometry, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	return space.TransGeometry(buffer.MinimumAreaRectangle(geom.ToMatrix())), nil
+}
+
+// MinimumBoundingCircle returns the smallest circle which encloses a geometry.
+// The circle of identical points has a zero radius and an empty polygon.
+func (g *megrezAlgorithm) MinimumBoundingCircle(geom space.Geometry) (*space.Circle, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	return newCircle(buffer.MinimumBoundingCircle(geom.ToMatrix()))
+}
+
+// newCircle returns the circle of the centre and the radius, with an empty polygon for a zero radius.
+func newCircle(centre matrix.Matrix, radius float64) (*space.Circle, error) {
+	if radius == 0 {
+		return &space.Circle{Centre: space.Point(centre), Segments: calc.QuadrantSegments}, nil
+	}
+	return space.CreateCircle(space.Point(centre), radius)
+}
+
+// MinimumDiameter returns the minimum width of a geometry, as the LineString from a vertex
+// of its convex hull perpendicular to the opposite edge, its length is the width.
+func (g *megrezAlgorithm) MinimumDiameter(geom space.Geometry) (space.Geometry, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	return space.LineString(buffer.MinimumDiameter(geom.ToMatrix())), nil
+}
+
+// MinimumWidthRectangle returns the rectangle of minimum width which encloses a geometry,
+// its width is the minimum diameter of the geometry.
+// The rectangle of collinear geometries is a LineString, the one of identical points is a Point.
+func (g *megrezAlgorithm) MinimumWidthRectangle(geom space.Geometry) (space.Geometry, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	return space.TransGeometry(buffer.MinimumWidthRectangle(geom.ToMatrix())), nil
+}
+
+// OffsetCurve Returns the line parallel to a LineString at a distance, on its left side for a positive distance
+// and on its right side for a negative one, with the join style, mitre limit and quadrant segments of the parameters.
+// The loops of the curve on the tight bends of the line are removed.
+// A MultiLineString returns the MultiLineString of the offset curves of its lines.
+func (g *megrezAlgorithm) OffsetCurve(geom space.Geometry, distance float64, params *buffer.CurveParameters) (space.Geometry, error) {
+	if geom == nil {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	switch line := geom.(type) {
+	case space.LineString:
+		curve := buffer.OffsetCurve(matrix.LineMatrix(line), distance, params)
+		if curve == nil {
+			return nil, spaceerr.ErrNilGeometry
+		}
+		return space.LineString(curve), nil
+	case space.MultiLineString:
+		curves := space.MultiLineString{}
+		for _, v := range line {
+			if curve := buffer.OffsetCurve(matrix.LineMatrix(v), distance, params); curve != nil {
+				curves = append(curves, space.LineString(curve))
+			}
+		}
+		if len(curves) == 0 {
+			return nil, spaceerr.ErrNilGeometry
+		}
+		return curves, nil
+	}
+	return nil, spaceerr.ErrNotSupportGeometry
+}
+
 // PointOnSurface Returns a POINT guaranteed to intersect a surface.
 func (g *megrezAlgorithm) PointOnSurface(geom space.Geometry) (space.Geometry, error) {
 	m := buffer.InteriorPoint(geom.ToMatrix())
 	return space.Point(m), nil
 }
 
+// Polygonize forms the polygons enclosed by the lines of a geometry, which are correctly noded.
+// Returns a Collection of the polygons as a MultiPolygon, and of the dangles, the cut edges and the invalid rings
+// as MultiLineStrings, the lines which bound no polygon.
+func (g *megrezAlgorithm) Polygonize(geom space.Geometry) (space.Collection, error) {
+	if geom == nil {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	result, err := polygonize.Polygonize(geom.ToMatrix())
+	if err != nil {
+		return nil, err
+	}
+	polys := space.MultiPolygon{}
+	for _, v := range result.Polygons {
+		polys = append(polys, space.Polygon(v))
+	}
+	lines := func(ls []matrix.LineMatrix) space.MultiLineString {
+		mls := space.MultiLineString{}
+		for _, v := range ls {
+			mls = append(mls, space.LineString(v))
+		}
+		return mls
+	}
+	return space.Collection{polys, lines(result.Dangles), lines(result.CutEdges), lines(result.InvalidRings)}, nil
+}
+
 // Simplify returns a "simplified" version of the given geometry using the Douglas-Peucker algorithm,
 // May not preserve topology
 func (g *megrezAlgorithm) Simplify(geom space.Geometry, tolerance float64) (space.Geometry, error) {
@@ -1,11 +1,16 @@
 package planar
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
+	"github.com/spatial-go/geoos/algorithm/buffer"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/subdivision"
 	"github.com/spatial-go/geoos/geoencoding/wkt"
 	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
 )
 
 func TestAlgorithm_Boundary(t *testing.T) {
@@ -115,6 +120,9 @@ func TestAlgorithm_Buffer(t *testing.T) {
 			t.Log(wkt.MarshalString(gotGeometry))
 		})
 	}
+	if got := NormalStrategy().Buffer(nil, 50, 4); got != nil {
+		t.Errorf("GEOAlgorithm.Buffer() = %v, want nil", got)
+	}
 }
 
 func TestAlgorithm_Centroid(t *testing.T) {
@@ -429,3 +437,272 @@ func TestAlgorithm_BufferInMeter(t *testing.T) {
 		})
 	}
 }
+
+func TestAlgorithm_BufferWithParams(t *testing.T) {
+	line := space.LineString{{0, 0}, {10, 0}, {10, 10}}
+	tests := []struct {
+		name   string
+		g      space.Geometry
+		width  float64
+		params *buffer.CurveParameters
+		want   space.Geometry
+	}{
+		{name: "flat cap", g: line, width: 1,
+			params: &buffer.CurveParameters{EndCapStyle: calc.CapFlat, JoinStyle: calc.JoinBevel},
+			want:   space.Polygon{{{9, 1}, {9, 10}, {11, 10}, {11, 0}, {10, -1}, {0, -1}, {0, 1}, {9, 1}}}},
+		{name: "single-sided", g: line, width: 1,
+			params: &buffer.CurveParameters{IsSingleSided: true},
+			want:   space.Polygon{{{10, 10}, {10, 0}, {0, 0}, {0, 1}, {9, 1}, {9, 10}, {10, 10}}}},
+		{name: "mitre bound", g: space.Bound{Min: space.Point{0, 0}, Max: space.Point{10, 10}}, width: 1,
+			params: &buffer.CurveParameters{JoinStyle: calc.JoinMitre},
+			want:   space.Polygon{{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}, {-1, -1}}}},
+		{name: "square multi point", g: space.MultiPoint{{0, 0}, {10, 0}}, width: 1,
+			params: &buffer.CurveParameters{EndCapStyle: calc.CapSquare},
+			want: space.MultiPolygon{{{{1, -1}, {1, 1}, {-1, 1}, {-1, -1}, {1, -1}}},
+				{{{11, -1}, {11, 1}, {9, 1}, {9, -1}, {11, -1}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G := NormalStrategy()
+			gotGeometry := G.BufferWithParams(tt.g, tt.width, tt.params)
+			if isEqual, _ := G.EqualsExact(gotGeometry, tt.want, 0.000001); !isEqual {
+				t.Errorf("GEOAlgorithm.BufferWithParams() = %v\n, want %v", wkt.MarshalString(gotGeometry), wkt.MarshalString(tt.want))
+			}
+			if gotGeometry = tt.g.BufferWithParams(tt.width, tt.params); !gotGeometry.EqualsExact(tt.want, 0.000001) {
+				t.Errorf("Geometry.BufferWithParams() = %v\n, want %v", wkt.MarshalString(gotGeometry), wkt.MarshalString(tt.want))
+			}
+		})
+	}
+	if got := NormalStrategy().BufferWithParams(nil, 1, &buffer.CurveParameters{}); got != nil {
+		t.Errorf("GEOAlgorithm.BufferWithParams() = %v, want nil", got)
+	}
+}
+
+func TestAlgorithm_OffsetCurve(t *testing.T) {
+	mitre := &buffer.CurveParameters{JoinStyle: calc.JoinMitre}
+	tests := []struct {
+		name     string
+		g        space.Geometry
+		distance float64
+		want     space.Geometry
+		wantErr  error
+	}{
+		{name: "line", g: space.LineString{{0, 0}, {10, 0}, {10, 10}}, distance: -1,
+			want: space.LineString{{0, -1}, {11, -1}, {11, 10}}},
+		{name: "multi line", g: space.MultiLineString{{{0, 0}, {10, 0}}, {{0, 5}, {10, 5}}}, distance: 1,
+			want: space.MultiLineString{{{0, 1}, {10, 1}}, {{0, 6}, {10, 6}}}},
+		{name: "polygon", g: space.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, distance: 1, wantErr: spaceerr.ErrNotSupportGeometry},
+		{name: "degenerate line", g: space.LineString{{0, 0}, {0, 0}}, distance: 1, wantErr: spaceerr.ErrNilGeometry},
+		{name: "degenerate multi line", g: space.MultiLineString{{{0, 0}, {0, 0}}}, distance: 1, wantErr: spaceerr.ErrNilGeometry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G := NormalStrategy()
+			got, err := G.OffsetCurve(tt.g, tt.distance, mitre)
+			if err != tt.wantErr {
+				t.Fatalf("GEOAlgorithm.OffsetCurve() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != nil && !got.EqualsExact(tt.want, 0.000001) {
+				t.Errorf("GEOAlgorithm.OffsetCurve() = %v, want %v", wkt.MarshalString(got), wkt.MarshalString(tt.want))
+			}
+		})
+	}
+}
+
+func TestAlgorithm_ConcaveHull(t *testing.T) {
+	// a U of points.
+	points := space.MultiPoint{}
+	for x := 0; x <= 6; x++ {
+		for y := 0; y <= 6; y++ {
+			if x >= 2 && x <= 4 && y >= 2 {
+				continue
+			}
+			points = append(points, space.Point{float64(x), float64(y)})
+		}
+	}
+	G := NormalStrategy()
+	convex, err := G.ConcaveHull(points, subdivision.ConcaveHullOptions{MaxEdgeLengthRatio: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area, _ := convex.Area(); area != 36 {
+		t.Errorf("GEOAlgorithm.ConcaveHull() area = %v, want %v", area, 36)
+	}
+	concave, _ := G.ConcaveHull(points, subdivision.ConcaveHullOptions{SinglePolygon: true})
+	if area, _ := concave.Area(); concave.GeoJSONType() != space.TypePolygon || area != 16 {
+		t.Errorf("GEOAlgorithm.ConcaveHull() = %v", wkt.MarshalString(concave))
+	}
+
+	polygons := space.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{2, 0}, {3, 0}, {3, 1}, {2, 1}, {2, 0}}},
+	}
+	hull, err := G.ConcaveHullOfPolygons(polygons, subdivision.ConcaveHullOptions{SinglePolygon: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area, _ := hull.Area(); hull.GeoJSONType() != space.TypePolygon || area != 3 {
+		t.Errorf("GEOAlgorithm.ConcaveHullOfPolygons() = %v", wkt.MarshalString(hull))
+	}
+	if _, err := G.ConcaveHullOfPolygons(space.LineString{{0, 0}, {1, 1}}, subdivision.ConcaveHullOptions{}); err != spaceerr.ErrNotPolygon {
+		t.Errorf("GEOAlgorithm.ConcaveHullOfPolygons() error = %v, want %v", err, spaceerr.ErrNotPolygon)
+	}
+}
+
+func TestAlgorithm_MinimumBounding(t *testing.T) {
+	G := NormalStrategy()
+	points := space.MultiPoint{{0, 0}, {4, 0}, {4, 2}, {0, 4}, {1, 1}}
+
+	circle, err := G.MinimumBoundingCircle(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !circle.Centre.EqualsExact(space.Point{2, 2}, 0.000001) || math.Abs(circle.Radius-2*math.Sqrt2) > 0.000001 ||
+		circle.Polygon.IsEmpty() {
+		t.Errorf("GEOAlgorithm.MinimumBoundingCircle() = %v %v", circle.Centre, circle.Radius)
+	}
+	if circle, _ := G.MinimumBoundingCircle(space.MultiPoint{{1, 1}, {1, 1}}); circle.Radius != 0 || !circle.Centre.Equals(space.Point{1, 1}) {
+		t.Errorf("GEOAlgorithm.MinimumBoundingCircle() = %v %v, want a zero radius", circle.Centre, circle.Radius)
+	}
+
+	diameter, _ := G.MinimumDiameter(points)
+	if width, _ := G.Length(diameter); math.Abs(width-8/math.Sqrt(5)) > 0.000001 {
+		t.Errorf("GEOAlgorithm.MinimumDiameter() = %v, want width %v", wkt.MarshalString(diameter), 8/math.Sqrt(5))
+	}
+
+	rectangle, _ := G.MinimumAreaRectangle(points)
+	if !rectangle.EqualsExact(space.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}, 0.000001) {
+		t.Errorf("GEOAlgorithm.MinimumAreaRectangle() = %v", wkt.MarshalString(rectangle))
+	}
+	rectangle, _ = G.MinimumWidthRectangle(points)
+	if area, _ := G.Area(rectangle); math.Abs(area-19.2) > 0.000001 {
+		t.Errorf("GEOAlgorithm.MinimumWidthRectangle() = %v, want area 19.2", wkt.MarshalString(rectangle))
+	}
+	rectangle, _ = G.MinimumAreaRectangle(space.LineString{{0, 0}, {1, 1}, {2, 2}})
+	if rectangle.GeoJSONType() != space.TypeLineString {
+		t.Errorf("GEOAlgorithm.MinimumAreaRectangle() = %v, want a LineString", wkt.MarshalString(rectangle))
+	}
+	if _, err := G.MinimumDiameter(nil); err != spaceerr.ErrNilGeometry {
+		t.Errorf("GEOAlgorithm.MinimumDiameter() error = %v, want %v", err, spaceerr.ErrNilGeometry)
+	}
+}
+
+func TestAlgorithm_MaximumInscribedCircle(t *testing.T) {
+	G := NormalStrategy()
+	polygon := space.Polygon{{{0, 0}, {10, 0}, {10, 4}, {0, 4}, {0, 0}}}
+	circle, err := G.MaximumInscribedCircle(polygon, 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(circle.Centre.Y()-2) > 0.001 || math.Abs(circle.Radius-2) > 0.001 || circle.Polygon.IsEmpty() {
+		t.Errorf("GEOAlgorithm.MaximumInscribedCircle() = %v %v", circle.Centre, circle.Radius)
+	}
+	if inside, _ := G.Within(circle.Centre, polygon); !inside {
+		t.Errorf("GEOAlgorithm.MaximumInscribedCircle() centre %v not within the polygon", circle.Centre)
+	}
+	if _, err := G.MaximumInscribedCircle(space.LineString{{0, 0}, {1, 1}}, 0.001); err != spaceerr.ErrNotPolygon {
+		t.Errorf("GEOAlgorithm.MaximumInscribedCircle() error = %v, want %v", err, spaceerr.ErrNotPolygon)
+	}
+
+	circle, err = G.LargestEmptyCircle(space.MultiPoint{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, nil, 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !circle.Centre.EqualsExact(space.Point{5, 5}, 0.01) || math.Abs(circle.Radius-5*math.Sqrt2) > 0.001 {
+		t.Errorf("GEOAlgorithm.LargestEmptyCircle() = %v %v", circle.Centre, circle.Radius)
+	}
+	circle, _ = G.LargestEmptyCircle(space.Point{1, 1}, polygon, 0.001)
+	if !circle.Centre.EqualsExact(space.Point{10, 4}, 0.01) {
+		t.Errorf("GEOAlgorithm.LargestEmptyCircle() = %v %v, want the far corner of the boundary", circle.Centre, circle.Radius)
+	}
+	if _, err := G.LargestEmptyCircle(space.Point{1, 1}, space.Point{1, 1}, 0.001); err != spaceerr.ErrNotPolygon {
+		t.Errorf("GEOAlgorithm.LargestEmptyCircle() error = %v, want %v", err, spaceerr.ErrNotPolygon)
+	}
+}
+
+func TestAlgorithm_MakeValid(t *testing.T) {
+	overlapping := space.MultiPolygon{{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}, {{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}}
+	tests := []struct {
+		name     string
+		g        space.Geometry
+		method   int
+		wantType string
+		wantArea float64
+		wantErr  error
+	}{
+		{name: "bow-tie", g: space.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}, method: calc.MakeValidLinework,
+			wantType: space.TypeMultiPolygon, wantArea: 50},
+		{name: "overlapping linework", g: overlapping, method: calc.MakeValidLinework, wantType: space.TypeMultiPolygon, wantArea: 150},
+		{name: "overlapping structure", g: overlapping, method: calc.MakeValidStructure, wantType: space.TypePolygon, wantArea: 175},
+		{name: "collapsed", g: space.Polygon{{{0, 0}, {1, 1}, {2, 2}, {0, 0}}}, method: calc.MakeValidLinework, wantType: space.TypeLineString},
+		{name: "spike", g: space.Polygon{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {10, 5}, {10, 10}, {0, 10}, {0, 0}}}, method: calc.MakeValidLinework,
+			wantType: space.TypeCollection, wantArea: 100},
+		{name: "line", g: space.LineString{{0, 0}, {1, 1}}, method: calc.MakeValidLinework, wantErr: spaceerr.ErrNotPolygon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G := NormalStrategy()
+			got, err := G.MakeValid(tt.g, tt.method)
+			if err != tt.wantErr {
+				t.Fatalf("GEOAlgorithm.MakeValid() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.GeoJSONType() != tt.wantType {
+				t.Errorf("GEOAlgorithm.MakeValid() = %v, want a %v", wkt.MarshalString(got), tt.wantType)
+			}
+			if area, _ := got.Area(); math.Abs(area-tt.wantArea) > 0.000001 {
+				t.Errorf("GEOAlgorithm.MakeValid() = %v, want area %v", wkt.MarshalString(got), tt.wantArea)
+			}
+			if tt.wantType == space.TypePolygon || tt.wantType == space.TypeMultiPolygon {
+				if !got.IsValid() {
+					t.Errorf("GEOAlgorithm.MakeValid() = %v is not valid", wkt.MarshalString(got))
+				}
+			}
+		})
+	}
+}
+
+func TestAlgorithm_Polygonize(t *testing.T) {
+	tests := []struct {
+		name         string
+		g            string
+		wantPolygons int
+		wantArea     float64
+		wantDangles  int
+		wantCutEdges int
+		wantErr      error
+	}{
+		{name: "parcels", g: `MULTILINESTRING((0 0,10 0),(10 0,20 0,20 10,10 10),(10 10,0 10,0 0),(10 0,10 10))`,
+			wantPolygons: 2, wantArea: 200},
+		{name: "parcel with hole", g: `MULTILINESTRING((0 0,10 0,10 10,0 10,0 0),(2 2,2 8,8 8,8 2,2 2))`,
+			wantPolygons: 2, wantArea: 100},
+		{name: "dangle and cut edge", g: `MULTILINESTRING((10 10,0 10,0 0,10 0,10 10),(10 10,20 10),(20 10,30 10,30 20),(30 20,20 20,20 10),(30 20,40 30))`,
+			wantPolygons: 2, wantArea: 200, wantDangles: 1, wantCutEdges: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G := NormalStrategy()
+			geom, _ := wkt.UnmarshalString(tt.g)
+			got, err := G.Polygonize(geom)
+			if err != tt.wantErr {
+				t.Fatalf("GEOAlgorithm.Polygonize() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			polys := got[0].(space.MultiPolygon)
+			area, _ := polys.Area()
+			if len(polys) != tt.wantPolygons || math.Abs(area-tt.wantArea) > 0.000001 {
+				t.Errorf("GEOAlgorithm.Polygonize() = %v, want %v polygons of area %v", wkt.MarshalString(polys), tt.wantPolygons, tt.wantArea)
+			}
+			if len(got[1].(space.MultiLineString)) != tt.wantDangles || len(got[2].(space.MultiLineString)) != tt.wantCutEdges {
+				t.Errorf("GEOAlgorithm.Polygonize() = %v, want %v dangles and %v cut edges", wkt.MarshalString(got), tt.wantDangles, tt.wantCutEdges)
+			}
+		})
+	}
+	if _, err := NormalStrategy().Polygonize(nil); err != spaceerr.ErrNilGeometry {
+		t.Errorf("GEOAlgorithm.Polygonize() error = %v, want %v", err, spaceerr.ErrNilGeometry)
+	}
+}
@@ -1,8 +1,11 @@
 package planar
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/operation"
 	"github.com/spatial-go/geoos/geoencoding/wkt"
 	"github.com/spatial-go/geoos/space"
 )
@@ -201,3 +204,35 @@ func TestAlgorithm_IsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestAlgorithm_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		g       string
+		want    []operation.ValidationResult
+		wantErr bool
+	}{
+		{name: "valid polygon", g: `POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,2 4,4 4,4 2,2 2))`},
+		{name: "bow-tie", g: `POLYGON((0 0,10 10,10 0,0 10,0 0))`,
+			want: []operation.ValidationResult{{Reason: operation.SelfIntersection, Location: matrix.Matrix{5, 5}}}},
+		{name: "overlapping multipolygon", g: `MULTIPOLYGON(((0 0,10 0,10 10,0 10,0 0)),((5 5,15 5,15 15,5 15,5 5)))`,
+			want: []operation.ValidationResult{{Reason: operation.SelfIntersection, Location: matrix.Matrix{10, 5}}}},
+		{name: "overlapping collection", g: `GEOMETRYCOLLECTION(POLYGON((0 0,10 0,10 10,0 10,0 0)),POLYGON((5 5,15 5,15 15,5 15,5 5)))`},
+		{name: "hole outside shell", g: `POLYGON((0 0,10 0,10 10,0 10,0 0),(20 20,20 22,22 22,22 20,20 20))`,
+			want: []operation.ValidationResult{{Reason: operation.HoleOutsideShell, Location: matrix.Matrix{20, 20}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			G := NormalStrategy()
+			geom, _ := wkt.UnmarshalString(tt.g)
+			got, err := G.Validate(geom)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
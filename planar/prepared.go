@@ -0,0 +1,390 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/spatial-go/geoos/algorithm/buffer"
+	"github.com/spatial-go/geoos/algorithm/calc"
+	"github.com/spatial-go/geoos/algorithm/matrix"
+	"github.com/spatial-go/geoos/algorithm/matrix/envelope"
+	"github.com/spatial-go/geoos/algorithm/relate"
+	"github.com/spatial-go/geoos/index/strtree"
+	"github.com/spatial-go/geoos/space"
+	"github.com/spatial-go/geoos/space/spaceerr"
+)
+
+// PreparedGeometry A geometry prepared for the repeated evaluation of spatial predicates against other geometries,
+// e.g. testing a large number of points against the same polygon.
+// Preparing caches an index of the segments of the geometry,
+// and a relate.IndexedPointInAreaLocator which indexes the edges of the rings by their y-intervals.
+// Predicates which cannot be answered from the indexes fall back to the normal algorithm.
+// A PreparedGeometry is not modified by the predicates, it is safe for concurrent use.
+type PreparedGeometry struct {
+	geom     space.Geometry
+	env      *envelope.Envelope
+	parts    *components
+	segments *strtree.STRtree
+	locator  *relate.IndexedPointInAreaLocator
+	strategy Algorithm
+}
+
+// Prepare Returns the geometry prepared for the repeated evaluation of spatial predicates.
+func Prepare(geom space.Geometry) (*PreparedGeometry, error) {
+	if geom == nil || geom.IsEmpty() {
+		return nil, spaceerr.ErrNilGeometry
+	}
+	p := &PreparedGeometry{
+		geom:     geom,
+		env:      boundEnvelope(geom.Bound()),
+		parts:    decompose(geom),
+		segments: strtree.NewSTRtree(),
+		strategy: NormalStrategy(),
+	}
+	for _, seg := range p.parts.segments() {
+		if err := p.segments.Insert(envelope.TwoMatrix(seg.P0, seg.P1), seg); err != nil {
+			return nil, err
+		}
+	}
+	// built now, so queries never modify the tree.
+	p.segments.Build()
+	if len(p.parts.polygons) > 0 {
+		polygons := matrix.Collection{}
+		for _, poly := range p.parts.polygons {
+			polygons = append(polygons, poly)
+		}
+		locator, err := relate.NewIndexedPointInAreaLocator(polygons, 0)
+		if err != nil {
+			return nil, err
+		}
+		p.locator = locator
+	}
+	return p, nil
+}
+
+// Geometry Returns the geometry which was prepared.
+func (p *PreparedGeometry) Geometry() space.Geometry {
+	return p.geom
+}
+
+// Contains Returns TRUE if no points of the geometry lie in the exterior of the prepared geometry,
+// and at least one point of the interior of the geometry lies in the interior of the prepared geometry.
+func (p *PreparedGeometry) Contains(geom space.Geometry) (bool, error) {
+	test, env, err := testComponents(geom)
+	if err != nil || test.dimension() < 0 || !p.env.Covers(env) {
+		return false, err
+	}
+	if !p.parts.isPolygonal() {
+		return p.strategy.Contains(p.geom, geom)
+	}
+	if ok, decided := p.polygonalCovers(test, true); decided {
+		return ok, nil
+	}
+	return p.strategy.Contains(p.geom, geom)
+}
+
+// Covers Returns TRUE if no point of the geometry is outside the prepared geometry.
+func (p *PreparedGeometry) Covers(geom space.Geometry) (bool, error) {
+	test, env, err := testComponents(geom)
+	if err != nil || test.dimension() < 0 || !p.env.Covers(env) {
+		return false, err
+	}
+	if !p.parts.isPolygonal() {
+		return p.strategy.Covers(p.geom, geom)
+	}
+	if ok, decided := p.polygonalCovers(test, false); decided {
+		return ok, nil
+	}
+	return p.strategy.Covers(p.geom, geom)
+}
+
+// Intersects Returns TRUE if the prepared geometry and the geometry share any portion of space.
+func (p *PreparedGeometry) Intersects(geom space.Geometry) (bool, error) {
+	test, env, err := testComponents(geom)
+	if err != nil || test.dimension() < 0 || !p.env.IsIntersects(env) {
+		return false, err
+	}
+	if p.locator != nil {
+		for _, v := range test.representatives() {
+			if p.locator.Locate(v) != calc.ImExterior {
+				return true, nil
+			}
+		}
+		if p.parts.isPolygonal() && test.isPuntal() {
+			return false, nil
+		}
+	}
+	if p.intersectsSegments(test) {
+		return true, nil
+	}
+	if len(test.polygons) > 0 {
+		rings := test.rings()
+		for _, v := range p.parts.representatives() {
+			if relate.LocateInRings(v, rings...) != calc.ImExterior {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Disjoint Returns TRUE if the prepared geometry and the geometry do not share any portion of space.
+func (p *PreparedGeometry) Disjoint(geom space.Geometry) (bool, error) {
+	ok, err := p.Intersects(geom)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
+// Within Returns TRUE if the prepared geometry is completely inside the geometry.
+func (p *PreparedGeometry) Within(geom space.Geometry) (bool, error) {
+	test, env, err := testComponents(geom)
+	if err != nil || test.dimension() < p.parts.dimension() || !env.Covers(p.env) {
+		return false, err
+	}
+	if p.parts.isPuntal() && test.isPolygonal() {
+		rings, interior := test.rings(), false
+		for _, v := range p.parts.points {
+			switch relate.LocateInRings(v, rings...) {
+			case calc.ImExterior:
+				return false, nil
+			case calc.ImInterior:
+				interior = true
+			}
+		}
+		return interior, nil
+	}
+	if test.isPolygonal() {
+		if ok, decided := p.withinPolygonal(test); decided {
+			return ok, nil
+		}
+	}
+	return p.strategy.Within(p.geom, geom)
+}
+
+// polygonalCovers tests whether the polygonal prepared geometry covers, or contains if proper, the test components.
+// It is not decided if the test geometry touches the boundary of the prepared geometry,
+// which needs the full topology.
+func (p *PreparedGeometry) polygonalCovers(test *components, proper bool) (ok, decided bool) {
+	if test.isPuntal() {
+		interior := false
+		for _, v := range test.points {
+			switch p.locator.Locate(v) {
+			case calc.ImExterior:
+				return false, true
+			case calc.ImInterior:
+				interior = true
+			}
+		}
+		return interior || !proper, true
+	}
+	if p.intersectsSegments(test) {
+		return false, false
+	}
+	// the test geometry does not touch the boundary,
+	// so each of its components lies either in the interior or in the exterior.
+	for _, v := range test.representatives() {
+		if p.locator.Locate(v) == calc.ImExterior {
+			return false, true
+		}
+	}
+	// a ring inside a test area, e.g. a hole, has exterior points next to it.
+	if len(test.polygons) > 0 {
+		rings := test.rings()
+		for _, ring := range p.parts.rings() {
+			if relate.LocateInRings(ring[0], rings...) != calc.ImExterior {
+				return false, true
+			}
+		}
+	}
+	return true, true
+}
+
+// withinPolygonal tests whether the prepared geometry is within the polygonal test components.
+// It is not decided if the prepared geometry touches the boundary of the test geometry.
+func (p *PreparedGeometry) withinPolygonal(test *components) (ok, decided bool) {
+	if p.intersectsSegments(test) {
+		return false, false
+	}
+	rings := test.rings()
+	for _, v := range p.parts.representatives() {
+		if relate.LocateInRings(v, rings...) != calc.ImInterior {
+			return false, true
+		}
+	}
+	if p.locator != nil {
+		for _, ring := range rings {
+			if p.locator.Locate(ring[0]) != calc.ImExterior {
+				return false, true
+			}
+		}
+	}
+	return true, true
+}
+
+// intersectsSegments tests whether a segment of the test components intersects an indexed segment.
+func (p *PreparedGeometry) intersectsSegments(test *components) bool {
+	for _, seg := range test.segments() {
+		found := false
+		visitor := &segmentVisitor{fn: func(other *matrix.LineSegment) bool {
+			found = segmentsIntersect(seg.P0, seg.P1, other.P0, other.P1)
+			return !found
+		}}
+		_ = p.segments.QueryVisitor(envelope.TwoMatrix(seg.P0, seg.P1), visitor)
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentVisitor calls fn for the visited segments until it returns false.
+type segmentVisitor struct {
+	fn      func(seg *matrix.LineSegment) bool
+	stopped bool
+}
+
+// VisitItem visits an item.
+func (s *segmentVisitor) VisitItem(item interface{}) {
+	if !s.stopped {
+		s.stopped = !s.fn(item.(*matrix.LineSegment))
+	}
+}
+
+// Items returns nil, the segments are not collected.
+func (s *segmentVisitor) Items() interface{} {
+	return nil
+}
+
+// components the points, lines and polygons of a geometry.
+type components struct {
+	points   []matrix.Matrix
+	lines    []matrix.LineMatrix
+	polygons []matrix.PolygonMatrix
+}
+
+// testComponents returns the components and the envelope of a geometry tested against a prepared geometry.
+func testComponents(geom space.Geometry) (*components, *envelope.Envelope, error) {
+	if geom == nil {
+		return nil, nil, spaceerr.ErrNilGeometry
+	}
+	if geom.IsEmpty() {
+		return &components{}, nil, nil
+	}
+	return decompose(geom), boundEnvelope(geom.Bound()), nil
+}
+
+func decompose(geom space.Geometry) *components {
+	c := &components{}
+	c.add(geom.ToMatrix())
+	return c
+}
+
+func (c *components) add(steric matrix.Steric) {
+	switch m := steric.(type) {
+	case matrix.Matrix:
+		c.points = append(c.points, m)
+	case matrix.LineMatrix:
+		if len(m) > 0 {
+			c.lines = append(c.lines, m)
+		}
+	case matrix.PolygonMatrix:
+		if len(m) > 0 && len(m[0]) > 0 {
+			c.polygons = append(c.polygons, m)
+		}
+	case matrix.MultiPolygonMatrix:
+		for _, v := range m {
+			c.add(matrix.PolygonMatrix(v))
+		}
+	case matrix.Collection:
+		for _, v := range m {
+			c.add(v)
+		}
+	}
+}
+
+// dimension returns the largest dimension of the components, -1 if empty.
+func (c *components) dimension() int {
+	switch {
+	case len(c.polygons) > 0:
+		return 2
+	case len(c.lines) > 0:
+		return 1
+	case len(c.points) > 0:
+		return 0
+	}
+	return -1
+}
+
+func (c *components) isPuntal() bool {
+	return len(c.points) > 0 && len(c.lines) == 0 && len(c.polygons) == 0
+}
+
+func (c *components) isPolygonal() bool {
+	return len(c.polygons) > 0 && len(c.lines) == 0 && len(c.points) == 0
+}
+
+// rings returns the shells and holes of the polygons.
+func (c *components) rings() []matrix.LineMatrix {
+	rings := []matrix.LineMatrix{}
+	for _, poly := range c.polygons {
+		for _, ring := range poly {
+			if len(ring) > 0 {
+				rings = append(rings, ring)
+			}
+		}
+	}
+	return rings
+}
+
+// linework returns the lines and the rings.
+func (c *components) linework() []matrix.LineMatrix {
+	return append(append([]matrix.LineMatrix{}, c.lines...), c.rings()...)
+}
+
+// segments returns the segments of the lines and the rings, points as segments of length zero.
+func (c *components) segments() []*matrix.LineSegment {
+	segs := []*matrix.LineSegment{}
+	for _, v := range c.points {
+		segs = append(segs, &matrix.LineSegment{P0: v, P1: v})
+	}
+	for _, line := range c.linework() {
+		if len(line) == 1 {
+			segs = append(segs, &matrix.LineSegment{P0: line[0], P1: line[0]})
+		}
+		for i := 1; i < len(line); i++ {
+			segs = append(segs, &matrix.LineSegment{P0: line[i-1], P1: line[i]})
+		}
+	}
+	return segs
+}
+
+// representatives returns the points and a vertex of each line and ring.
+func (c *components) representatives() []matrix.Matrix {
+	reps := append([]matrix.Matrix{}, c.points...)
+	for _, line := range c.linework() {
+		reps = append(reps, line[0])
+	}
+	return reps
+}
+
+// segmentsIntersect tests whether the segments p1-p2 and q1-q2 have any point in common.
+func segmentsIntersect(p1, p2, q1, q2 matrix.Matrix) bool {
+	o1, o2 := buffer.OrientationIndex(p1, p2, q1), buffer.OrientationIndex(p1, p2, q2)
+	o3, o4 := buffer.OrientationIndex(q1, q2, p1), buffer.OrientationIndex(q1, q2, p2)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return (o1 == 0 && inSegmentBound(q1, p1, p2)) || (o2 == 0 && inSegmentBound(q2, p1, p2)) ||
+		(o3 == 0 && inSegmentBound(p1, q1, q2)) || (o4 == 0 && inSegmentBound(p2, q1, q2))
+}
+
+// inSegmentBound tests whether p lies in the bound of the segment a-b.
+func inSegmentBound(p, a, b matrix.Matrix) bool {
+	return p[0] >= math.Min(a[0], b[0]) && p[0] <= math.Max(a[0], b[0]) &&
+		p[1] >= math.Min(a[1], b[1]) && p[1] <= math.Max(a[1], b[1])
+}
+
+func boundEnvelope(bound space.Bound) *envelope.Envelope {
+	return envelope.TwoMatrix(matrix.Matrix(bound.Min), matrix.Matrix(bound.Max))
+}
@@ -0,0 +1,137 @@
+package planar
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/spatial-go/geoos/space"
+)
+
+// preparedPolygon two squares, the first has a hole.
+var preparedPolygon = space.MultiPolygon{
+	{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}},
+	{{{20, 0}, {30, 0}, {30, 10}, {20, 10}, {20, 0}}},
+}
+
+func TestPreparedGeometry_Polygon(t *testing.T) {
+	p, err := Prepare(preparedPolygon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                         string
+		geom                         space.Geometry
+		contains, covers, intersects bool
+	}{
+		{"interior point", space.Point{2, 2}, true, true, true},
+		{"point in hole", space.Point{5, 5}, false, false, false},
+		{"point on shell", space.Point{0, 5}, false, true, true},
+		{"point on hole", space.Point{4, 5}, false, true, true},
+		{"point on vertex", space.Point{10, 10}, false, true, true},
+		{"point between", space.Point{15, 5}, false, false, false},
+		{"point in second", space.Point{25, 5}, true, true, true},
+		{"multipoint touching", space.MultiPoint{{2, 2}, {0, 5}}, true, true, true},
+		{"multipoint on boundary", space.MultiPoint{{0, 5}, {10, 5}}, false, true, true},
+		{"line inside", space.LineString{{1, 1}, {3, 3}}, true, true, true},
+		{"line into hole", space.LineString{{1, 1}, {5, 5}}, false, false, true},
+		{"line crossing", space.LineString{{-5, 5}, {15, 5}}, false, false, true},
+		{"line between", space.LineString{{12, 1}, {18, 1}}, false, false, false},
+		{"polygon inside", space.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}, true, true, true},
+		{"polygon around hole", space.Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}}, false, false, true},
+		{"polygon in hole", space.Polygon{{{4.5, 4.5}, {5.5, 4.5}, {5.5, 5.5}, {4.5, 5.5}, {4.5, 4.5}}}, false, false, false},
+		{"polygon around all", space.Polygon{{{-1, -1}, {31, -1}, {31, 11}, {-1, 11}, {-1, -1}}}, false, false, true},
+		{"collection", space.Collection{space.Point{2, 2}, space.LineString{{21, 1}, {29, 9}}}, true, true, true},
+		{"empty", space.LineString{}, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := p.Contains(tt.geom); err != nil || got != tt.contains {
+				t.Errorf("PreparedGeometry.Contains() = %v, %v, want %v", got, err, tt.contains)
+			}
+			if got, err := p.Covers(tt.geom); err != nil || got != tt.covers {
+				t.Errorf("PreparedGeometry.Covers() = %v, %v, want %v", got, err, tt.covers)
+			}
+			if got, err := p.Intersects(tt.geom); err != nil || got != tt.intersects {
+				t.Errorf("PreparedGeometry.Intersects() = %v, %v, want %v", got, err, tt.intersects)
+			}
+			if got, err := p.Disjoint(tt.geom); err != nil || got == tt.intersects {
+				t.Errorf("PreparedGeometry.Disjoint() = %v, %v, want %v", got, err, !tt.intersects)
+			}
+		})
+	}
+	if _, err := p.Contains(nil); err == nil {
+		t.Errorf("PreparedGeometry.Contains(nil) error = nil")
+	}
+	if _, err := Prepare(space.Polygon{}); err == nil {
+		t.Errorf("Prepare(empty) error = nil")
+	}
+}
+
+func TestPreparedGeometry_Points(t *testing.T) {
+	G := NormalStrategy()
+	polygon := space.Polygon{{{0, 0}, {10, 0}, {10, 10}, {5, 4}, {0, 10}, {0, 0}}, {{2, 1}, {4, 1}, {4, 3}, {2, 3}, {2, 1}}}
+	p, _ := Prepare(polygon)
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 500; i++ {
+		point := space.Point{r.Float64()*12 - 1, r.Float64()*12 - 1}
+		want, _ := G.Contains(polygon, point)
+		if got, _ := p.Contains(point); got != want {
+			t.Errorf("PreparedGeometry.Contains(%v) = %v, want %v", point, got, want)
+		}
+		want, _ = G.Intersects(polygon, point)
+		if got, _ := p.Intersects(point); got != want {
+			t.Errorf("PreparedGeometry.Intersects(%v) = %v, want %v", point, got, want)
+		}
+	}
+}
+
+func TestPreparedGeometry_Lineal(t *testing.T) {
+	p, _ := Prepare(space.LineString{{0, 0}, {10, 10}, {20, 0}})
+	tests := []struct {
+		name       string
+		geom       space.Geometry
+		intersects bool
+	}{
+		{"point on line", space.Point{5, 5}, true},
+		{"point off line", space.Point{5, 6}, false},
+		{"crossing line", space.LineString{{0, 5}, {20, 5}}, true},
+		{"touching line", space.LineString{{10, 10}, {10, 20}}, true},
+		{"parallel line", space.LineString{{0, 1}, {9, 10}}, false},
+		{"polygon around", space.Polygon{{{-1, -1}, {21, -1}, {21, 11}, {-1, 11}, {-1, -1}}}, true},
+		{"polygon under", space.Polygon{{{8, 0}, {12, 0}, {10, 8}, {8, 0}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := p.Intersects(tt.geom); err != nil || got != tt.intersects {
+				t.Errorf("PreparedGeometry.Intersects() = %v, %v, want %v", got, err, tt.intersects)
+			}
+		})
+	}
+}
+
+func TestPreparedGeometry_Within(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepared space.Geometry
+		geom     space.Geometry
+		want     bool
+	}{
+		{"point in polygon", space.Point{2, 2}, preparedPolygon, true},
+		{"point on boundary", space.Point{0, 2}, preparedPolygon, false},
+		{"points in polygon", space.MultiPoint{{0, 2}, {2, 2}}, preparedPolygon, true},
+		{"point in hole", space.Point{5, 5}, preparedPolygon, false},
+		{"polygon in point", preparedPolygon, space.Point{5, 5}, false},
+		{"line in polygon", space.LineString{{1, 1}, {3, 3}}, preparedPolygon, true},
+		{"line across hole", space.LineString{{1, 1}, {9, 9}}, preparedPolygon, false},
+		{"polygon in polygon", space.Polygon{{{21, 1}, {29, 1}, {29, 9}, {21, 9}, {21, 1}}}, preparedPolygon, true},
+		{"polygon around hole", space.Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}}, preparedPolygon, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := Prepare(tt.prepared)
+			if got, err := p.Within(tt.geom); err != nil || got != tt.want {
+				t.Errorf("PreparedGeometry.Within() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package space
 import (
 	"math"
 
+	"github.com/spatial-go/geoos/algorithm/buffer"
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/space/spaceerr"
 )
@@ -260,6 +261,12 @@ func (b Bound) Buffer(width float64, quadsegs int) Geometry {
 	return b.ToPolygon().Buffer(width, quadsegs)
 }
 
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance, built with the buffer parameters.
+func (b Bound) BufferWithParams(width float64, params *buffer.CurveParameters) Geometry {
+	return b.ToPolygon().BufferWithParams(width, params)
+}
+
 // BufferInMeter Returns a geometry that represents all points whose distance
 // from this space.Geometry is less than or equal to distance.
 func (b Bound) BufferInMeter(width float64, quadsegs int) Geometry {
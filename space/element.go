@@ -162,6 +162,19 @@ func TransGeometry(inputGeom matrix.Steric) Geometry {
 	}
 }
 
+// TransBuffer trans the steric of a buffer result to geometry, nil if it is not a line, a polygon or a collection.
+func TransBuffer(buff matrix.Steric) Geometry {
+	switch b := buff.(type) {
+	case matrix.LineMatrix:
+		return LineString(b)
+	case matrix.PolygonMatrix:
+		return Polygon(b)
+	case matrix.Collection:
+		return TransGeometry(b)
+	}
+	return nil
+}
+
 // BufferInMeter ...
 func BufferInMeter(geometry Geometry, width float64, quadsegs int) Geometry {
 	centroid := geometry.Centroid()
@@ -1,6 +1,7 @@
 package space
 
 import (
+	"github.com/spatial-go/geoos/algorithm/buffer"
 	"github.com/spatial-go/geoos/algorithm/matrix"
 )
 
@@ -56,6 +57,11 @@ type Geometry interface {
 	// from this space.Geometry is less than or equal to distance.
 	Buffer(width float64, quadsegs int) Geometry
 
+	// BufferWithParams Returns a geometry that represents all points whose distance
+	// from this space.Geometry is less than or equal to distance,
+	// built with the end cap style, join style, mitre limit and single-sided parameters.
+	BufferWithParams(width float64, params *buffer.CurveParameters) Geometry
+
 	// BufferInMeter Returns a geometry that represents all points whose distance
 	// from this space.Geometry is less than or equal to distance.
 	BufferInMeter(width float64, quadsegs int) Geometry
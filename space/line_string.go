@@ -197,14 +197,13 @@ func (ls LineString) SimplifyP(tolerance float64) Geometry {
 // Buffer Returns a geometry that represents all points whose distance
 // from this space.Geometry is less than or equal to distance.
 func (ls LineString) Buffer(width float64, quadsegs int) Geometry {
-	buff := buffer.Buffer(ls.ToMatrix(), width, quadsegs)
-	switch b := buff.(type) {
-	case matrix.LineMatrix:
-		return LineString(b)
-	case matrix.PolygonMatrix:
-		return Polygon(b)
-	}
-	return nil
+	return TransBuffer(buffer.Buffer(ls.ToMatrix(), width, quadsegs))
+}
+
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance, built with the buffer parameters.
+func (ls LineString) BufferWithParams(width float64, params *buffer.CurveParameters) Geometry {
+	return TransBuffer(buffer.BufferWithParams(ls.ToMatrix(), width, params))
 }
 
 // BufferInMeter Returns a geometry that represents all points whose distance
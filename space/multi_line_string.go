@@ -208,14 +208,13 @@ func (mls MultiLineString) SimplifyP(tolerance float64) Geometry {
 // Buffer Returns a geometry that represents all points whose distance
 // from this space.Geometry is less than or equal to distance.
 func (mls MultiLineString) Buffer(width float64, quadsegs int) Geometry {
-	buff := buffer.Buffer(mls.ToMatrix(), width, quadsegs)
-	switch b := buff.(type) {
-	case matrix.LineMatrix:
-		return LineString(b)
-	case matrix.PolygonMatrix:
-		return Polygon(b)
-	}
-	return nil
+	return TransBuffer(buffer.Buffer(mls.ToMatrix(), width, quadsegs))
+}
+
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance, built with the buffer parameters.
+func (mls MultiLineString) BufferWithParams(width float64, params *buffer.CurveParameters) Geometry {
+	return TransBuffer(buffer.BufferWithParams(mls.ToMatrix(), width, params))
 }
 
 // BufferInMeter Returns a geometry that represents all points whose distance
@@ -165,14 +165,13 @@ func (mp MultiPoint) SimplifyP(tolerance float64) Geometry {
 // Buffer Returns a geometry that represents all points whose distance
 // from this space.Geometry is less than or equal to distance.
 func (mp MultiPoint) Buffer(width float64, quadsegs int) Geometry {
-	buff := buffer.Buffer(mp.ToMatrix(), width, quadsegs)
-	switch b := buff.(type) {
-	case matrix.LineMatrix:
-		return LineString(b)
-	case matrix.PolygonMatrix:
-		return Polygon(b)
-	}
-	return nil
+	return TransBuffer(buffer.Buffer(mp.ToMatrix(), width, quadsegs))
+}
+
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance, built with the buffer parameters.
+func (mp MultiPoint) BufferWithParams(width float64, params *buffer.CurveParameters) Geometry {
+	return TransBuffer(buffer.BufferWithParams(mp.ToMatrix(), width, params))
 }
 
 // BufferInMeter Returns a geometry that represents all points whose distance
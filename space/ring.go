@@ -1,6 +1,7 @@
 package space
 
 import (
+	"github.com/spatial-go/geoos/algorithm/buffer"
 	"github.com/spatial-go/geoos/algorithm/buffer/simplify"
 	"github.com/spatial-go/geoos/algorithm/matrix"
 	"github.com/spatial-go/geoos/algorithm/measure"
@@ -139,6 +140,12 @@ func (r Ring) Buffer(width float64, quadsegs int) Geometry {
 	return LineString(r).Buffer(width, quadsegs)
 }
 
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance, built with the buffer parameters.
+func (r Ring) BufferWithParams(width float64, params *buffer.CurveParameters) Geometry {
+	return LineString(r).BufferWithParams(width, params)
+}
+
 // BufferInMeter Returns a geometry that represents all points whose distance
 // from this space.Geometry is less than or equal to distance.
 func (r Ring) BufferInMeter(width float64, quadsegs int) Geometry {
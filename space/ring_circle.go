@@ -3,6 +3,7 @@ package space
 import (
 	"math"
 
+	"github.com/spatial-go/geoos/algorithm/buffer"
 	"github.com/spatial-go/geoos/algorithm/calc"
 )
 
@@ -86,6 +87,12 @@ func (c *Circle) Buffer(width float64, quadsegs int) Geometry {
 	return c.Centre.Buffer(width+c.Radius, quadsegs)
 }
 
+// BufferWithParams Returns a geometry that represents all points whose distance
+// from this space.Geometry is less than or equal to distance, built with the buffer parameters.
+func (c *Circle) BufferWithParams(width float64, params *buffer.CurveParameters) Geometry {
+	return c.Centre.BufferWithParams(width+c.Radius, params)
+}
+
 // Envelope returns the  minimum bounding box for the supplied geometry, as a geometry.
 // The polygon is defined by the corner points of the bounding box
 // ((MINX, MINY), (MINX, MAXY), (MAXX, MAXY), (MAXX, MINY), (MINX, MINY)).